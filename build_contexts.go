@@ -0,0 +1,170 @@
+package testcontainers
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/pkg/archive"
+)
+
+const (
+	// dockerImageBuildContextPrefix is the prefix used to identify an additional build context
+	// that is backed by an image reference, the same as "docker buildx build --build-context".
+	dockerImageBuildContextPrefix = "docker-image://"
+
+	// additionalBuildContextsDir is the directory inside the build context archive
+	// where the local additional build contexts are stored.
+	additionalBuildContextsDir = ".testcontainers-build-contexts"
+)
+
+// withAdditionalBuildContexts returns a new build context archive including the additional build contexts.
+// The Docker API does not support named build contexts, so they are emulated defining a stage for each
+// of them, which is prepended to the stages of the Dockerfile:
+//   - local directories are copied into the archive, and the stage copies them into a scratch image.
+//   - image references are used as the base image of the stage.
+//
+// In both cases, the Dockerfile can consume them by name, as in "COPY --from=name" or "FROM name".
+func withAdditionalBuildContexts(buildContext io.Reader, dockerfile string, buildContexts map[string]string) (io.Reader, error) {
+	names := make([]string, 0, len(buildContexts))
+	for name := range buildContexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stages := &strings.Builder{}
+	for _, name := range names {
+		value := buildContexts[name]
+		if strings.HasPrefix(value, dockerImageBuildContextPrefix) {
+			fmt.Fprintf(stages, "FROM %s AS %s\n", strings.TrimPrefix(value, dockerImageBuildContextPrefix), name)
+			continue
+		}
+
+		fmt.Fprintf(stages, "FROM scratch AS %s\nCOPY %s/%s/ /\n", name, additionalBuildContextsDir, name)
+	}
+
+	buffer := &bytes.Buffer{}
+	tw := tar.NewWriter(buffer)
+
+	dockerfileName := path.Clean(filepath.ToSlash(dockerfile))
+
+	tr := tar.NewReader(buildContext)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading build context: %w", err)
+		}
+
+		if path.Clean(hdr.Name) != dockerfileName {
+			if err := tw.WriteHeader(hdr); err != nil {
+				return nil, fmt.Errorf("error writing header: %w", err)
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				return nil, fmt.Errorf("error writing build context: %w", err)
+			}
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("error reading Dockerfile: %w", err)
+		}
+
+		content = insertBeforeFirstStage(content, stages.String())
+
+		hdr.Size = int64(len(content))
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("error writing header: %w", err)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, fmt.Errorf("error writing Dockerfile: %w", err)
+		}
+	}
+
+	for _, name := range names {
+		value := buildContexts[name]
+		if strings.HasPrefix(value, dockerImageBuildContextPrefix) {
+			continue
+		}
+
+		if err := appendDirToTar(tw, value, path.Join(additionalBuildContextsDir, name)); err != nil {
+			return nil, fmt.Errorf("error adding build context %s: %w", name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("error closing tar file: %w", err)
+	}
+
+	return buffer, nil
+}
+
+// appendDirToTar writes the content of the directory into the tar writer, under the given prefix.
+func appendDirToTar(tw *tar.Writer, dir string, prefix string) error {
+	rc, err := archive.TarWithOptions(dir, &archive.TarOptions{})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Join(prefix, hdr.Name)
+		if hdr.Typeflag == tar.TypeDir {
+			name += "/"
+		}
+		hdr.Name = name
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// insertBeforeFirstStage inserts the given stages right before the first FROM instruction
+// of the Dockerfile, so parser directives and global build args are preserved.
+func insertBeforeFirstStage(dockerfile []byte, stages string) []byte {
+	var lines []string
+
+	inserted := false
+	scanner := bufio.NewScanner(bytes.NewReader(dockerfile))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !inserted {
+			fields := strings.Fields(line)
+			if len(fields) > 0 && strings.EqualFold(fields[0], "FROM") {
+				lines = append(lines, strings.TrimSuffix(stages, "\n"))
+				inserted = true
+			}
+		}
+
+		lines = append(lines, line)
+	}
+
+	if !inserted {
+		lines = append(lines, strings.TrimSuffix(stages, "\n"))
+	}
+
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,149 @@
+package testcontainers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/moby/term"
+)
+
+// BuildEvent is a step of the build of an image, decoded from the progress reported by the Docker engine.
+type BuildEvent struct {
+	Step       int      // the number of the step, starting at 1
+	TotalSteps int      // the number of steps of the build
+	Command    string   // the instruction run by the step, e.g. "RUN make"
+	Cached     bool     // true if the result of the step was taken from the build cache
+	Lines      []string // the output of the step, without the line breaks
+}
+
+// buildStepRegex matches the header of a step in the output of the build, e.g. "Step 2/5 : RUN make"
+var buildStepRegex = regexp.MustCompile(`^Step (\d+)/(\d+) : (.*)$`)
+
+// readBuildOutput reads the output of the build until it completes, printing it if the image
+// requires it, and decoding it into build events if the image has a consumer for them.
+func readBuildOutput(body io.Reader, img ImageBuildInfo) error {
+	var consumer func(BuildEvent)
+	if c, ok := img.(interface{ GetBuildProgressConsumer() func(BuildEvent) }); ok {
+		consumer = c.GetBuildProgressConsumer()
+	}
+
+	if consumer == nil {
+		return printBuildOutput(body, img)
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := decodeBuildProgress(pr, consumer)
+		// keep draining the pipe, so reading the output of the build is not blocked
+		_, _ = io.Copy(io.Discard, pr)
+		done <- err
+	}()
+
+	err := printBuildOutput(io.TeeReader(body, pw), img)
+	pw.Close()
+	decodeErr := <-done
+	if err != nil {
+		return err
+	}
+
+	return decodeErr
+}
+
+// printBuildOutput reads the output of the build, printing it to stderr if the image requires it.
+func printBuildOutput(body io.Reader, img ImageBuildInfo) error {
+	if img.ShouldPrintBuildLog() {
+		termFd, isTerm := term.GetFdInfo(os.Stderr)
+		err := jsonmessage.DisplayJSONMessagesStream(body, os.Stderr, termFd, isTerm, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// need to read the response from Docker, I think otherwise the image
+	// might not finish building before continuing to execute here
+	_, err := io.Copy(io.Discard, body)
+	return err
+}
+
+// decodeBuildProgress decodes the JSON messages of the output of the build, invoking the consumer
+// once each step completes. If the build fails, the failing step is consumed before returning the error.
+// Only the output of the classic builder is decoded, as the progress of BuildKit is not reported as text,
+// so the build options reject a consumer for BuildKit builds.
+func decodeBuildProgress(r io.Reader, consumer func(BuildEvent)) error {
+	var current *BuildEvent
+	emit := func() {
+		if current != nil {
+			consumer(*current)
+			current = nil
+		}
+	}
+
+	handle := func(line string) {
+		if m := buildStepRegex.FindStringSubmatch(line); m != nil {
+			emit()
+			step, _ := strconv.Atoi(m[1])
+			total, _ := strconv.Atoi(m[2])
+			current = &BuildEvent{Step: step, TotalSteps: total, Command: m[3]}
+			return
+		}
+
+		if strings.HasPrefix(line, "Successfully built ") || strings.HasPrefix(line, "Successfully tagged ") {
+			emit()
+			return
+		}
+
+		if current == nil {
+			return
+		}
+
+		if strings.TrimSpace(line) == "---> Using cache" {
+			current.Cached = true
+		}
+		current.Lines = append(current.Lines, line)
+	}
+
+	dec := json.NewDecoder(r)
+	var partial string
+	for {
+		var msg jsonmessage.JSONMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("decode build progress: %w", err)
+		}
+
+		if msg.Error != nil {
+			if partial != "" {
+				handle(partial)
+			}
+			emit()
+			return msg.Error
+		}
+
+		partial += msg.Stream
+		for {
+			i := strings.IndexByte(partial, '\n')
+			if i < 0 {
+				break
+			}
+			handle(partial[:i])
+			partial = partial[i+1:]
+		}
+	}
+
+	if partial != "" {
+		handle(partial)
+	}
+	emit()
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/docker/docker/api/types/versions"
+)
+
+// minBuildKitAPIVersion is the first version of the Docker API supporting BuildKit
+const minBuildKitAPIVersion = "1.39"
+
+// BuildSession is a session attached to an image build, through which the Docker engine
+// requests the secrets of the build from the client.
+type BuildSession interface {
+	// ID returns the ID of the session, passed to the Docker engine with the build
+	ID() string
+	// Close closes the session, once the build completes
+	Close() error
+}
+
+// BuildSessionDialer opens the connection to the Docker engine the session runs over,
+// upgrading the given protocol, with the given metadata as headers.
+type BuildSessionDialer func(ctx context.Context, proto string, meta map[string][]string) (net.Conn, error)
+
+// BuildSessionStarter starts a session providing the secrets of a build, with the secret id as key
+// and the path of the file with the secret as value, running it over the connection opened with dial.
+// Testcontainers does not depend on BuildKit, so the session is started by an implementation such as
+// the one of the github.com/testcontainers/testcontainers-go/buildkit module.
+type BuildSessionStarter func(ctx context.Context, buildSecrets map[string]string, dial BuildSessionDialer) (BuildSession, error)
+
+// buildSecretsInfo is implemented by the ImageBuildInfo defining the secrets of the build.
+type buildSecretsInfo interface {
+	GetBuildSecrets() map[string]string
+	GetBuildSessionStarter() BuildSessionStarter
+}
+
+// startBuildSession starts the session providing the secrets of the build, if the Docker engine
+// supports BuildKit. The session must be closed once the build completes.
+func (p *DockerProvider) startBuildSession(ctx context.Context, buildSecrets map[string]string, starter BuildSessionStarter) (BuildSession, error) {
+	ping, err := p.client.Ping(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ping: %w", err)
+	}
+
+	if versions.LessThan(ping.APIVersion, minBuildKitAPIVersion) || ping.OSType == "windows" {
+		return nil, fmt.Errorf("build secrets require BuildKit, which is not supported by the Docker engine (API version %s, OS %s)", ping.APIVersion, ping.OSType)
+	}
+
+	dial := func(ctx context.Context, proto string, meta map[string][]string) (net.Conn, error) {
+		return p.client.DialHijack(ctx, "/session", proto, meta)
+	}
+
+	sess, err := starter(ctx, buildSecrets, dial)
+	if err != nil {
+		return nil, fmt.Errorf("start build session: %w", err)
+	}
+
+	return sess, nil
+}
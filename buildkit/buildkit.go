@@ -0,0 +1,64 @@
+// Package buildkit provides the BuildKit session which the build secrets of a FromDockerfile
+// request require. It's a separate module, so Testcontainers does not depend on BuildKit.
+package buildkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets/secretsprovider"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// sessionName is the name of the BuildKit session, shown by the Docker engine
+const sessionName = "testcontainers"
+
+var _ testcontainers.BuildSessionStarter = StartSecretsSession
+
+// WithBuildSecrets sets the secrets of the build of the container, with the secret id as key and the
+// path of the file with the secret as value, and the BuildKit session providing them.
+func WithBuildSecrets(buildSecrets map[string]string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.FromDockerfile.BuildSecrets = buildSecrets
+		req.FromDockerfile.BuildSessionStarter = StartSecretsSession
+
+		return nil
+	}
+}
+
+// StartSecretsSession starts a BuildKit session providing the secrets of a build, running until
+// it's closed, or the connection to the Docker engine fails, which fails the build requiring it.
+func StartSecretsSession(ctx context.Context, buildSecrets map[string]string, dial testcontainers.BuildSessionDialer) (testcontainers.BuildSession, error) {
+	ids := make([]string, 0, len(buildSecrets))
+	for id := range buildSecrets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	sources := make([]secretsprovider.Source, 0, len(ids))
+	for _, id := range ids {
+		sources = append(sources, secretsprovider.Source{ID: id, FilePath: buildSecrets[id]})
+	}
+
+	store, err := secretsprovider.NewStore(sources)
+	if err != nil {
+		return nil, fmt.Errorf("build secrets: %w", err)
+	}
+
+	sess, err := session.NewSession(ctx, sessionName, "")
+	if err != nil {
+		return nil, fmt.Errorf("new build session: %w", err)
+	}
+	sess.Allow(secretsprovider.NewSecretProvider(store))
+
+	go func() {
+		if err := sess.Run(ctx, session.Dialer(dial)); err != nil {
+			testcontainers.Logger.Printf("🔒 Build session failed: %v", err)
+		}
+	}()
+
+	return sess, nil
+}
@@ -0,0 +1,60 @@
+package buildkit_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/buildkit"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestWithBuildSecrets(t *testing.T) {
+	ctx := context.Background()
+
+	secret := "s3cr3t-t0k3n"
+	secretPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secretPath, []byte(secret), 0o600))
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			FromDockerfile: testcontainers.FromDockerfile{
+				Context: "testdata/buildsecrets",
+			},
+			WaitingFor: wait.ForExit(),
+		},
+		Started: true,
+	}
+
+	// fromDockerfileWithBuildSecrets {
+	err := buildkit.WithBuildSecrets(map[string]string{
+		"token": secretPath,
+	})(&req)
+	// }
+	require.NoError(t, err)
+
+	c, err := testcontainers.GenericContainer(ctx, req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, c.Terminate(ctx))
+	})
+
+	r, err := c.Logs(ctx)
+	require.NoError(t, err)
+
+	logs, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	// the secret was mounted in the build, but it's not part of the image
+	sum := sha256.Sum256([]byte(secret))
+	assert.Contains(t, string(logs), hex.EncodeToString(sum[:]))
+	assert.Contains(t, string(logs), "No such file or directory")
+}
@@ -0,0 +1,160 @@
+package testcontainers
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// caTrustStore is the location of the CA certificates in the trust store of a distro,
+// and the command that updates the trust store with them.
+type caTrustStore struct {
+	dir    string
+	update []string
+}
+
+var (
+	// debianTrustStore is used by Debian, Ubuntu and Alpine based images
+	debianTrustStore = caTrustStore{dir: "/usr/local/share/ca-certificates", update: []string{"update-ca-certificates"}}
+	// suseTrustStore is used by openSUSE and SLES based images
+	suseTrustStore = caTrustStore{dir: "/etc/pki/trust/anchors", update: []string{"update-ca-certificates"}}
+	// rhelTrustStore is used by RHEL, Fedora, CentOS, Rocky, AlmaLinux and Amazon Linux based images
+	rhelTrustStore = caTrustStore{dir: "/etc/pki/ca-trust/source/anchors", update: []string{"update-ca-trust", "extract"}}
+)
+
+// caTrustStores maps the distro IDs of the os-release file to their trust stores
+var caTrustStores = map[string]caTrustStore{
+	"debian":   debianTrustStore,
+	"ubuntu":   debianTrustStore,
+	"alpine":   debianTrustStore,
+	"suse":     suseTrustStore,
+	"opensuse": suseTrustStore,
+	"sles":     suseTrustStore,
+	"rhel":     rhelTrustStore,
+	"fedora":   rhelTrustStore,
+	"centos":   rhelTrustStore,
+	"amzn":     rhelTrustStore,
+}
+
+// WithCACert adds the PEM encoded CA certificate to the trust store of the container, right after
+// the container is started and before waiting for it to be ready. The distro of the image is detected
+// from its /etc/os-release file, and the certificate is copied to the trust store of the distro, which
+// is then updated running its update-ca-certificates or update-ca-trust command as root.
+// Debian, Ubuntu, Alpine, SUSE and RHEL based images, such as Fedora, CentOS and Amazon Linux,
+// are supported, as long as the image contains the ca-certificates package.
+func WithCACert(certPEM []byte) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if err := validateCACert(certPEM); err != nil {
+			return err
+		}
+
+		req.LifecycleHooks = append(req.LifecycleHooks, ContainerLifecycleHooks{
+			PostStarts: []ContainerHook{
+				func(ctx context.Context, c Container) error {
+					return addCACert(ctx, c, certPEM)
+				},
+			},
+		})
+
+		return nil
+	}
+}
+
+// validateCACert ensures that the PEM encoded content is a single certificate.
+func validateCACert(certPEM []byte) error {
+	block, rest := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return errors.New("CA certificate: no PEM encoded certificate found")
+	}
+
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return errors.New("CA certificate: only one certificate is supported")
+	}
+
+	if _, err := x509.ParseCertificate(block.Bytes); err != nil {
+		return fmt.Errorf("CA certificate: %w", err)
+	}
+
+	return nil
+}
+
+// addCACert copies the certificate into the trust store of the container and updates it.
+func addCACert(ctx context.Context, c Container, certPEM []byte) error {
+	store, err := detectCATrustStore(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	// name the certificate after its content, so adding different certificates does not overwrite them
+	sum := sha256.Sum256(certPEM)
+	certPath := path.Join(store.dir, "testcontainers-"+hex.EncodeToString(sum[:8])+".crt")
+
+	if err := c.CopyToContainer(ctx, certPEM, certPath, 0o644); err != nil {
+		return fmt.Errorf("copy CA certificate to %s: %w", certPath, err)
+	}
+
+	code, reader, err := c.Exec(ctx, store.update, tcexec.WithUser("root"), tcexec.Multiplexed())
+	if err != nil {
+		return fmt.Errorf("%s: %w", strings.Join(store.update, " "), err)
+	}
+
+	if code != 0 {
+		output, _ := io.ReadAll(reader)
+		return fmt.Errorf("%s: exit code %d: %s", strings.Join(store.update, " "), code, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// detectCATrustStore returns the trust store of the distro of the container, reading its os-release file.
+func detectCATrustStore(ctx context.Context, c Container) (caTrustStore, error) {
+	code, reader, err := c.Exec(ctx, []string{"cat", "/etc/os-release"}, tcexec.Multiplexed())
+	if err != nil {
+		return caTrustStore{}, fmt.Errorf("read os-release: %w", err)
+	}
+
+	osRelease, err := io.ReadAll(reader)
+	if err != nil {
+		return caTrustStore{}, fmt.Errorf("read os-release: %w", err)
+	}
+
+	if code != 0 {
+		return caTrustStore{}, fmt.Errorf("read os-release: exit code %d: %s", code, strings.TrimSpace(string(osRelease)))
+	}
+
+	return caTrustStoreFor(string(osRelease))
+}
+
+// caTrustStoreFor returns the trust store for the distro described by the os-release file content,
+// looking for its ID first, and then for the IDs of the distros it's based on.
+func caTrustStoreFor(osRelease string) (caTrustStore, error) {
+	fields := map[string]string{}
+
+	scanner := bufio.NewScanner(strings.NewReader(osRelease))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		fields[key] = strings.Trim(value, `"'`)
+	}
+
+	ids := append([]string{fields["ID"]}, strings.Fields(fields["ID_LIKE"])...)
+	for _, id := range ids {
+		if store, ok := caTrustStores[id]; ok {
+			return store, nil
+		}
+	}
+
+	return caTrustStore{}, fmt.Errorf("unsupported distro %q to add a CA certificate", fields["ID"])
+}
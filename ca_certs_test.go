@@ -0,0 +1,168 @@
+package testcontainers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+)
+
+// newTestCA creates a self-signed CA, returning its PEM encoded certificate,
+// and a TLS certificate for the given host signed by it.
+func newTestCA(t *testing.T, host string) ([]byte, tls.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "testcontainers CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caCert, &serverKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+
+	return caPEM, tls.Certificate{Certificate: [][]byte{serverDER}, PrivateKey: serverKey}
+}
+
+func TestCATrustStoreFor(t *testing.T) {
+	tests := []struct {
+		name      string
+		osRelease string
+		want      caTrustStore
+		wantErr   bool
+	}{
+		{
+			name:      "debian",
+			osRelease: "PRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\nID=debian\n",
+			want:      debianTrustStore,
+		},
+		{
+			name:      "alpine",
+			osRelease: "NAME=\"Alpine Linux\"\nID=alpine\nVERSION_ID=3.19.1\n",
+			want:      debianTrustStore,
+		},
+		{
+			name:      "rocky-like-rhel",
+			osRelease: "NAME=\"Rocky Linux\"\nID=\"rocky\"\nID_LIKE=\"rhel centos fedora\"\n",
+			want:      rhelTrustStore,
+		},
+		{
+			name:      "opensuse-like-suse",
+			osRelease: "NAME=\"openSUSE Leap\"\nID=\"opensuse-leap\"\nID_LIKE=\"suse opensuse\"\n",
+			want:      suseTrustStore,
+		},
+		{
+			name:      "unsupported",
+			osRelease: "NAME=\"Arch Linux\"\nID=arch\n",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := caTrustStoreFor(tt.osRelease)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, store)
+		})
+	}
+}
+
+func TestWithCACert(t *testing.T) {
+	const host = "host.testcontainers.internal"
+
+	caPEM, serverCert := newTestCA(t, host)
+
+	t.Run("invalid", func(t *testing.T) {
+		req := &GenericContainerRequest{}
+
+		require.Error(t, WithCACert([]byte("not a certificate"))(req))
+		require.Error(t, WithCACert(append(caPEM, caPEM...))(req))
+		require.Empty(t, req.LifecycleHooks)
+	})
+
+	t.Run("trusted-in-container", func(t *testing.T) {
+		ctx := context.Background()
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("trusted"))
+		}))
+		server.TLS = &tls.Config{Certificates: []tls.Certificate{serverCert}}
+		server.StartTLS()
+		defer server.Close()
+
+		_, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+		require.NoError(t, err)
+		port, err := strconv.Atoi(portStr)
+		require.NoError(t, err)
+
+		req := GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image:           "docker.io/buildpack-deps:bookworm-curl",
+				Entrypoint:      []string{"tail", "-f", "/dev/null"},
+				HostAccessPorts: []int{port},
+			},
+			Started: true,
+		}
+		require.NoError(t, WithCACert(caPEM)(&req))
+
+		ctr, err := GenericContainer(ctx, req)
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		code, reader, err := ctr.Exec(ctx, []string{"curl", "--silent", "--show-error", fmt.Sprintf("https://%s:%d", host, port)}, tcexec.Multiplexed())
+		require.NoError(t, err)
+
+		output, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Zero(t, code, string(output))
+		require.Equal(t, "trusted", string(output))
+	})
+}
@@ -1,12 +1,15 @@
 package testcontainers
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -51,7 +54,7 @@ type Container interface {
 	FollowOutput(LogConsumer)                                       // Deprecated: it will be removed in the next major release
 	StartLogProducer(context.Context, ...LogProductionOption) error // Deprecated: Use the ContainerRequest instead
 	StopLogProducer() error                                         // Deprecated: it will be removed in the next major release
-	Name(context.Context) (string, error)                           // Deprecated: Use c.Inspect(ctx).Name instead
+	Name(context.Context) (string, error)                           // get the current name of the container
 	State(context.Context) (*types.ContainerState, error)           // returns container's running state
 	Networks(context.Context) ([]string, error)                     // get container networks
 	NetworkAliases(context.Context) (map[string][]string, error)    // get container network aliases for a network
@@ -87,6 +90,7 @@ type FromDockerfile struct {
 	Repo           string                         // the repo label for image, defaults to UUID
 	Tag            string                         // the tag label for image, defaults to UUID
 	BuildArgs      map[string]*string             // enable user to pass build args to docker daemon
+	ExtraHosts     []string                       // extra hosts for the build in the "host:ip" format, the same as the "--add-host" flag of "docker build"
 	PrintBuildLog  bool                           // enable user to print build log
 	AuthConfigs    map[string]registry.AuthConfig // Deprecated. Testcontainers will detect registry credentials automatically. Enable auth configs to be able to pull from an authenticated docker registry
 	// KeepImage describes whether DockerContainer.Terminate should not delete the
@@ -97,6 +101,25 @@ type FromDockerfile struct {
 	// advanced configurations while building the image. Please consider that the modifier
 	// is called after the default build options are set.
 	BuildOptionsModifier func(*types.ImageBuildOptions)
+	// AdditionalBuildContexts defines named build contexts, the same as the "--build-context"
+	// flag of "docker buildx build". The key is the name of the context, which can be consumed
+	// from the Dockerfile (e.g. "COPY --from=name" or "FROM name"), and the value is either a path
+	// to a local directory or an image reference prefixed with "docker-image://".
+	// It's only supported when the build context is defined with the Context field.
+	AdditionalBuildContexts map[string]string
+	// BuildProgressConsumer is invoked with a structured event for each step of the build, once the
+	// step completes, e.g. to report the progress of the build. Only the output of the classic builder
+	// is decoded into events, so setting it fails builds using BuildKit, e.g. the ones with build secrets.
+	BuildProgressConsumer func(BuildEvent)
+	// BuildSecrets defines the secrets of the build, the same as the "--secret id=...,src=..." flag of
+	// "docker build". The key is the id of the secret, consumed from the Dockerfile with
+	// "RUN --mount=type=secret,id=...", and the value is the path to the file holding it.
+	// The secrets are not stored in the layers of the image, and they require BuildKit and a build
+	// session providing them, started by the BuildSessionStarter.
+	BuildSecrets map[string]string
+	// BuildSessionStarter starts the session providing the BuildSecrets to the Docker engine, e.g.
+	// the StartSecretsSession function of the github.com/testcontainers/testcontainers-go/buildkit module.
+	BuildSessionStarter BuildSessionStarter
 }
 
 type ContainerFile struct {
@@ -119,6 +142,27 @@ func (c *ContainerFile) validate() error {
 	return nil
 }
 
+// ExtractFile is a file copied out of the container to the host once the container is ready,
+// e.g. a certificate generated by the container on startup.
+type ExtractFile struct {
+	ContainerFilePath string
+	HostFilePath      string
+	FileMode          int64 // permissions of the host file, 0o644 if zero
+}
+
+// validate validates the ExtractFile
+func (f *ExtractFile) validate() error {
+	if f.ContainerFilePath == "" {
+		return errors.New("ContainerFilePath must be specified")
+	}
+
+	if f.HostFilePath == "" {
+		return errors.New("HostFilePath must be specified")
+	}
+
+	return nil
+}
+
 // ContainerRequest represents the parameters used to get a running container
 type ContainerRequest struct {
 	FromDockerfile
@@ -128,31 +172,46 @@ type ContainerRequest struct {
 	Entrypoint              []string
 	Env                     map[string]string
 	ExposedPorts            []string // allow specifying protocol info
+	AutoExposeImagePorts    bool     // expose all the ports declared by the image on random host ports, in addition to the ExposedPorts
+	SkipPortMerge           bool     // keep the port bindings set by the HostConfigModifier verbatim, without binding the exposed ports to random host ports
 	Cmd                     []string
 	Labels                  map[string]string
 	Mounts                  ContainerMounts
-	Tmpfs                   map[string]string
-	RegistryCred            string // Deprecated: Testcontainers will detect registry credentials automatically
+	Tmpfs                   map[string]string // tmpfs mounts, by path, with their options, e.g. {"/run": "rw,size=64m"}, merged with the ones set by the modifiers
+	RegistryCred            string            // Deprecated: Testcontainers will detect registry credentials automatically
 	WaitingFor              wait.Strategy
 	Name                    string // for specifying container name
 	Hostname                string
 	WorkingDir              string                                     // specify the working directory of the container
+	OpenStdin               bool                                       // keep the stdin of the container open, to write to it once with DockerContainer.AttachStdin
 	ExtraHosts              []string                                   // Deprecated: Use HostConfigModifier instead
 	Privileged              bool                                       // For starting privileged container
 	Networks                []string                                   // for specifying network names
 	NetworkAliases          map[string][]string                        // for specifying network aliases
+	NetworkStaticIPs        map[string]string                          // for specifying the static IPv4 or IPv6 address of the container in a user-defined network, by network name
+	MacAddress              string                                     // MAC address of the container in its first network, e.g. "02:42:ac:11:00:02"
 	NetworkMode             container.NetworkMode                      // Deprecated: Use HostConfigModifier instead
 	Resources               container.Resources                        // Deprecated: Use HostConfigModifier instead
 	Files                   []ContainerFile                            // files which will be copied when container starts
+	ExtractFiles            []ExtractFile                              // files which will be copied to the host once the container is ready
 	User                    string                                     // for specifying uid:gid
 	SkipReaper              bool                                       // Deprecated: The reaper is globally controlled by the .testcontainers.properties file or the TESTCONTAINERS_RYUK_DISABLED environment variable
 	ReaperImage             string                                     // Deprecated: use WithImageName ContainerOption instead. Alternative reaper image
 	ReaperOptions           []ContainerOption                          // Deprecated: the reaper is configured at the properties level, for an entire test session
 	AutoRemove              bool                                       // Deprecated: Use HostConfigModifier instead. If set to true, the container will be removed from the host when stopped
 	AlwaysPullImage         bool                                       // Always pull image
+	ImageLocalOnly          bool                                       // Never pull an image referenced by digest, which must be present locally
 	ImagePlatform           string                                     // ImagePlatform describes the platform which the image runs on.
 	Binds                   []string                                   // Deprecated: Use HostConfigModifier instead
-	ShmSize                 int64                                      // Amount of memory shared with the host (in bytes)
+	ShmSize                 int64                                      // Size of /dev/shm in bytes. The daemon's default, 64MB, is used if zero. The host config modifiers take precedence
+	CpusetCpus              string                                     // CPUs in which to allow execution, e.g. "0-3,5"
+	CpusetMems              string                                     // Memory nodes (MEMs) in which to allow execution, e.g. "0-3,5". Only effective on NUMA systems
+	PidMode                 string                                     // PID namespace to use: "host" or "container:<name|id>". Empty means a private namespace
+	CgroupnsMode            string                                     // Cgroup namespace to use: "host" or "private". Empty means the daemon's default
+	UsernsMode              string                                     // User namespace to use: "host" disables the remapping of the daemon. Empty means the daemon's default
+	SecurityOpts            []string                                   // security options, e.g. "no-new-privileges", "apparmor=<profile>" or "seccomp=<json>"
+	ReadOnlyRootfs          bool                                       // mount the root filesystem of the container as read-only. Pair it with tmpfs mounts for the writable paths
+	RestartPolicy           container.RestartPolicy                    // restart the container automatically when it exits, e.g. when it's OOM-killed. Not compatible with AutoRemove
 	CapAdd                  []string                                   // Deprecated: Use HostConfigModifier instead. Add Linux capabilities
 	CapDrop                 []string                                   // Deprecated: Use HostConfigModifier instead. Drop Linux capabilities
 	ConfigModifier          func(*container.Config)                    // Modifier for the config before container creation
@@ -160,6 +219,17 @@ type ContainerRequest struct {
 	EnpointSettingsModifier func(map[string]*network.EndpointSettings) // Modifier for the network settings before container creation
 	LifecycleHooks          []ContainerLifecycleHooks                  // define hooks to be executed during container lifecycle
 	LogConsumerCfg          *LogConsumerConfig                         // define the configuration for the log producer and its log consumers to follow the logs
+	CaptureStatsOnTerminate bool                                       // capture the resource usage of the container right before terminating it, see DockerContainer.LastStats
+	StopStrategy            StopStrategy                               // prepare the container to be stopped, e.g. draining its connections, right before stopping it
+	StopTimeout             *time.Duration                             // grace period given to the container to stop, by Stop and Terminate, before it's killed, rounded up to whole seconds. Nil or zero keeps the engine default, negative values are rejected. Not applied by the reaper, which force-removes the containers left behind
+
+	// configModifiers and hostConfigModifiers are the modifiers registered at a priority,
+	// applied together with the ConfigModifier and HostConfigModifier fields, see ModifierPriority.
+	configModifiers     []configModifier
+	hostConfigModifiers []hostConfigModifier
+
+	// runOnce is true if commands are run once with WithRunOnce, which requires a writable root filesystem
+	runOnce bool
 }
 
 // containerOptions functional options for a container
@@ -194,7 +264,17 @@ func (c *ContainerRequest) Validate() error {
 	validationMethods := []func() error{
 		c.validateContextAndImage,
 		c.validateContextOrImageIsSpecified,
+		c.validateAdditionalBuildContexts,
+		c.validateBuildSecrets,
+		c.validateBuildExtraHosts,
+		c.validateCpusets,
+		c.validateNamespaceModes,
+		c.validateMacAddress,
 		c.validateMounts,
+		c.validateImageLocalOnly,
+		c.validateRestartPolicy,
+		c.validateRunOnce,
+		c.validateStopTimeout,
 	}
 
 	var err error
@@ -213,7 +293,7 @@ func (c *ContainerRequest) GetContext() (io.Reader, error) {
 	var includes []string = []string{"."}
 
 	if c.ContextArchive != nil {
-		return c.ContextArchive, nil
+		return peekContextArchive(c.ContextArchive)
 	}
 
 	// always pass context as absolute path
@@ -243,6 +323,12 @@ func (c *ContainerRequest) GetContext() (io.Reader, error) {
 		return nil, err
 	}
 
+	if len(c.AdditionalBuildContexts) > 0 {
+		defer buildContext.Close()
+
+		return withAdditionalBuildContexts(buildContext, c.GetDockerfile(), c.AdditionalBuildContexts)
+	}
+
 	return buildContext, nil
 }
 
@@ -266,11 +352,76 @@ func parseDockerIgnore(targetDir string) (bool, []string, error) {
 	return exists, excluded, nil
 }
 
+// tarBlockSize is the size of the blocks of a tar archive, including its headers
+const tarBlockSize = 512
+
+// peekContextArchive checks that the build context archive starts with a tar header, peeking it,
+// and returns a reader with the whole archive. Compressed archives are not checked, as they are
+// decompressed by the Docker engine.
+func peekContextArchive(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, tarBlockSize)
+
+	block, err := br.Peek(tarBlockSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("read context archive: %w", err)
+	}
+
+	if archive.DetectCompression(block) != archive.Uncompressed {
+		return br, nil
+	}
+
+	if !isTarHeader(block) {
+		return nil, errors.New("invalid context archive: not a tar archive")
+	}
+
+	return br, nil
+}
+
+// isTarHeader reports whether the block is a tar header, verifying its checksum,
+// which is the sum of the bytes of the header, counting the checksum field as spaces.
+func isTarHeader(block []byte) bool {
+	if len(block) < tarBlockSize {
+		return false
+	}
+
+	const chksumStart, chksumEnd = 148, 156
+
+	chksum, err := strconv.ParseInt(strings.Trim(string(block[chksumStart:chksumEnd]), " \x00"), 8, 64)
+	if err != nil {
+		return false
+	}
+
+	var sum int64
+	for i, b := range block[:tarBlockSize] {
+		if i >= chksumStart && i < chksumEnd {
+			b = ' '
+		}
+		sum += int64(b)
+	}
+
+	return sum == chksum
+}
+
 // GetBuildArgs returns the env args to be used when creating from Dockerfile
 func (c *ContainerRequest) GetBuildArgs() map[string]*string {
 	return c.FromDockerfile.BuildArgs
 }
 
+// GetBuildProgressConsumer returns the consumer of the events of the build, if any
+func (c *ContainerRequest) GetBuildProgressConsumer() func(BuildEvent) {
+	return c.FromDockerfile.BuildProgressConsumer
+}
+
+// GetBuildSecrets returns the secrets of the build, by id, if any
+func (c *ContainerRequest) GetBuildSecrets() map[string]string {
+	return c.FromDockerfile.BuildSecrets
+}
+
+// GetBuildSessionStarter returns the starter of the session providing the secrets of the build, if any
+func (c *ContainerRequest) GetBuildSessionStarter() BuildSessionStarter {
+	return c.FromDockerfile.BuildSessionStarter
+}
+
 // GetDockerfile returns the Dockerfile from the ContainerRequest, defaults to "Dockerfile"
 func (c *ContainerRequest) GetDockerfile() string {
 	f := c.FromDockerfile.Dockerfile
@@ -354,7 +505,23 @@ func (c *ContainerRequest) BuildOptions() (types.ImageBuildOptions, error) {
 
 	// apply mandatory values after the modifier
 	buildOptions.BuildArgs = c.GetBuildArgs()
+
+	if len(c.FromDockerfile.BuildSecrets) > 0 {
+		switch buildOptions.Version {
+		case "":
+			buildOptions.Version = types.BuilderBuildKit
+		case types.BuilderBuildKit:
+		default:
+			return buildOptions, fmt.Errorf("build secrets require BuildKit, but the build options use the builder version %q", buildOptions.Version)
+		}
+	}
+
+	// the progress of BuildKit is not reported as text, so it could not be decoded into events
+	if c.FromDockerfile.BuildProgressConsumer != nil && buildOptions.Version == types.BuilderBuildKit {
+		return buildOptions, errors.New("the build progress consumer is only supported by the classic builder, but the build uses BuildKit")
+	}
 	buildOptions.Dockerfile = c.GetDockerfile()
+	buildOptions.ExtraHosts = append(buildOptions.ExtraHosts, c.FromDockerfile.ExtraHosts...)
 
 	buildContext, err := c.GetContext()
 	if err != nil {
@@ -419,6 +586,212 @@ func (c *ContainerRequest) validateContextOrImageIsSpecified() error {
 	return nil
 }
 
+// validateAdditionalBuildContexts ensures that the additional build contexts are only used
+// together with a build context path, and that the local ones exist and are directories.
+func (c *ContainerRequest) validateAdditionalBuildContexts() error {
+	if len(c.AdditionalBuildContexts) == 0 {
+		return nil
+	}
+
+	if c.FromDockerfile.Context == "" || c.FromDockerfile.ContextArchive != nil {
+		return errors.New("additional build contexts can only be used with a build context path")
+	}
+
+	for name, value := range c.AdditionalBuildContexts {
+		if name == "" {
+			return errors.New("additional build context name must not be empty")
+		}
+
+		if strings.HasPrefix(value, dockerImageBuildContextPrefix) {
+			if strings.TrimPrefix(value, dockerImageBuildContextPrefix) == "" {
+				return fmt.Errorf("additional build context %s: image reference must not be empty", name)
+			}
+
+			continue
+		}
+
+		dir, err := isDir(value)
+		if err != nil {
+			return fmt.Errorf("additional build context %s: %w", name, err)
+		}
+
+		if !dir {
+			return fmt.Errorf("additional build context %s: path %s is not a directory", name, value)
+		}
+	}
+
+	return nil
+}
+
+// validateBuildSecrets ensures that the build secrets have an id, that their files exist,
+// and that there is a session starter to provide them.
+func (c *ContainerRequest) validateBuildSecrets() error {
+	if len(c.FromDockerfile.BuildSecrets) > 0 && c.FromDockerfile.BuildSessionStarter == nil {
+		return errors.New("build secrets require a build session starter, e.g. the one of the github.com/testcontainers/testcontainers-go/buildkit module")
+	}
+
+	for id, path := range c.FromDockerfile.BuildSecrets {
+		if id == "" {
+			return errors.New("build secret id must not be empty")
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("build secret %s: %w", id, err)
+		}
+
+		if info.IsDir() {
+			return fmt.Errorf("build secret %s: path %s is a directory", id, path)
+		}
+	}
+
+	return nil
+}
+
+// validateBuildExtraHosts ensures that the extra hosts of the build are in the "host:ip" format,
+// where the IP can also be the special "host-gateway" value.
+func (c *ContainerRequest) validateBuildExtraHosts() error {
+	for _, extraHost := range c.FromDockerfile.ExtraHosts {
+		host, ip, ok := strings.Cut(extraHost, ":")
+		if !ok || host == "" {
+			return fmt.Errorf("invalid extra host %q: must be in the \"host:ip\" format", extraHost)
+		}
+
+		if ip != "host-gateway" && net.ParseIP(strings.Trim(ip, "[]")) == nil {
+			return fmt.Errorf("invalid extra host %q: invalid IP address %q", extraHost, ip)
+		}
+	}
+
+	return nil
+}
+
+// validateCpusets ensures that the CPU and memory node sets use the cpuset list format,
+// i.e. a comma-separated list of numbers or ranges of numbers, e.g. "0-3,5".
+func (c *ContainerRequest) validateCpusets() error {
+	cpusets := map[string]string{
+		"CpusetCpus": c.CpusetCpus,
+		"CpusetMems": c.CpusetMems,
+	}
+
+	for field, cpuset := range cpusets {
+		if cpuset == "" {
+			continue
+		}
+
+		if err := validateCpuset(cpuset); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", field, cpuset, err)
+		}
+	}
+
+	return nil
+}
+
+// validateNamespaceModes ensures that the PID and cgroup namespace modes are known to Docker.
+func (c *ContainerRequest) validateNamespaceModes() error {
+	if pidMode := container.PidMode(c.PidMode); !pidMode.Valid() {
+		return fmt.Errorf("invalid PidMode %q: must be \"host\" or \"container:<name|id>\"", c.PidMode)
+	}
+
+	if cgroupnsMode := container.CgroupnsMode(c.CgroupnsMode); !cgroupnsMode.Valid() {
+		return fmt.Errorf("invalid CgroupnsMode %q: must be \"host\" or \"private\"", c.CgroupnsMode)
+	}
+
+	// the remapping profiles are configured in the daemon, so a container can only opt out of them
+	if usernsMode := container.UsernsMode(c.UsernsMode); !usernsMode.Valid() {
+		return fmt.Errorf("invalid UsernsMode %q: must be \"host\"", c.UsernsMode)
+	}
+
+	return nil
+}
+
+// validateImageLocalOnly ensures that an image is not both always pulled and never pulled.
+func (c *ContainerRequest) validateImageLocalOnly() error {
+	if c.ImageLocalOnly && c.AlwaysPullImage {
+		return errors.New("ImageLocalOnly and AlwaysPullImage cannot be used together")
+	}
+
+	return nil
+}
+
+// validateRestartPolicy ensures that the restart policy is valid, and that the container is not
+// automatically removed, as the Docker engine does not restart the containers it removes.
+func (c *ContainerRequest) validateRestartPolicy() error {
+	if err := container.ValidateRestartPolicy(c.RestartPolicy); err != nil {
+		return err
+	}
+
+	if !c.RestartPolicy.IsNone() && c.AutoRemove {
+		return fmt.Errorf("restart policy %q cannot be used together with AutoRemove", c.RestartPolicy.Name)
+	}
+
+	return nil
+}
+
+// validateRunOnce ensures that the root filesystem is writable when commands are run once,
+// as the markers recording that they ran are written into it.
+func (c *ContainerRequest) validateRunOnce() error {
+	if c.runOnce && c.ReadOnlyRootfs {
+		return errRunOnceReadOnlyRootfs
+	}
+
+	return nil
+}
+
+// validateStopTimeout ensures that the grace period is not negative, which the engine
+// takes as waiting forever for the container to stop, so Stop and Terminate would hang.
+func (c *ContainerRequest) validateStopTimeout() error {
+	if c.StopTimeout != nil && *c.StopTimeout < 0 {
+		return fmt.Errorf("invalid StopTimeout %s: must not be negative", *c.StopTimeout)
+	}
+
+	return nil
+}
+
+// validateMacAddress ensures that the MAC address is a valid Ethernet address.
+func (c *ContainerRequest) validateMacAddress() error {
+	if c.MacAddress == "" {
+		return nil
+	}
+
+	hw, err := net.ParseMAC(c.MacAddress)
+	if err != nil {
+		return fmt.Errorf("invalid MacAddress %q: %w", c.MacAddress, err)
+	}
+
+	if len(hw) != 6 {
+		return fmt.Errorf("invalid MacAddress %q: must be a 48-bit Ethernet address", c.MacAddress)
+	}
+
+	return nil
+}
+
+// validateCpuset validates a cpuset in the list format, e.g. "0-3,5".
+func validateCpuset(cpuset string) error {
+	for _, item := range strings.Split(cpuset, ",") {
+		bounds := strings.SplitN(item, "-", 2)
+
+		low, err := strconv.ParseUint(bounds[0], 10, 16)
+		if err != nil {
+			return fmt.Errorf("%q is not a number or a range of numbers", item)
+		}
+
+		if len(bounds) == 1 {
+			continue
+		}
+
+		high, err := strconv.ParseUint(bounds[1], 10, 16)
+		if err != nil {
+			return fmt.Errorf("%q is not a number or a range of numbers", item)
+		}
+
+		if low > high {
+			return fmt.Errorf("range %q is not in ascending order", item)
+		}
+	}
+
+	return nil
+}
+
 // validateMounts ensures that the mounts do not have duplicate targets.
 // It will check the Mounts and HostConfigModifier.Binds fields.
 func (c *ContainerRequest) validateMounts() error {
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestContainerFileValidation(t *testing.T) {
@@ -71,3 +73,44 @@ func TestContainerFileValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractFileValidation(t *testing.T) {
+	testTable := []struct {
+		Name          string
+		ExpectedError string
+		File          ExtractFile
+	}{
+		{
+			Name: "valid extract file",
+			File: ExtractFile{
+				ContainerFilePath: "/path/to/container",
+				HostFilePath:      "/path/to/host",
+			},
+		},
+		{
+			Name:          "invalid extract file: no container path",
+			ExpectedError: "ContainerFilePath must be specified",
+			File: ExtractFile{
+				HostFilePath: "/path/to/host",
+			},
+		},
+		{
+			Name:          "invalid extract file: no host path",
+			ExpectedError: "HostFilePath must be specified",
+			File: ExtractFile{
+				ContainerFilePath: "/path/to/container",
+			},
+		},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.Name, func(t *testing.T) {
+			err := testCase.File.validate()
+			if testCase.ExpectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, testCase.ExpectedError)
+		})
+	}
+}
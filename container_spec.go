@@ -0,0 +1,177 @@
+package testcontainers
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+)
+
+// ContainerSpec is the effective configuration of a container, which can be serialized to JSON,
+// e.g. to attach it to a bug report, and used to recreate the container with RunFromSpec.
+type ContainerSpec struct {
+	Image        string               `json:"image"`                 // the image of the container, as requested
+	ImageID      string               `json:"imageID,omitempty"`     // the ID of the image in the Docker engine, e.g. "sha256:<hex>"
+	ImageDigest  string               `json:"imageDigest,omitempty"` // the image pinned by its registry digest, e.g. "redis@sha256:<hex>", if it was pulled
+	Env          []string             `json:"env,omitempty"`         // the environment variables, as KEY=VALUE, including the ones of the image
+	Entrypoint   []string             `json:"entrypoint,omitempty"`
+	Cmd          []string             `json:"cmd,omitempty"`
+	WorkingDir   string               `json:"workingDir,omitempty"`
+	User         string               `json:"user,omitempty"`
+	ExposedPorts []string             `json:"exposedPorts,omitempty"` // the exposed ports, e.g. "80/tcp", sorted
+	Mounts       []ContainerSpecMount `json:"mounts,omitempty"`       // the mounts, without the anonymous volumes, e.g. the ones declared by the image
+	Labels       map[string]string    `json:"labels,omitempty"`       // the labels, without the ones added by Testcontainers
+}
+
+// ContainerSpecMount is a mount of a ContainerSpec.
+type ContainerSpecMount struct {
+	Type     mount.Type `json:"type"`             // the type of the mount, e.g. "bind", "volume" or "tmpfs"
+	Source   string     `json:"source,omitempty"` // the path of the host for a bind mount, or the name of the volume
+	Target   string     `json:"target"`           // the path in the container
+	ReadOnly bool       `json:"readOnly,omitempty"`
+}
+
+// ExportSpec returns the effective configuration of the container, to recreate it with RunFromSpec.
+// The ports of the host the exposed ports are bound to are not kept, as they are random. The anonymous
+// volumes are not kept either, so the recreated container gets new ones instead of sharing them.
+func (c *DockerContainer) ExportSpec(ctx context.Context) (ContainerSpec, error) {
+	inspect, err := c.inspectRawContainer(ctx)
+	if err != nil {
+		return ContainerSpec{}, err
+	}
+
+	img, _, err := c.provider.client.ImageInspectWithRaw(ctx, inspect.Image)
+	if err != nil {
+		return ContainerSpec{}, fmt.Errorf("inspect image %s: %w", inspect.Image, err)
+	}
+
+	return newContainerSpec(*inspect, img), nil
+}
+
+// newContainerSpec returns the spec of the inspected container, created from the inspected image.
+func newContainerSpec(inspect types.ContainerJSON, img types.ImageInspect) ContainerSpec {
+	spec := ContainerSpec{
+		ImageID: img.ID,
+	}
+
+	if inspect.Config != nil {
+		spec.Image = inspect.Config.Image
+		spec.Env = inspect.Config.Env
+		spec.Entrypoint = inspect.Config.Entrypoint
+		spec.Cmd = inspect.Config.Cmd
+		spec.WorkingDir = inspect.Config.WorkingDir
+		spec.User = inspect.Config.User
+
+		for port := range inspect.Config.ExposedPorts {
+			spec.ExposedPorts = append(spec.ExposedPorts, string(port))
+		}
+		sort.Strings(spec.ExposedPorts)
+
+		for k, v := range inspect.Config.Labels {
+			if strings.HasPrefix(k, core.LabelBase) {
+				continue
+			}
+			if spec.Labels == nil {
+				spec.Labels = map[string]string{}
+			}
+			spec.Labels[k] = v
+		}
+	}
+
+	if len(img.RepoDigests) > 0 {
+		spec.ImageDigest = img.RepoDigests[0]
+	}
+
+	for _, m := range inspect.Mounts {
+		source := m.Source
+		if m.Type == mount.TypeVolume {
+			if isAnonymousVolume(m.Name) {
+				continue
+			}
+			source = m.Name
+		}
+
+		spec.Mounts = append(spec.Mounts, ContainerSpecMount{
+			Type:     m.Type,
+			Source:   source,
+			Target:   m.Destination,
+			ReadOnly: !m.RW,
+		})
+	}
+
+	return spec
+}
+
+// isAnonymousVolume reports whether the volume was created by the engine, e.g. for a volume declared
+// by the image, in which case it's named after a random 64 characters hexadecimal ID.
+func isAnonymousVolume(name string) bool {
+	if len(name) != 64 {
+		return false
+	}
+
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
+
+// RunFromSpec creates and starts a container with the configuration of the spec, applying the options,
+// e.g. to set a wait strategy. The image is pinned by its registry digest if the spec has it,
+// otherwise the image of the spec is used.
+func RunFromSpec(ctx context.Context, spec ContainerSpec, opts ...ContainerCustomizer) (Container, error) {
+	req := GenericContainerRequest{
+		ContainerRequest: spec.request(),
+		Started:          true,
+	}
+
+	for _, opt := range opts {
+		if err := opt.Customize(&req); err != nil {
+			return nil, err
+		}
+	}
+
+	return GenericContainer(ctx, req)
+}
+
+// request returns the container request recreating the container of the spec.
+func (s ContainerSpec) request() ContainerRequest {
+	image := s.Image
+	if s.ImageDigest != "" {
+		image = s.ImageDigest
+	}
+
+	mounts := make([]mount.Mount, 0, len(s.Mounts))
+	for _, m := range s.Mounts {
+		mounts = append(mounts, mount.Mount{
+			Type:     m.Type,
+			Source:   m.Source,
+			Target:   m.Target,
+			ReadOnly: m.ReadOnly,
+		})
+	}
+
+	return ContainerRequest{
+		Image:        image,
+		Env:          envMap(s.Env),
+		Entrypoint:   s.Entrypoint,
+		Cmd:          s.Cmd,
+		WorkingDir:   s.WorkingDir,
+		User:         s.User,
+		ExposedPorts: s.ExposedPorts,
+		Labels:       maps.Clone(s.Labels), // the labels of Testcontainers are added to the ones of the request
+		// the mounts are registered at the default priority, instead of using the HostConfigModifier field,
+		// so they are kept when the options set a host config modifier, and can be changed by it
+		hostConfigModifiers: []hostConfigModifier{{
+			priority: ModifierPriorityDefault,
+			modify: func(hostConfig *container.HostConfig) {
+				hostConfig.Mounts = append(hostConfig.Mounts, mounts...)
+			},
+		}},
+	}
+}
@@ -0,0 +1,169 @@
+package testcontainers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/internal/core"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestContainerSpec(t *testing.T) {
+	inspect := types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			Image: "sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1",
+		},
+		Config: &container.Config{
+			Image:      "redis:7",
+			Env:        []string{"PATH=/usr/local/bin:/usr/bin", "REDIS_ARGS=--save 60 1", "EMPTY="},
+			Entrypoint: []string{"docker-entrypoint.sh"},
+			Cmd:        []string{"redis-server"},
+			WorkingDir: "/data",
+			User:       "redis",
+			ExposedPorts: nat.PortSet{
+				"6379/tcp":  {},
+				"16379/tcp": {},
+			},
+			Labels: map[string]string{
+				"app":               "cache",
+				core.LabelBase:      "true",
+				core.LabelSessionID: "1234",
+			},
+		},
+		Mounts: []types.MountPoint{
+			{Type: mount.TypeBind, Source: "/tmp/conf", Destination: "/etc/redis", RW: false},
+			{Type: mount.TypeVolume, Name: "redis-data", Source: "/var/lib/docker/volumes/redis-data/_data", Destination: "/data", RW: true},
+			// anonymous volume, e.g. declared by the image, which is not kept
+			{Type: mount.TypeVolume, Name: "f1a8b7c6d5e4f3a2b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8", Destination: "/cache", RW: true},
+		},
+	}
+	img := types.ImageInspect{
+		ID:          "sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1",
+		RepoDigests: []string{"redis@sha256:0f97c1c9daf5b69b93390ccbe8d3e2971617ec4801fd0882c72bf7cad3a13494"},
+	}
+
+	spec := newContainerSpec(inspect, img)
+
+	want := ContainerSpec{
+		Image:        "redis:7",
+		ImageID:      "sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1",
+		ImageDigest:  "redis@sha256:0f97c1c9daf5b69b93390ccbe8d3e2971617ec4801fd0882c72bf7cad3a13494",
+		Env:          []string{"PATH=/usr/local/bin:/usr/bin", "REDIS_ARGS=--save 60 1", "EMPTY="},
+		Entrypoint:   []string{"docker-entrypoint.sh"},
+		Cmd:          []string{"redis-server"},
+		WorkingDir:   "/data",
+		User:         "redis",
+		ExposedPorts: []string{"16379/tcp", "6379/tcp"},
+		Mounts: []ContainerSpecMount{
+			{Type: mount.TypeBind, Source: "/tmp/conf", Target: "/etc/redis", ReadOnly: true},
+			{Type: mount.TypeVolume, Source: "redis-data", Target: "/data"},
+		},
+		Labels: map[string]string{"app": "cache"},
+	}
+	require.Equal(t, want, spec)
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(spec)
+		require.NoError(t, err)
+
+		var decoded ContainerSpec
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		require.Equal(t, spec, decoded)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		req := spec.request()
+
+		require.Equal(t, "redis@sha256:0f97c1c9daf5b69b93390ccbe8d3e2971617ec4801fd0882c72bf7cad3a13494", req.Image)
+		require.Equal(t, map[string]string{"PATH": "/usr/local/bin:/usr/bin", "REDIS_ARGS": "--save 60 1", "EMPTY": ""}, req.Env)
+		require.Equal(t, []string{"docker-entrypoint.sh"}, req.Entrypoint)
+		require.Equal(t, []string{"redis-server"}, req.Cmd)
+		require.Equal(t, "/data", req.WorkingDir)
+		require.Equal(t, "redis", req.User)
+		require.Equal(t, []string{"16379/tcp", "6379/tcp"}, req.ExposedPorts)
+		require.Equal(t, map[string]string{"app": "cache"}, req.Labels)
+
+		hostConfig := &container.HostConfig{}
+		req.applyHostConfigModifiers(hostConfig)
+		require.Equal(t, []mount.Mount{
+			{Type: mount.TypeBind, Source: "/tmp/conf", Target: "/etc/redis", ReadOnly: true},
+			{Type: mount.TypeVolume, Source: "redis-data", Target: "/data"},
+		}, hostConfig.Mounts)
+	})
+
+	t.Run("request-with-host-config-modifier", func(t *testing.T) {
+		req := GenericContainerRequest{ContainerRequest: spec.request()}
+		require.NoError(t, WithHostConfigModifier(func(hostConfig *container.HostConfig) {
+			hostConfig.Privileged = true
+		})(&req))
+
+		hostConfig := &container.HostConfig{}
+		req.applyHostConfigModifiers(hostConfig)
+		require.True(t, hostConfig.Privileged)
+		require.Equal(t, []mount.Mount{
+			{Type: mount.TypeBind, Source: "/tmp/conf", Target: "/etc/redis", ReadOnly: true},
+			{Type: mount.TypeVolume, Source: "redis-data", Target: "/data"},
+		}, hostConfig.Mounts)
+	})
+
+	t.Run("request-without-digest", func(t *testing.T) {
+		spec := spec
+		spec.ImageDigest = ""
+
+		require.Equal(t, "redis:7", spec.request().Image)
+	})
+}
+
+func TestRunFromSpec(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:        nginxAlpineImage,
+			ExposedPorts: []string{nginxDefaultPort},
+			Env:          map[string]string{"FOO": "bar"},
+			Labels:       map[string]string{"app": "web"},
+			WorkingDir:   "/usr/share/nginx",
+			WaitingFor:   wait.ForListeningPort(nginxDefaultPort),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	spec, err := ctr.(*DockerContainer).ExportSpec(ctx)
+	require.NoError(t, err)
+
+	// the spec survives its serialization, e.g. when attached to a bug report
+	b, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	var decoded ContainerSpec
+	require.NoError(t, json.Unmarshal(b, &decoded))
+
+	replayed, err := RunFromSpec(ctx, decoded, WithWaitStrategy(wait.ForListeningPort(nginxDefaultPort)))
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, replayed)
+
+	replayedSpec, err := replayed.(*DockerContainer).ExportSpec(ctx)
+	require.NoError(t, err)
+
+	// the image may be pinned by its digest in the recreated container
+	require.Equal(t, spec.ImageID, replayedSpec.ImageID)
+	replayedSpec.Image = spec.Image
+
+	require.ElementsMatch(t, spec.Env, replayedSpec.Env)
+	replayedSpec.Env = spec.Env
+
+	require.Equal(t, spec, replayedSpec)
+	require.Contains(t, spec.Env, "FOO=bar")
+	require.Equal(t, "web", spec.Labels["app"])
+}
@@ -3,6 +3,7 @@ package testcontainers_test
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -84,6 +85,122 @@ func Test_ContainerValidation(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name:          "Can set cpusets",
+			ExpectedError: nil,
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "redis:latest",
+				CpusetCpus: "0-3,5",
+				CpusetMems: "0",
+			},
+		},
+		{
+			Name:          "Invalid cpuset cpus",
+			ExpectedError: errors.New(`invalid CpusetCpus "0-a": "0-a" is not a number or a range of numbers`),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "redis:latest",
+				CpusetCpus: "0-a",
+			},
+		},
+		{
+			Name:          "Invalid cpuset mems range",
+			ExpectedError: errors.New(`invalid CpusetMems "3-1": range "3-1" is not in ascending order`),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "redis:latest",
+				CpusetMems: "3-1",
+			},
+		},
+		{
+			Name:          "Can set namespace modes",
+			ExpectedError: nil,
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:        "redis:latest",
+				PidMode:      "container:my-sidecar",
+				CgroupnsMode: "host",
+				UsernsMode:   "host",
+			},
+		},
+		{
+			Name:          "Invalid pid mode",
+			ExpectedError: errors.New(`invalid PidMode "private": must be "host" or "container:<name|id>"`),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:   "redis:latest",
+				PidMode: "private",
+			},
+		},
+		{
+			Name:          "Invalid cgroupns mode",
+			ExpectedError: errors.New(`invalid CgroupnsMode "container:foo": must be "host" or "private"`),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:        "redis:latest",
+				CgroupnsMode: "container:foo",
+			},
+		},
+		{
+			Name:          "Invalid userns mode",
+			ExpectedError: errors.New(`invalid UsernsMode "default": must be "host"`),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "redis:latest",
+				UsernsMode: "default",
+			},
+		},
+		{
+			Name:          "Can set a restart policy",
+			ExpectedError: nil,
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:         "redis:latest",
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 3},
+			},
+		},
+		{
+			Name:          "Invalid restart policy",
+			ExpectedError: errors.New("invalid restart policy: maximum retry count can only be used with 'on-failure'"),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:         "redis:latest",
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyAlways, MaximumRetryCount: 3},
+			},
+		},
+		{
+			Name:          "Restart policy with AutoRemove",
+			ExpectedError: errors.New(`restart policy "unless-stopped" cannot be used together with AutoRemove`),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:         "redis:latest",
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyUnlessStopped},
+				AutoRemove:    true,
+			},
+		},
+		{
+			Name:          "Negative stop timeout",
+			ExpectedError: errors.New("invalid StopTimeout -1s: must not be negative"),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:       "redis:latest",
+				StopTimeout: func() *time.Duration { d := -time.Second; return &d }(),
+			},
+		},
+		{
+			Name:          "Valid MAC address",
+			ExpectedError: nil,
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "redis:latest",
+				MacAddress: "02:42:ac:11:00:02",
+			},
+		},
+		{
+			Name:          "Invalid MAC address",
+			ExpectedError: errors.New(`invalid MacAddress "02:42:ac:11:00": address 02:42:ac:11:00: invalid MAC address`),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "redis:latest",
+				MacAddress: "02:42:ac:11:00",
+			},
+		},
+		{
+			Name:          "Non-Ethernet MAC address",
+			ExpectedError: errors.New(`invalid MacAddress "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01": must be a 48-bit Ethernet address`),
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "redis:latest",
+				MacAddress: "00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01",
+			},
+		},
 	}
 
 	for _, testCase := range testTable {
@@ -144,6 +261,56 @@ func Test_GetDockerfile(t *testing.T) {
 	}
 }
 
+func Test_GetContextArchive(t *testing.T) {
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	content := "FROM docker.io/alpine"
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "Dockerfile", Mode: 0o644, Size: int64(len(content))}))
+	_, err := tw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+
+	var compressed bytes.Buffer
+	gw := gzip.NewWriter(&compressed)
+	_, err = gw.Write(archive.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	tests := []struct {
+		name    string
+		archive []byte
+		wantErr bool
+	}{
+		{name: "tar", archive: archive.Bytes()},
+		{name: "compressed", archive: compressed.Bytes()},
+		{name: "empty", archive: []byte{}, wantErr: true},
+		{name: "not-a-tar", archive: []byte(strings.Repeat("FROM docker.io/alpine\n", 50)), wantErr: true},
+		{name: "truncated-header", archive: archive.Bytes()[:100], wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testcontainers.ContainerRequest{
+				FromDockerfile: testcontainers.FromDockerfile{
+					ContextArchive: bytes.NewReader(tt.archive),
+				},
+			}
+
+			r, err := req.GetContext()
+			if tt.wantErr {
+				require.ErrorContains(t, err, "not a tar archive")
+				return
+			}
+			require.NoError(t, err)
+
+			// the peeked header is still sent to the Docker engine
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, tt.archive, b)
+		})
+	}
+}
+
 func Test_BuildImageWithContexts(t *testing.T) {
 	type TestCase struct {
 		Name               string
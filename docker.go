@@ -3,6 +3,7 @@ package testcontainers
 import (
 	"archive/tar"
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/binary"
@@ -11,11 +12,16 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"math"
 	"net"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,12 +32,14 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/jsonmessage"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
-	"github.com/moby/term"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 
 	tcexec "github.com/testcontainers/testcontainers-go/exec"
@@ -63,8 +71,18 @@ type DockerContainer struct {
 
 	isRunning     bool
 	imageWasBuilt bool
-	// keepBuiltImage makes Terminate not remove the image if imageWasBuilt.
-	keepBuiltImage     bool
+	// keepBuiltImage makes Terminate not remove the image if imageWasBuilt, nor the tags of pushedRefs.
+	keepBuiltImage bool
+	// pushedRefs are the references the image was tagged with by TagAndPush, removed by Terminate.
+	pushedRefs []string
+	// stopTimeout is the grace period of the container, making Terminate stop the container
+	// before removing it, instead of killing it right away.
+	stopTimeout *time.Duration
+	// requestEnv is the environment set by the request, nil if the container was not created by the provider.
+	requestEnv []string
+	// autoRemove is true when the Docker engine removes the container once it stops,
+	// so Terminate does not fail if the container does not exist anymore.
+	autoRemove         bool
 	provider           *DockerProvider
 	sessionID          string
 	terminationSignal  chan bool
@@ -83,11 +101,28 @@ type DockerContainer struct {
 
 	logProductionStop chan struct{}
 
+	// logProductionSince is the timestamp from which the logs are produced, set when the
+	// container is restarted, so the logs of the previous runs are not produced again.
+	logProductionSince string
+
 	logProductionTimeout *time.Duration
 	logger               Logging
 	lifecycleHooks       []ContainerLifecycleHooks
 
 	healthStatus string // container health status, will default to healthStatusNone if no healthcheck is present
+
+	// lifecycleState is the state of the container in the lifecycle, guarded by lifecycleStateMu
+	// because the readiness of the container could be checked from a different goroutine.
+	lifecycleState   LifecycleState
+	lifecycleStateMu sync.RWMutex
+
+	// metadata holds the in-process metadata of the container, guarded by metadataMu
+	metadata   map[string]string
+	metadataMu sync.RWMutex
+
+	// lastStats is the resource usage of the container captured right before terminating it,
+	// if the request enabled CaptureStatsOnTerminate.
+	lastStats *container.StatsResponse
 }
 
 // SetLogger sets the logger for the container
@@ -95,6 +130,59 @@ func (c *DockerContainer) SetLogger(logger Logging) {
 	c.logger = logger
 }
 
+// MetadataFilePath is the path of the file in the container where WriteMetadataFile
+// mirrors the metadata of the container, as KEY=VALUE lines.
+const MetadataFilePath = "/.testcontainers-metadata.env"
+
+// SetMetadata stores the given key and value as metadata of the container, e.g. to tag it
+// with the phase of the test using it. As Docker does not allow changing the labels of a
+// container after its creation, the metadata is only stored in-process, in this struct,
+// and it's not visible to the container unless it's mirrored with WriteMetadataFile.
+func (c *DockerContainer) SetMetadata(key string, value string) {
+	c.metadataMu.Lock()
+	defer c.metadataMu.Unlock()
+
+	if c.metadata == nil {
+		c.metadata = map[string]string{}
+	}
+	c.metadata[key] = value
+}
+
+// Metadata returns the value of the metadata of the container for the given key,
+// and whether it was set with SetMetadata.
+func (c *DockerContainer) Metadata(key string) (string, bool) {
+	c.metadataMu.RLock()
+	defer c.metadataMu.RUnlock()
+
+	value, ok := c.metadata[key]
+	return value, ok
+}
+
+// WriteMetadataFile mirrors the metadata of the container into the MetadataFilePath file
+// in the container, as KEY=VALUE lines sorted by key, replacing any previous content.
+// Keys and values cannot contain new lines, and keys cannot contain the equal sign.
+func (c *DockerContainer) WriteMetadataFile(ctx context.Context) error {
+	c.metadataMu.RLock()
+	keys := make([]string, 0, len(c.metadata))
+	for key := range c.metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		value := c.metadata[key]
+		if strings.ContainsAny(key, "=\n") || strings.Contains(value, "\n") {
+			c.metadataMu.RUnlock()
+			return fmt.Errorf("invalid metadata %q: keys cannot contain new lines nor '=', and values cannot contain new lines", key)
+		}
+		fmt.Fprintf(&buf, "%s=%s\n", key, value)
+	}
+	c.metadataMu.RUnlock()
+
+	return c.CopyToContainer(ctx, buf.Bytes(), MetadataFilePath, 0o644)
+}
+
 // SetProvider sets the provider for the container
 func (c *DockerContainer) SetProvider(provider *DockerProvider) {
 	c.provider = provider
@@ -154,6 +242,60 @@ func (c *DockerContainer) PortEndpoint(ctx context.Context, port nat.Port, proto
 	return fmt.Sprintf("%s%s:%s", protoFull, host, outerPort.Port()), nil
 }
 
+// InternalEndpoint gets proto://host:port string for the given port, to be used by other containers
+// attached to the same network, as opposed to PortEndpoint, which returns the endpoint reachable
+// from the test process, using the host of the Docker daemon and the mapped port.
+// The host is the first network alias of the container, or its IP address if it has no aliases,
+// and the port is the internal, unmapped, one. Will returns just host:port if proto is "".
+func (c *DockerContainer) InternalEndpoint(ctx context.Context, port nat.Port, proto string) (string, error) {
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	networks := inspect.NetworkSettings.Networks
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	// sort the networks so the returned host is stable across calls
+	sort.Strings(names)
+
+	host := ""
+	for _, name := range names {
+		for _, alias := range networks[name].Aliases {
+			// skip the alias Docker adds with the short container ID
+			if len(c.ID) < 12 || alias != c.ID[:12] {
+				host = alias
+				break
+			}
+		}
+		if host != "" {
+			break
+		}
+	}
+
+	if host == "" {
+		for _, name := range names {
+			if ip := networks[name].IPAddress; ip != "" {
+				host = ip
+				break
+			}
+		}
+	}
+
+	if host == "" {
+		return "", fmt.Errorf("container %s has no network alias nor IP address", c.ID)
+	}
+
+	protoFull := ""
+	if proto != "" {
+		protoFull = fmt.Sprintf("%s://", proto)
+	}
+
+	return fmt.Sprintf("%s%s:%s", protoFull, host, port.Port()), nil
+}
+
 // Host gets host (ip or name) of the docker daemon where the container port is exposed
 // Warning: this is based on your Docker host setting. Will fail if using an SSH tunnel
 // You can use the "TESTCONTAINERS_HOST_OVERRIDE" env variable to set this yourself
@@ -207,6 +349,46 @@ func (c *DockerContainer) MappedPort(ctx context.Context, port nat.Port) (nat.Po
 	return "", errors.New("port not found")
 }
 
+// MappedPortOnHostIP gets the externally mapped port for a container port, as MappedPort does,
+// but selecting the binding of the given host IP, e.g. when the port is bound to both IPv4 and IPv6
+// addresses, or to specific interfaces. An error is returned if no binding matches the host IP.
+func (c *DockerContainer) MappedPortOnHostIP(ctx context.Context, port nat.Port, hostIP string) (nat.Port, error) {
+	ip := net.ParseIP(hostIP)
+	if ip == nil {
+		return "", fmt.Errorf("invalid host IP %q", hostIP)
+	}
+
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return "", err
+	}
+	if inspect.ContainerJSONBase.HostConfig.NetworkMode == "host" {
+		return port, nil
+	}
+
+	return portOnHostIP(inspect.NetworkSettings.Ports, port, ip)
+}
+
+// portOnHostIP returns the host port of the binding of the container port to the given host IP.
+func portOnHostIP(ports nat.PortMap, port nat.Port, ip net.IP) (nat.Port, error) {
+	for k, bindings := range ports {
+		if k.Port() != port.Port() {
+			continue
+		}
+		if port.Proto() != "" && k.Proto() != port.Proto() {
+			continue
+		}
+
+		for _, binding := range bindings {
+			if ip.Equal(net.ParseIP(binding.HostIP)) {
+				return nat.NewPort(k.Proto(), binding.HostPort)
+			}
+		}
+	}
+
+	return "", fmt.Errorf("port %s not bound to host IP %s", port, ip)
+}
+
 // Deprecated: use c.Inspect(ctx).NetworkSettings.Ports instead.
 // Ports gets the exposed ports for the container.
 func (c *DockerContainer) Ports(ctx context.Context) (nat.PortMap, error) {
@@ -234,6 +416,12 @@ func (c *DockerContainer) Start(ctx context.Context) error {
 	}
 	defer c.provider.Close()
 
+	c.raw = nil // invalidate the cache, as the ports could be mapped differently after starting
+	c.setLifecycleState(func(state *LifecycleState) {
+		state.Started = true
+		state.Ready = false
+	})
+
 	err = c.startedHook(ctx)
 	if err != nil {
 		return err
@@ -249,6 +437,105 @@ func (c *DockerContainer) Start(ctx context.Context) error {
 	return nil
 }
 
+// Restart restarts the container with a single call to the Docker engine, which stops it,
+// waiting for the given timeout before killing it as Stop does, and starts it again.
+// Once restarted, the wait strategy of the container is run again, and the PostStarts and
+// PostReadies hooks are called, but not the PreStarts, PreStops and PostStops hooks, nor
+// the create hooks. Restarting a container created with AutoRemove is not supported,
+// as the Docker engine could remove it before starting it again.
+func (c *DockerContainer) Restart(ctx context.Context, timeout *time.Duration) error {
+	if c.autoRemove {
+		return fmt.Errorf("restart container %s: not supported for containers created with AutoRemove", c.ID)
+	}
+
+	var options container.StopOptions
+	if timeout != nil {
+		timeoutSeconds := int(timeout.Seconds())
+		options.Timeout = &timeoutSeconds
+	}
+
+	if err := c.provider.client.ContainerRestart(ctx, c.ID, options); err != nil {
+		return err
+	}
+	defer c.provider.Close()
+
+	c.raw = nil // invalidate the cache, as the container representation changes after restarting
+	c.setLifecycleState(func(state *LifecycleState) {
+		state.Started = true
+		state.Ready = false
+	})
+
+	if err := c.startedHook(ctx); err != nil {
+		return err
+	}
+
+	c.isRunning = true
+
+	return c.readiedHook(ctx)
+}
+
+// RestartCount returns the number of times the container has been restarted by the Docker engine,
+// because of its restart policy, e.g. after being OOM-killed. The count is reset by the Docker engine
+// when the container is restarted manually, e.g. with Restart.
+func (c *DockerContainer) RestartCount(ctx context.Context) (int, error) {
+	inspect, err := c.inspectRawContainer(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return inspect.RestartCount, nil
+}
+
+// UnixSocketPath returns the path on the host of the given Unix socket of the container, which must be
+// in a directory bind-mounted from the host, e.g. with WithUnixSocketDir, so it can be dialed from the host.
+// When the socket is in nested bind mounts, the innermost one is used. The socket is not required to exist yet.
+func (c *DockerContainer) UnixSocketPath(ctx context.Context, containerSocket string) (string, error) {
+	if !strings.HasPrefix(containerSocket, "/") {
+		return "", fmt.Errorf("container socket %q must be an absolute path", containerSocket)
+	}
+
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		hostDir string
+		rel     string
+		target  string
+	)
+	for _, m := range inspect.Mounts {
+		if m.Type != mount.TypeBind || len(m.Destination) <= len(target) {
+			continue
+		}
+
+		if r, ok := strings.CutPrefix(containerSocket, strings.TrimSuffix(m.Destination, "/")+"/"); ok && r != "" {
+			hostDir, rel, target = m.Source, r, m.Destination
+		}
+	}
+
+	if target == "" {
+		return "", fmt.Errorf("container socket %q is not in a directory bind-mounted from the host", containerSocket)
+	}
+
+	return filepath.Join(hostDir, filepath.FromSlash(rel)), nil
+}
+
+// TagAndPush tags the image of the container, e.g. an image built from a Dockerfile, with the reference,
+// and pushes it to the registry of the reference, using the given auth, if any, or else the credentials
+// for the registry in the Docker config, as DockerProvider.TagAndPushImage does. The tag is removed from
+// the Docker host when the container is terminated, unless the built image is kept, while the pushed
+// image stays in the registry.
+func (c *DockerContainer) TagAndPush(ctx context.Context, ref string, auth *registry.AuthConfig) error {
+	if err := c.provider.TagAndPushImage(ctx, c.Image, ref, auth); err != nil {
+		return err
+	}
+
+	c.pushedRefs = append(c.pushedRefs, ref)
+
+	return nil
+}
+
 // Stop will stop an already started container
 //
 // In case the container fails to stop
@@ -259,6 +546,39 @@ func (c *DockerContainer) Start(ctx context.Context) error {
 // otherwise the engine default. A negative timeout value can be specified,
 // meaning no timeout, i.e. no forceful termination is performed.
 func (c *DockerContainer) Stop(ctx context.Context, timeout *time.Duration) error {
+	return c.StopWithOptions(ctx, timeout)
+}
+
+// defaultStopGracePeriod is the time the Docker engine waits for a container to stop
+// before killing it, when neither the stop call nor the container define a timeout.
+const defaultStopGracePeriod = 10 * time.Second
+
+// StopOption is a functional option to configure how a container is stopped.
+type StopOption func(*stopOptions)
+
+// stopOptions holds the configuration applied when stopping a container.
+type stopOptions struct {
+	forcedKill        bool
+	killConfirmPeriod time.Duration
+}
+
+// WithForcedKill makes the stop escalate to a SIGKILL sent by the client if the container
+// is still running once the grace period is over, e.g. because the stop request hangs.
+// The container must then be stopped within the confirmPeriod, or an error is returned.
+func WithForcedKill(confirmPeriod time.Duration) StopOption {
+	return func(o *stopOptions) {
+		o.forcedKill = true
+		o.killConfirmPeriod = confirmPeriod
+	}
+}
+
+// StopWithOptions stops an already started container, as Stop does, applying the given options.
+func (c *DockerContainer) StopWithOptions(ctx context.Context, timeout *time.Duration, opts ...StopOption) error {
+	var stopOpts stopOptions
+	for _, opt := range opts {
+		opt(&stopOpts)
+	}
+
 	err := c.stoppingHook(ctx)
 	if err != nil {
 		return err
@@ -271,13 +591,22 @@ func (c *DockerContainer) Stop(ctx context.Context, timeout *time.Duration) erro
 		options.Timeout = &timeoutSeconds
 	}
 
-	if err := c.provider.client.ContainerStop(ctx, c.ID, options); err != nil {
+	if stopOpts.forcedKill {
+		err = c.stopOrKill(ctx, timeout, options, stopOpts.killConfirmPeriod)
+	} else {
+		err = c.provider.client.ContainerStop(ctx, c.ID, options)
+	}
+	if err != nil {
 		return err
 	}
 	defer c.provider.Close()
 
 	c.isRunning = false
 	c.raw = nil // invalidate the cache, as the container representation will change after stopping
+	c.setLifecycleState(func(state *LifecycleState) {
+		state.Started = false
+		state.Ready = false
+	})
 
 	err = c.stoppedHook(ctx)
 	if err != nil {
@@ -287,6 +616,73 @@ func (c *DockerContainer) Stop(ctx context.Context, timeout *time.Duration) erro
 	return nil
 }
 
+// stopRequestMargin is the time given to the stop request on top of the grace period,
+// so the engine can kill the container itself before the client gives up on the request.
+const stopRequestMargin = 5 * time.Second
+
+// stopOrKill stops the container, bounding the stop request to the grace period plus a margin.
+// If the request fails and the container is still running, a SIGKILL is sent, and the container
+// must stop within the confirmPeriod. A negative grace period is handled as zero.
+func (c *DockerContainer) stopOrKill(ctx context.Context, timeout *time.Duration, options container.StopOptions, confirmPeriod time.Duration) error {
+	grace := defaultStopGracePeriod
+	if timeout != nil {
+		grace = *timeout
+	} else {
+		inspect, err := c.inspectRawContainer(ctx)
+		if err != nil {
+			return err
+		}
+		if inspect.Config != nil && inspect.Config.StopTimeout != nil {
+			grace = time.Duration(*inspect.Config.StopTimeout) * time.Second
+		}
+	}
+	if grace < 0 {
+		grace = 0
+	}
+
+	stopCtx, cancel := context.WithTimeout(ctx, grace+stopRequestMargin)
+	defer cancel()
+
+	err := c.provider.client.ContainerStop(stopCtx, c.ID, options)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	// the request timed out, but the engine may have stopped the container in the meantime
+	state, err := c.State(ctx)
+	if err != nil {
+		return err
+	}
+	if !state.Running {
+		return nil
+	}
+
+	shortID := c.ID[:12]
+	c.logger.Printf("🔪 Container %s did not stop within %s, sending SIGKILL", shortID, grace)
+
+	if err := c.provider.client.ContainerKill(ctx, c.ID, "SIGKILL"); err != nil && !errdefs.IsConflict(err) {
+		return fmt.Errorf("kill container %s: %w", shortID, err)
+	}
+
+	confirmCtx, cancelConfirm := context.WithTimeout(ctx, confirmPeriod)
+	defer cancelConfirm()
+
+	waitC, errC := c.provider.client.ContainerWait(confirmCtx, c.ID, container.WaitConditionNotRunning)
+	select {
+	case <-waitC:
+		c.logger.Printf("🔪 Container %s killed", shortID)
+		return nil
+	case err := <-errC:
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("container %s did not stop within %s after SIGKILL", shortID, confirmPeriod)
+		}
+		return fmt.Errorf("wait for container %s to stop: %w", shortID, err)
+	}
+}
+
 // Terminate is used to kill the container. It is usually triggered by as defer function.
 func (c *DockerContainer) Terminate(ctx context.Context) error {
 	select {
@@ -297,13 +693,21 @@ func (c *DockerContainer) Terminate(ctx context.Context) error {
 
 	defer c.provider.client.Close()
 
-	errs := []error{
-		c.terminatingHook(ctx),
-		c.provider.client.ContainerRemove(ctx, c.GetContainerID(), container.RemoveOptions{
-			RemoveVolumes: true,
-			Force:         true,
-		}),
-		c.terminatedHook(ctx),
+	errs := []error{c.terminatingHook(ctx)}
+	if c.stopTimeout != nil {
+		errs = append(errs, c.stopGracefully(ctx, *c.stopTimeout))
+	}
+	errs = append(errs, c.remove(ctx), c.terminatedHook(ctx))
+
+	if !c.keepBuiltImage {
+		// removing a tag of an image with other tags only untags it
+		for _, ref := range c.pushedRefs {
+			_, err := c.provider.client.ImageRemove(ctx, ref, image.RemoveOptions{})
+			if err != nil && !errdefs.IsNotFound(err) {
+				errs = append(errs, err)
+			}
+		}
+		c.pushedRefs = nil
 	}
 
 	if c.imageWasBuilt && !c.keepBuiltImage {
@@ -317,14 +721,176 @@ func (c *DockerContainer) Terminate(ctx context.Context) error {
 	c.sessionID = ""
 	c.isRunning = false
 	c.raw = nil // invalidate the cache here too
+	c.setLifecycleState(func(state *LifecycleState) {
+		*state = LifecycleState{}
+	})
 	return errors.Join(errs...)
 }
 
+// stopGracefully stops the container within the grace period, before it's removed, so it can
+// shut down cleanly. The stop hooks are not called, as the container is being terminated.
+func (c *DockerContainer) stopGracefully(ctx context.Context, grace time.Duration) error {
+	timeoutSeconds := stopTimeoutSeconds(grace)
+	err := c.provider.client.ContainerStop(ctx, c.ID, container.StopOptions{Timeout: &timeoutSeconds})
+	if err != nil && !errdefs.IsNotFound(err) {
+		return fmt.Errorf("stop container: %w", err)
+	}
+
+	return nil
+}
+
+// connectToReaperNetwork attaches the created container to the network of the reaper.
+// If it fails, the container is removed, as it's not returned to the caller to terminate it.
+func (p *DockerProvider) connectToReaperNetwork(ctx context.Context, containerID string) error {
+	err := p.client.NetworkConnect(ctx, p.reaperNetwork, containerID, nil)
+	if err == nil {
+		return nil
+	}
+
+	err = fmt.Errorf("connect container to the reaper network %s: %w", p.reaperNetwork, err)
+	if rmErr := p.client.ContainerRemove(ctx, containerID, container.RemoveOptions{RemoveVolumes: true, Force: true}); rmErr != nil {
+		return errors.Join(err, fmt.Errorf("remove container: %w", rmErr))
+	}
+
+	return err
+}
+
+// stopTimeoutSeconds converts the grace period to the whole seconds expected by the engine,
+// rounding it up so a sub-second grace period is not turned into an immediate kill.
+// A negative grace period means no timeout.
+func stopTimeoutSeconds(grace time.Duration) int {
+	if grace < 0 {
+		return -1
+	}
+
+	return int(math.Ceil(grace.Seconds()))
+}
+
+// stopTimeoutOf returns the grace period set in the config of the container,
+// or nil if it keeps the engine default. A negative timeout, which makes the engine
+// wait forever, is ignored so terminating the container cannot hang.
+func stopTimeoutOf(config *container.Config) *time.Duration {
+	if config == nil || config.StopTimeout == nil || *config.StopTimeout <= 0 {
+		return nil
+	}
+
+	timeout := time.Duration(*config.StopTimeout) * time.Second
+	return &timeout
+}
+
+// remove removes the container and its volumes. If the container is automatically removed
+// by the Docker engine once stopped, it's not an error that the container does not exist
+// anymore, or that its removal is already in progress.
+func (c *DockerContainer) remove(ctx context.Context) error {
+	err := c.provider.client.ContainerRemove(ctx, c.GetContainerID(), container.RemoveOptions{
+		RemoveVolumes: true,
+		Force:         true,
+	})
+	if err != nil && c.autoRemove && (errdefs.IsNotFound(err) || errdefs.IsConflict(err)) {
+		return nil
+	}
+
+	return err
+}
+
+// LastStats returns the resource usage of the container captured right before it was terminated,
+// e.g. to report its memory and CPU usage, or nil if it was not captured.
+// The capture is only done if the container request enabled CaptureStatsOnTerminate.
+func (c *DockerContainer) LastStats() *container.StatsResponse {
+	return c.lastStats
+}
+
+// captureStats stores a snapshot of the resource usage of the container.
+func (c *DockerContainer) captureStats(ctx context.Context) error {
+	stats, err := c.Stats(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.lastStats = stats
+	return nil
+}
+
+// Stats returns a snapshot of the resource usage of the container, such as its memory and CPU usage.
+// As the snapshot is taken without streaming, the CPU usage of the previous read is not populated,
+// so use StatsStream to calculate the CPU usage between two reads.
+func (c *DockerContainer) Stats(ctx context.Context) (*container.StatsResponse, error) {
+	resp, err := c.provider.client.ContainerStatsOneShot(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("container stats: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var stats container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("decode container stats: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// StatsStream streams the resource usage of the container, sending a snapshot to the returned channel
+// every second, as reported by the Docker engine. The channel is closed when the context is done,
+// when the container exits, or if a snapshot cannot be decoded.
+func (c *DockerContainer) StatsStream(ctx context.Context) (<-chan container.StatsResponse, error) {
+	resp, err := c.provider.client.ContainerStats(ctx, c.ID, true)
+	if err != nil {
+		return nil, fmt.Errorf("container stats: %w", err)
+	}
+
+	ch := make(chan container.StatsResponse)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		// the request is bound to the context, so the decoding fails once it's done
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var stats container.StatsResponse
+			if err := dec.Decode(&stats); err != nil {
+				return
+			}
+
+			select {
+			case ch <- stats:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// ContainerNotFoundError is returned when the container does not exist anymore in the Docker engine,
+// e.g. because it was removed once stopped, as requested by the AutoRemove option of its host config.
+type ContainerNotFoundError struct {
+	ID  string // the ID of the container
+	Err error  // the error returned by the Docker engine
+}
+
+// Error implements the error interface.
+func (e *ContainerNotFoundError) Error() string {
+	return fmt.Sprintf("container %s not found: %v", e.ID, e.Err)
+}
+
+// Unwrap returns the error returned by the Docker engine.
+func (e *ContainerNotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// NotFound implements the not found error of the Docker errdefs package, so errdefs.IsNotFound
+// reports true for the error.
+func (e *ContainerNotFoundError) NotFound() {}
+
 // update container raw info
 func (c *DockerContainer) inspectRawContainer(ctx context.Context) (*types.ContainerJSON, error) {
 	defer c.provider.Close()
 	inspect, err := c.provider.client.ContainerInspect(ctx, c.ID)
 	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil, &ContainerNotFoundError{ID: c.ID, Err: err}
+		}
 		return nil, err
 	}
 
@@ -386,6 +952,46 @@ func (c *DockerContainer) Logs(ctx context.Context) (io.ReadCloser, error) {
 	return pr, nil
 }
 
+// LogsText fetches the logs of the container, returning them as plain text, where the
+// stdout and stderr streams are combined and the Docker stream framing is removed.
+// If neither stdout nor stderr are requested in the options, both of them are returned.
+// The Follow option is not supported, as the logs are read until the end.
+func (c *DockerContainer) LogsText(ctx context.Context, opts container.LogsOptions) (string, error) {
+	if opts.Follow {
+		return "", errors.New("follow is not supported when reading the logs as text")
+	}
+
+	if !opts.ShowStdout && !opts.ShowStderr {
+		opts.ShowStdout = true
+		opts.ShowStderr = true
+	}
+
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	rc, err := c.provider.client.ContainerLogs(ctx, c.ID, opts)
+	if err != nil {
+		return "", err
+	}
+	defer c.provider.Close()
+	defer rc.Close()
+
+	var buf bytes.Buffer
+	if inspect.Config != nil && inspect.Config.Tty {
+		// with a TTY, the logs are not multiplexed
+		_, err = io.Copy(&buf, rc)
+	} else {
+		_, err = stdcopy.StdCopy(&buf, &buf, rc)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read logs: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
 // Deprecated: use the ContainerRequest.LogConsumerConfig field instead.
 func (c *DockerContainer) FollowOutput(consumer LogConsumer) {
 	c.followOutput(consumer)
@@ -397,14 +1003,14 @@ func (c *DockerContainer) followOutput(consumer LogConsumer) {
 	c.consumers = append(c.consumers, consumer)
 }
 
-// Deprecated: use c.Inspect(ctx).Name instead.
-// Name gets the name of the container.
+// Name gets the current name of the container, without the leading slash.
+// This method does not use the cache, so it reflects the renames of the container.
 func (c *DockerContainer) Name(ctx context.Context) (string, error) {
-	inspect, err := c.Inspect(ctx)
+	inspect, err := c.inspectRawContainer(ctx)
 	if err != nil {
 		return "", err
 	}
-	return inspect.Name, nil
+	return strings.TrimPrefix(inspect.Name, "/"), nil
 }
 
 // State returns container's running state. This method does not use the cache
@@ -438,6 +1044,36 @@ func (c *DockerContainer) Networks(ctx context.Context) ([]string, error) {
 	return n, nil
 }
 
+// ConnectToNetwork attaches the container to a network, by ID or name, with the given aliases,
+// e.g. to reattach a node of a cluster after a partition test. The container can be running.
+// The cached inspection of the container is refreshed, so it reflects the new network settings.
+func (c *DockerContainer) ConnectToNetwork(ctx context.Context, networkID string, aliases ...string) error {
+	err := c.provider.client.NetworkConnect(ctx, networkID, c.ID, &network.EndpointSettings{
+		Aliases: aliases,
+	})
+	if err != nil {
+		return fmt.Errorf("connect container %s to network %s: %w", c.ID, networkID, err)
+	}
+	defer c.provider.Close()
+
+	_, err = c.inspectRawContainer(ctx)
+	return err
+}
+
+// DisconnectFromNetwork detaches the container from a network, by ID or name, e.g. to isolate a node
+// of a cluster in a partition test. The container can be running.
+// The cached inspection of the container is refreshed, so it reflects the new network settings.
+func (c *DockerContainer) DisconnectFromNetwork(ctx context.Context, networkID string) error {
+	err := c.provider.client.NetworkDisconnect(ctx, networkID, c.ID, false)
+	if err != nil {
+		return fmt.Errorf("disconnect container %s from network %s: %w", c.ID, networkID, err)
+	}
+	defer c.provider.Close()
+
+	_, err = c.inspectRawContainer(ctx)
+	return err
+}
+
 // ContainerIP gets the IP address of the primary network within the container.
 func (c *DockerContainer) ContainerIP(ctx context.Context) (string, error) {
 	inspect, err := c.Inspect(ctx)
@@ -476,6 +1112,55 @@ func (c *DockerContainer) ContainerIPs(ctx context.Context) ([]string, error) {
 	return ips, nil
 }
 
+// GatewayIP gets the IP address of the gateway of the container's network, which the container
+// can use to reach the host, e.g. to call back to a server started by the test. If the container
+// is attached to multiple networks, the gateway of the first one, sorted by name, is returned.
+// Containers using the host network mode share the network stack of the host, so there is no
+// gateway and an empty string is returned: they can reach the host at localhost instead.
+func (c *DockerContainer) GatewayIP(ctx context.Context) (string, error) {
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if inspect.HostConfig != nil && inspect.HostConfig.NetworkMode.IsHost() {
+		return "", nil
+	}
+
+	if gateway := inspect.NetworkSettings.Gateway; gateway != "" {
+		return gateway, nil
+	}
+
+	networks := inspect.NetworkSettings.Networks
+	names := make([]string, 0, len(networks))
+	for name := range networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if gateway := networks[name].Gateway; gateway != "" {
+			return gateway, nil
+		}
+	}
+
+	// fall back to the gateway defined in the IPAM config of the networks
+	for _, name := range names {
+		nw, err := c.provider.client.NetworkInspect(ctx, networks[name].NetworkID, network.InspectOptions{})
+		if err != nil {
+			return "", err
+		}
+
+		for _, cfg := range nw.IPAM.Config {
+			if cfg.Gateway != "" {
+				return cfg.Gateway, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no gateway found for container %s", c.ID)
+}
+
 // NetworkAliases gets the aliases of the container for the networks it is attached to.
 func (c *DockerContainer) NetworkAliases(ctx context.Context) (map[string][]string, error) {
 	inspect, err := c.Inspect(ctx)
@@ -517,19 +1202,71 @@ func (c *DockerContainer) Exec(ctx context.Context, cmd []string, options ...tce
 		return 0, nil, err
 	}
 
-	hijack, err := cli.ContainerExecAttach(ctx, response.ID, container.ExecAttachOptions{})
+	hijack, err := cli.ContainerExecAttach(ctx, response.ID, container.ExecAttachOptions{
+		Tty: processOptions.ExecConfig.Tty,
+	})
 	if err != nil {
 		return 0, nil, err
 	}
 
+	processOptions.ExecID = response.ID
 	processOptions.Reader = hijack.Reader
 
+	// the stdin is written in the background, as the command could block writing its output
+	// until it's read, which the multiplexed option does, or otherwise the output is buffered below.
+	var stdinDone chan error
+	if processOptions.Stdin != nil {
+		stdinDone = make(chan error, 1)
+		go func() {
+			err := copyExecStdin(hijack.Conn, processOptions.Stdin)
+			// closing the write half signals EOF to the command
+			stdinDone <- errors.Join(err, hijack.CloseWrite())
+		}()
+	}
+
 	// second loop to process the multiplexed option, as now we have a reader
 	// from the created exec response.
 	for _, o := range options {
 		o.Apply(processOptions)
 	}
 
+	if stdinDone != nil {
+		// without the multiplexed option nothing reads the output yet, so it's buffered until
+		// the command exits, otherwise a command echoing its input blocks, and the stdin with it
+		var outputDone chan error
+		if processOptions.Reader == io.Reader(hijack.Reader) {
+			var output bytes.Buffer
+			outputDone = make(chan error, 1)
+			go func() {
+				_, err := io.Copy(&output, hijack.Reader)
+				outputDone <- err
+			}()
+			processOptions.Reader = &output
+		}
+
+		select {
+		case err := <-stdinDone:
+			if err != nil {
+				return 0, nil, fmt.Errorf("write stdin: %w", err)
+			}
+		case <-ctx.Done():
+			hijack.Close()
+			return 0, nil, ctx.Err()
+		}
+
+		if outputDone != nil {
+			select {
+			case err := <-outputDone:
+				if err != nil {
+					return 0, nil, fmt.Errorf("read output: %w", err)
+				}
+			case <-ctx.Done():
+				hijack.Close()
+				return 0, nil, ctx.Err()
+			}
+		}
+	}
+
 	var exitCode int
 	for {
 		execResp, err := cli.ContainerExecInspect(ctx, response.ID)
@@ -545,68 +1282,271 @@ func (c *DockerContainer) Exec(ctx context.Context, cmd []string, options ...tce
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	return exitCode, processOptions.Reader, nil
-}
-
-type FileFromContainer struct {
-	underlying *io.ReadCloser
-	tarreader  *tar.Reader
+	return exitCode, processOptions.Reader, nil
+}
+
+// AttachStdin attaches to the stdin of the main process of the container, writing the content of
+// the reader to it, and closes the stdin once the reader is drained, so processes reading their
+// input until EOF, e.g. "cat", can complete. The container must be created with the OpenStdin
+// field of the request, and its stdin can only be written once, as it's closed afterwards.
+func (c *DockerContainer) AttachStdin(ctx context.Context, r io.Reader) error {
+	inspect, err := c.inspectRawContainer(ctx)
+	if err != nil {
+		return err
+	}
+
+	if inspect.Config == nil || !inspect.Config.OpenStdin {
+		return fmt.Errorf("attach stdin of container %s: the container was not created with OpenStdin", c.ID)
+	}
+
+	resp, err := c.provider.client.ContainerAttach(ctx, c.ID, container.AttachOptions{
+		Stream: true,
+		Stdin:  true,
+	})
+	if err != nil {
+		return fmt.Errorf("attach stdin of container %s: %w", c.ID, err)
+	}
+	defer resp.Close()
+
+	if err := copyExecStdin(resp.Conn, r); err != nil {
+		return fmt.Errorf("write stdin of container %s: %w", c.ID, err)
+	}
+
+	// closing the write side of the connection closes the stdin of the container
+	return resp.CloseWrite()
+}
+
+// copyExecStdin writes the content of the reader to the stdin of a command until the reader is drained,
+// returning an error only if it fails reading. Failing to write is not an error, as the command could
+// exit without consuming its whole input.
+func copyExecStdin(w io.Writer, r io.Reader) error {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if _, werr := w.Write(buf[:n]); werr != nil {
+				return nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// execOutputPollInterval is the interval between the executions of the command in WaitForExecOutput.
+const execOutputPollInterval = 100 * time.Millisecond
+
+// WaitForExecOutput repeatedly executes the command in the container until its stdout matches
+// the regular expression, regardless of its exit code, e.g. to poll a tool reporting the status
+// of a cluster until a node is up. An error is returned if the output does not match within the timeout.
+func (c *DockerContainer) WaitForExecOutput(ctx context.Context, cmd []string, re *regexp.Regexp, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastOutput string
+	for {
+		_, reader, err := c.Exec(ctx, cmd)
+		if err == nil {
+			// the output of an exec without a TTY is multiplexed
+			var stdout bytes.Buffer
+			if _, err = stdcopy.StdCopy(&stdout, io.Discard, reader); err == nil {
+				lastOutput = stdout.String()
+				if re.MatchString(lastOutput) {
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%w: exec %q: %w", ctx.Err(), cmd, err)
+			}
+			return fmt.Errorf("%w: output of %q does not match %q, last output: %q", ctx.Err(), cmd, re, lastOutput)
+		case <-time.After(execOutputPollInterval):
+		}
+	}
+}
+
+// ResizeExecTTY resizes the TTY of the exec with the given ID, which is created
+// running the Exec method with the [tcexec.WithTTY] option. The ID of the exec
+// is obtained with the [tcexec.WithExecIDHandler] option.
+func (c *DockerContainer) ResizeExecTTY(ctx context.Context, execID string, height, width uint) error {
+	err := c.provider.client.ContainerExecResize(ctx, execID, container.ResizeOptions{
+		Height: height,
+		Width:  width,
+	})
+	if err != nil {
+		return fmt.Errorf("resize exec TTY: %w", err)
+	}
+
+	return nil
+}
+
+type FileFromContainer struct {
+	underlying *io.ReadCloser
+	tarreader  *tar.Reader
+}
+
+func (fc *FileFromContainer) Read(b []byte) (int, error) {
+	return (*fc.tarreader).Read(b)
+}
+
+func (fc *FileFromContainer) Close() error {
+	return (*fc.underlying).Close()
+}
+
+func (c *DockerContainer) CopyFileFromContainer(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	r, _, err := c.provider.client.CopyFromContainer(ctx, c.ID, filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer c.provider.Close()
+
+	tarReader := tar.NewReader(r)
+
+	// if we got here we have exactly one file in the TAR-stream
+	// so we advance the index by one so the next call to Read will start reading it
+	_, err = tarReader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &FileFromContainer{
+		underlying: &r,
+		tarreader:  tarReader,
+	}
+
+	return ret, nil
+}
+
+// CopyFileFromContainerToHost copies a file of the container to a path of the host, streaming it
+// to disk without buffering it in memory, which suits large files, e.g. the artifacts of a build.
+// The file at the host path is created or truncated, and its permissions are set to the given mode.
+// It fails if the path of the container is a directory.
+func (c *DockerContainer) CopyFileFromContainerToHost(ctx context.Context, containerPath string, hostPath string, mode os.FileMode) error {
+	r, stat, err := c.provider.client.CopyFromContainer(ctx, c.ID, containerPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if stat.Mode.IsDir() {
+		return fmt.Errorf("path %s is a directory in the container", containerPath)
+	}
+
+	return extractTarFile(r, containerPath, hostPath, mode)
+}
+
+// extractTarFile writes the first regular file of the tar stream to the host path, with the given mode.
+// The host file is removed if the copy fails.
+func extractTarFile(r io.Reader, containerPath string, hostPath string, mode os.FileMode) (err error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return fmt.Errorf("no regular file found at path %s in the container", containerPath)
+			}
+			return fmt.Errorf("read tar: %w", err)
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			return fmt.Errorf("path %s is a directory in the container", containerPath)
+		}
+
+		if hdr.Typeflag == tar.TypeReg {
+			break
+		}
+	}
+
+	f, err := os.OpenFile(hostPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(hostPath)
+		}
+	}()
+
+	if _, err = io.Copy(f, tr); err != nil {
+		f.Close()
+		return fmt.Errorf("copy file: %w", err)
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	// the mode is set explicitly, as the umask applies when creating the file, and it's kept when truncating it
+	return os.Chmod(hostPath, mode)
 }
 
-func (fc *FileFromContainer) Read(b []byte) (int, error) {
-	return (*fc.tarreader).Read(b)
+// CopyDirToContainer copies the contents of a directory to a parent path in the container. This parent path must exist in the container first
+// as we cannot create it. Symlinks are copied as symlinks.
+func (c *DockerContainer) CopyDirToContainer(ctx context.Context, hostDirPath string, containerParentPath string, fileMode int64) error {
+	return c.CopyDirToContainerWithOptions(ctx, hostDirPath, containerParentPath, fileMode)
 }
 
-func (fc *FileFromContainer) Close() error {
-	return (*fc.underlying).Close()
-}
+// CopyDirToContainerWithOptions works like CopyDirToContainer, configuring the copy with the given options,
+// e.g. WithDereferencedSymlinks to copy the content the symlinks point to instead of the symlinks themselves.
+func (c *DockerContainer) CopyDirToContainerWithOptions(ctx context.Context, hostDirPath string, containerParentPath string, fileMode int64, opts ...CopyOption) error {
+	var options copyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
 
-func (c *DockerContainer) CopyFileFromContainer(ctx context.Context, filePath string) (io.ReadCloser, error) {
-	r, _, err := c.provider.client.CopyFromContainer(ctx, c.ID, filePath)
+	dir, err := isDir(hostDirPath)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer c.provider.Close()
 
-	tarReader := tar.NewReader(r)
+	if !dir {
+		// it's not a dir: let the consumer to handle an error
+		return fmt.Errorf("path %s is not a directory", hostDirPath)
+	}
 
-	// if we got here we have exactly one file in the TAR-stream
-	// so we advance the index by one so the next call to Read will start reading it
-	_, err = tarReader.Next()
+	buff, err := tarDir(hostDirPath, fileMode, options)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	ret := &FileFromContainer{
-		underlying: &r,
-		tarreader:  tarReader,
+	// create the directory under its parent
+	parent := filepath.Dir(containerParentPath)
+
+	err = c.provider.client.CopyToContainer(ctx, c.ID, parent, buff, container.CopyToContainerOptions{})
+	if err != nil {
+		return err
 	}
+	defer c.provider.Close()
 
-	return ret, nil
+	return nil
 }
 
-// CopyDirToContainer copies the contents of a directory to a parent path in the container. This parent path must exist in the container first
-// as we cannot create it
-func (c *DockerContainer) CopyDirToContainer(ctx context.Context, hostDirPath string, containerParentPath string, fileMode int64) error {
+// copyDirContentsToContainer copies the contents of the host directory into the container directory,
+// which is created if needed, instead of copying the host directory itself.
+func (c *DockerContainer) copyDirContentsToContainer(ctx context.Context, hostDirPath string, containerDirPath string, fileMode int64) error {
 	dir, err := isDir(hostDirPath)
 	if err != nil {
 		return err
 	}
 
 	if !dir {
-		// it's not a dir: let the consumer to handle an error
 		return fmt.Errorf("path %s is not a directory", hostDirPath)
 	}
 
-	buff, err := tarDir(hostDirPath, fileMode)
+	// name the entries after the container directory, relative to the root
+	buff, err := tarDirAs(hostDirPath, strings.TrimPrefix(path.Clean("/"+containerDirPath), "/"), fileMode, copyOptions{})
 	if err != nil {
 		return err
 	}
 
-	// create the directory under its parent
-	parent := filepath.Dir(containerParentPath)
-
-	err = c.provider.client.CopyToContainer(ctx, c.ID, parent, buff, container.CopyToContainerOptions{})
+	err = c.provider.client.CopyToContainer(ctx, c.ID, "/", buff, container.CopyToContainerOptions{})
 	if err != nil {
 		return err
 	}
@@ -658,8 +1598,81 @@ func (c *DockerContainer) CopyToContainer(ctx context.Context, fileContent []byt
 	}, int64(len(fileContent)), containerFilePath, fileMode)
 }
 
-func (c *DockerContainer) copyToContainer(ctx context.Context, fileContent func(tw io.Writer) error, fileContentSize int64, containerFilePath string, fileMode int64) error {
-	buffer, err := tarFile(containerFilePath, fileContent, fileContentSize, fileMode)
+// Top lists the processes running in the container, returning the titles of the columns
+// and a row for each process. The ps arguments, e.g. "-ef", are passed to the ps command
+// run by the Docker engine, which uses "-ef" by default.
+func (c *DockerContainer) Top(ctx context.Context, psArgs ...string) (container.ContainerTopOKBody, error) {
+	top, err := c.provider.client.ContainerTop(ctx, c.ID, psArgs)
+	if err != nil {
+		return container.ContainerTopOKBody{}, fmt.Errorf("container top: %w", err)
+	}
+	defer c.provider.Close()
+
+	return top, nil
+}
+
+// Diff returns the changes in the filesystem of the container, relative to its image,
+// i.e. the added, modified and deleted paths, which is handy to check which files a command produced.
+func (c *DockerContainer) Diff(ctx context.Context) ([]container.FilesystemChange, error) {
+	changes, err := c.provider.client.ContainerDiff(ctx, c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("container diff: %w", err)
+	}
+	defer c.provider.Close()
+
+	return changes, nil
+}
+
+// CopyTarToContainer copies the content of an uncompressed or gzip'ed tar stream into the given
+// directory of the container, without re-archiving it, so it can be streamed from any source
+// with no need to buffer it on disk. The destination directory must exist in the container.
+func (c *DockerContainer) CopyTarToContainer(ctx context.Context, tarStream io.Reader, destDir string) error {
+	stat, err := c.provider.client.ContainerStatPath(ctx, c.ID, destDir)
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", destDir, err)
+	}
+
+	if !stat.Mode.IsDir() {
+		return fmt.Errorf("path %s is not a directory", destDir)
+	}
+
+	err = c.provider.client.CopyToContainer(ctx, c.ID, destDir, tarStream, container.CopyToContainerOptions{})
+	if err != nil {
+		return err
+	}
+	defer c.provider.Close()
+
+	return nil
+}
+
+// CopyArchiveToContainer extracts the archive at the host path, a .tar, .tar.gz, .tgz or .zip file detected
+// by its extension, into the given directory of the container, e.g. to seed a large dataset. The archive is
+// validated before copying anything, failing if it's corrupted or if any of its entries would be extracted
+// outside of the directory. The destination directory must exist in the container.
+func (c *DockerContainer) CopyArchiveToContainer(ctx context.Context, hostArchivePath string, destDir string) error {
+	// the archive is converted to a tar file on disk, as it can be too large to be buffered in memory
+	tmp, err := os.CreateTemp("", "testcontainers-archive-*.tar")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if err := archiveToTar(hostArchivePath, tmp); err != nil {
+		return fmt.Errorf("invalid archive %s: %w", hostArchivePath, err)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return c.CopyTarToContainer(ctx, tmp, destDir)
+}
+
+func (c *DockerContainer) copyToContainer(ctx context.Context, fileContent func(tw io.Writer) error, fileContentSize int64, containerFilePath string, fileMode int64, hdrModifiers ...func(hdr *tar.Header)) error {
+	buffer, err := tarFile(containerFilePath, fileContent, fileContentSize, fileMode, hdrModifiers...)
 	if err != nil {
 		return err
 	}
@@ -673,6 +1686,71 @@ func (c *DockerContainer) copyToContainer(ctx context.Context, fileContent func(
 	return nil
 }
 
+// secretFileMode is the mode of the secret files, only readable by their owner.
+const secretFileMode = 0o400
+
+// copySecretToContainer copies the secret content into the container at the given path, only readable
+// by the user of the container when it's numeric, e.g. "1000" or "1000:1000", or by root otherwise.
+// The content is never logged, and it's scrubbed from the returned errors.
+func (c *DockerContainer) copySecretToContainer(ctx context.Context, content []byte, containerFilePath string) error {
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return fmt.Errorf("inspect container: %w", err)
+	}
+
+	uid, gid, numeric := numericUser(inspect.Config.User)
+	if !numeric {
+		c.logger.Printf("⚠️ The user %q of container %s is not numeric, the secret file %s is owned by root", inspect.Config.User, c.ID[:12], containerFilePath)
+	}
+
+	err = c.copyToContainer(ctx, func(tw io.Writer) error {
+		_, err := tw.Write(content)
+		return err
+	}, int64(len(content)), containerFilePath, secretFileMode, func(hdr *tar.Header) {
+		hdr.Uid, hdr.Gid = uid, gid
+	})
+	if err != nil {
+		return scrubSecret(fmt.Errorf("copy secret file to %s: %w", containerFilePath, err), content)
+	}
+
+	return nil
+}
+
+// numericUser parses a "uid[:gid]" user, returning false if it's empty or not numeric.
+func numericUser(user string) (int, int, bool) {
+	if user == "" {
+		return 0, 0, false
+	}
+
+	uidStr, gidStr, hasGid := strings.Cut(user, ":")
+
+	uid, err := strconv.Atoi(uidStr)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	if !hasGid {
+		return uid, 0, true
+	}
+
+	gid, err := strconv.Atoi(gidStr)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return uid, gid, true
+}
+
+// scrubSecret replaces the secret content in the error message, if present,
+// so it can't leak into the logs of the tests.
+func scrubSecret(err error, secret []byte) error {
+	if len(secret) == 0 || !strings.Contains(err.Error(), string(secret)) {
+		return err
+	}
+
+	return errors.New(strings.ReplaceAll(err.Error(), string(secret), "[REDACTED]"))
+}
+
 type LogProductionOption func(*DockerContainer)
 
 // WithLogProductionTimeout is a functional option that sets the timeout for the log production.
@@ -726,7 +1804,7 @@ func (c *DockerContainer) startLogProduction(ctx context.Context, opts ...LogPro
 			c.logProductionWaitGroup.Done()
 		}()
 
-		since := ""
+		since := c.logProductionSince
 		// if the socket is closed we will make additional logs request with updated Since timestamp
 	BEGIN:
 		options := container.LogsOptions{
@@ -844,6 +1922,8 @@ type DockerNetwork struct {
 }
 
 // Remove is used to remove the network. It is usually triggered by as defer function.
+// The reaper is detached from the network first, as it could have been attached to it
+// by the WithReaperNetwork option, and it outlives the network.
 func (n *DockerNetwork) Remove(ctx context.Context) error {
 	select {
 	// close reaper if it was created
@@ -853,9 +1933,45 @@ func (n *DockerNetwork) Remove(ctx context.Context) error {
 
 	defer n.provider.Close()
 
+	if err := n.disconnectReaper(ctx); err != nil {
+		return err
+	}
+
 	return n.provider.client.NetworkRemove(ctx, n.ID)
 }
 
+// disconnectReaper disconnects the reaper containers attached to the network, if any.
+func (n *DockerNetwork) disconnectReaper(ctx context.Context) error {
+	nw, err := n.provider.client.NetworkInspect(ctx, n.ID, network.InspectOptions{})
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("inspect network %s: %w", n.Name, err)
+	}
+
+	for id := range nw.Containers {
+		inspect, err := n.provider.client.ContainerInspect(ctx, id)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("inspect container %s: %w", id, err)
+		}
+
+		if inspect.Config == nil || inspect.Config.Labels[core.LabelReaper] != "true" {
+			continue
+		}
+
+		err = n.provider.client.NetworkDisconnect(ctx, n.ID, id, true)
+		if err != nil && !errdefs.IsNotFound(err) {
+			return fmt.Errorf("disconnect reaper from network %s: %w", n.Name, err)
+		}
+	}
+
+	return nil
+}
+
 func (n *DockerNetwork) SetTerminationSignal(signal chan bool) {
 	n.terminationSignal = signal
 }
@@ -897,6 +2013,21 @@ func (p *DockerProvider) BuildImage(ctx context.Context, img ImageBuildInfo) (st
 		return "", err
 	}
 
+	if s, ok := img.(buildSecretsInfo); ok && len(s.GetBuildSecrets()) > 0 {
+		starter := s.GetBuildSessionStarter()
+		if starter == nil {
+			return "", errors.New("build secrets require a build session starter")
+		}
+
+		sess, err := p.startBuildSession(ctx, s.GetBuildSecrets(), starter)
+		if err != nil {
+			return "", err
+		}
+		defer sess.Close()
+
+		buildOptions.SessionID = sess.ID()
+	}
+
 	var buildError error
 	var resp types.ImageBuildResponse
 	err = backoff.Retry(func() error {
@@ -917,17 +2048,7 @@ func (p *DockerProvider) BuildImage(ctx context.Context, img ImageBuildInfo) (st
 		return "", errors.Join(buildError, err)
 	}
 
-	if img.ShouldPrintBuildLog() {
-		termFd, isTerm := term.GetFdInfo(os.Stderr)
-		err = jsonmessage.DisplayJSONMessagesStream(resp.Body, os.Stderr, termFd, isTerm, nil)
-		if err != nil {
-			return "", err
-		}
-	}
-
-	// need to read the response from Docker, I think otherwise the image
-	// might not finish building before continuing to execute here
-	_, err = io.Copy(io.Discard, resp.Body)
+	err = readBuildOutput(resp.Body, img)
 	if err != nil {
 		return "", err
 	}
@@ -1042,7 +2163,11 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 
 		var shouldPullImage bool
 
-		if req.AlwaysPullImage {
+		if req.ImageLocalOnly && isImageDigest(imageName) {
+			if err := p.checkLocalImage(ctx, imageName, platform); err != nil {
+				return nil, err
+			}
+		} else if req.AlwaysPullImage {
 			shouldPullImage = true // If requested always attempt to pull image
 		} else {
 			img, _, err := p.client.ImageInspectWithRaw(ctx, imageName)
@@ -1084,12 +2209,19 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 		Hostname:   req.Hostname,
 		User:       req.User,
 		WorkingDir: req.WorkingDir,
+		OpenStdin:  req.OpenStdin,
+		StdinOnce:  req.OpenStdin,
+	}
+
+	if req.StopTimeout != nil && *req.StopTimeout != 0 {
+		// the engine uses it when the container is stopped without a timeout, e.g. by Stop(ctx, nil)
+		timeoutSeconds := stopTimeoutSeconds(*req.StopTimeout)
+		dockerInput.StopTimeout = &timeoutSeconds
 	}
 
 	hostConfig := &container.HostConfig{
 		Privileged: req.Privileged,
 		ShmSize:    req.ShmSize,
-		Tmpfs:      req.Tmpfs,
 	}
 
 	networkingConfig := &network.NetworkingConfig{}
@@ -1101,6 +2233,9 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 		defaultCopyFileToContainerHook(req.Files),
 		defaultLogConsumersHook(req.LogConsumerCfg),
 		defaultReadinessHook(),
+		defaultExtractFilesHook(req.ExtractFiles),
+		defaultStatsCaptureHook(req.CaptureStatsOnTerminate),
+		defaultStopStrategyHook(req.StopStrategy),
 	}
 
 	// in the case the container needs to access a local port
@@ -1139,25 +2274,45 @@ func (p *DockerProvider) CreateContainer(ctx context.Context, req ContainerReque
 				endpointSetting := network.EndpointSettings{
 					Aliases: req.NetworkAliases[n],
 				}
+				if ip, ok := req.NetworkStaticIPs[n]; ok {
+					endpointSetting.IPAMConfig, err = staticIPAMConfig(nw, ip)
+					if err != nil {
+						return nil, err
+					}
+				}
 				err = p.client.NetworkConnect(ctx, nw.ID, resp.ID, &endpointSetting)
 				if err != nil {
 					return nil, err
 				}
+			} else if _, ok := req.NetworkStaticIPs[n]; ok {
+				return nil, fmt.Errorf("get network %s for the static IP: %w", n, err)
 			}
 		}
 	}
 
+	// attach the container to the network of the reaper, so they share it, unless
+	// its network mode does not allow it, e.g. "host", "none" or "container:<id>"
+	if p.reaperNetwork != "" && !slices.Contains(req.Networks, p.reaperNetwork) && allowsExtraNetworks(hostConfig.NetworkMode) {
+		if err := p.connectToReaperNetwork(ctx, resp.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	c := &DockerContainer{
 		ID:                resp.ID,
 		WaitingFor:        req.WaitingFor,
 		Image:             imageName,
 		imageWasBuilt:     req.ShouldBuildImage(),
 		keepBuiltImage:    req.ShouldKeepBuiltImage(),
+		autoRemove:        hostConfig.AutoRemove,
+		stopTimeout:       stopTimeoutOf(dockerInput),
+		requestEnv:        dockerInput.Env,
 		sessionID:         core.SessionID(),
 		provider:          p,
 		terminationSignal: termSignal,
 		logger:            p.Logger,
 		lifecycleHooks:    req.LifecycleHooks,
+		lifecycleState:    LifecycleState{Created: true},
 	}
 
 	err = c.createdHook(ctx)
@@ -1250,6 +2405,9 @@ func (p *DockerProvider) ReuseOrCreateContainer(ctx context.Context, req Contain
 		DefaultLoggingHook(p.Logger),
 		defaultReadinessHook(),
 		defaultLogConsumersHook(req.LogConsumerCfg),
+		defaultExtractFilesHook(req.ExtractFiles),
+		defaultStatsCaptureHook(req.CaptureStatsOnTerminate),
+		defaultStopStrategyHook(req.StopStrategy),
 	}
 
 	dc := &DockerContainer{
@@ -1261,6 +2419,7 @@ func (p *DockerProvider) ReuseOrCreateContainer(ctx context.Context, req Contain
 		terminationSignal: termSignal,
 		logger:            p.Logger,
 		lifecycleHooks:    []ContainerLifecycleHooks{combineContainerHooks(defaultHooks, req.LifecycleHooks)},
+		lifecycleState:    LifecycleState{Created: true, Started: true},
 	}
 
 	err = dc.startedHook(ctx)
@@ -1278,9 +2437,106 @@ func (p *DockerProvider) ReuseOrCreateContainer(ctx context.Context, req Contain
 	return dc, nil
 }
 
+// allowsExtraNetworks returns true if a container with the network mode can be connected to other
+// networks, unlike the ones sharing the network stack of the host or of another container, or having none.
+func allowsExtraNetworks(mode container.NetworkMode) bool {
+	return !mode.IsHost() && !mode.IsNone() && !mode.IsContainer()
+}
+
+// imageDigestRegex matches an image ID, e.g. "sha256:<hex>", or an image name with a digest, e.g. "redis@sha256:<hex>"
+var imageDigestRegex = regexp.MustCompile(`(^|@)sha256:[a-f0-9]{64}$`)
+
+// isImageDigest returns true if the image is referenced by its content digest instead of by a tag.
+func isImageDigest(img string) bool {
+	return imageDigestRegex.MatchString(img)
+}
+
+// checkLocalImage checks that the image is present locally for the platform, if any, without pulling it.
+func (p *DockerProvider) checkLocalImage(ctx context.Context, img string, platform *specs.Platform) error {
+	inspect, _, err := p.client.ImageInspectWithRaw(ctx, img)
+	if err != nil {
+		if client.IsErrNotFound(err) {
+			return fmt.Errorf("image %s not present locally, and it's not pulled in local-only mode: %w", img, err)
+		}
+		return err
+	}
+
+	if platform != nil && (inspect.Architecture != platform.Architecture || inspect.Os != platform.OS) {
+		return fmt.Errorf("local image %s is for platform %s/%s, not %s/%s", img, inspect.Os, inspect.Architecture, platform.OS, platform.Architecture)
+	}
+
+	return nil
+}
+
 // attemptToPullImage tries to pull the image while respecting the ctx cancellations.
 // Besides, if the image cannot be pulled due to ErrorNotFound then no need to retry but terminate immediately.
+// Concurrent pulls of the same image and platform from the same Docker host are coalesced,
+// and the number of concurrent pulls is limited by SetGlobalMaxConcurrentPulls.
 func (p *DockerProvider) attemptToPullImage(ctx context.Context, tag string, pullOpt image.PullOptions) error {
+	key := p.host + "|" + pullOpt.Platform + "|" + tag
+	return defaultImagePuller.pull(ctx, key, func(ctx context.Context) error {
+		return p.pullImage(ctx, tag, pullOpt)
+	})
+}
+
+// ErrImagePullUnauthorized is reported by errors.Is for the PullError returned when the registry
+// denies the access to an image, e.g. a private image pulled without credentials, or with wrong ones.
+var ErrImagePullUnauthorized = errors.New("image pull unauthorized")
+
+// PullError is returned when an image cannot be pulled.
+type PullError struct {
+	Registry             string // the host of the registry, e.g. "docker.io"
+	Reference            string // the reference of the image, e.g. "docker.io/library/redis:7"
+	CredentialsAttempted bool   // true if credentials for the registry were sent to the Docker engine
+	Unauthorized         bool   // true if the registry denied the access to the image
+	Err                  error  // the error returned by the Docker engine
+}
+
+// Error implements the error interface.
+func (e *PullError) Error() string {
+	msg := fmt.Sprintf("pull image %s from registry %s", e.Reference, e.Registry)
+	if e.Unauthorized {
+		if e.CredentialsAttempted {
+			msg += " (unauthorized with the configured credentials)"
+		} else {
+			msg += " (unauthorized, no credentials found for the registry)"
+		}
+	}
+
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+// Unwrap returns the error returned by the Docker engine.
+func (e *PullError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports ErrImagePullUnauthorized as matching if the registry denied the access to the image.
+func (e *PullError) Is(target error) bool {
+	return target == ErrImagePullUnauthorized && e.Unauthorized
+}
+
+// isPullUnauthorized returns true if the error of a pull means that the registry denied the access
+// to the image. The Docker engine reports a private image pulled without credentials as not found,
+// as the registries do not disclose if the image exists, so the message is checked too.
+func isPullUnauthorized(err error) bool {
+	if errdefs.IsUnauthorized(err) || errdefs.IsForbidden(err) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, s := range []string{"pull access denied", "unauthorized", "authentication required", "requested access to the resource is denied"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pullImage pulls the image, retrying on non-permanent errors while respecting the ctx cancellations.
+// The errors are returned as a PullError.
+func (p *DockerProvider) pullImage(ctx context.Context, tag string, pullOpt image.PullOptions) error {
 	registry, imageAuth, err := DockerImageAuth(ctx, tag)
 	if err != nil {
 		p.Logger.Printf("Failed to get image auth for %s. Setting empty credentials for the image: %s. Error is:%s", registry, tag, err)
@@ -1294,6 +2550,23 @@ func (p *DockerProvider) attemptToPullImage(ctx context.Context, tag string, pul
 		}
 	}
 
+	if err := p.pullImageWithRetries(ctx, tag, pullOpt); err != nil {
+		return &PullError{
+			Registry:             registry,
+			Reference:            tag,
+			CredentialsAttempted: pullOpt.RegistryAuth != "",
+			Unauthorized:         isPullUnauthorized(err),
+			Err:                  err,
+		}
+	}
+
+	return nil
+}
+
+// pullImageWithRetries pulls the image, retrying on non-permanent errors while respecting the ctx cancellations.
+func (p *DockerProvider) pullImageWithRetries(ctx context.Context, tag string, pullOpt image.PullOptions) error {
+	var err error
+
 	var pull io.ReadCloser
 	err = backoff.Retry(func() error {
 		pull, err = p.client.ImagePull(ctx, tag, pullOpt)
@@ -1570,6 +2843,11 @@ func containerFromDockerResponse(ctx context.Context, response types.Container)
 	ctr.sessionID = core.SessionID()
 	ctr.consumers = []LogConsumer{}
 	ctr.isRunning = response.State == "running"
+	ctr.lifecycleState = LifecycleState{
+		Created: true,
+		Started: ctr.isRunning,
+		Ready:   ctr.isRunning,
+	}
 
 	// the termination signal should be obtained from the reaper
 	ctr.terminationSignal = nil
@@ -1580,6 +2858,11 @@ func containerFromDockerResponse(ctx context.Context, response types.Container)
 		return nil, err
 	}
 
+	if ctr.raw.HostConfig != nil {
+		ctr.autoRemove = ctr.raw.HostConfig.AutoRemove
+	}
+	ctr.stopTimeout = stopTimeoutOf(ctr.raw.Config)
+
 	// the health status of the container, if any
 	if health := ctr.raw.State.Health; health != nil {
 		ctr.healthStatus = health.Status
@@ -1607,6 +2890,69 @@ func (p *DockerProvider) ListImages(ctx context.Context) ([]ImageInfo, error) {
 	return images, nil
 }
 
+// ContainerInfo is a summary of a container created by Testcontainers, as reported by ListContainers.
+type ContainerInfo struct {
+	ID        string
+	Image     string
+	Name      string
+	State     string // e.g. "running" or "exited"
+	SessionID string // the session which created the container
+}
+
+// ListOption is a functional option to configure which containers are listed by ListContainers.
+type ListOption func(*listOptions)
+
+// listOptions holds the configuration applied when listing the containers.
+type listOptions struct {
+	allSessions bool
+}
+
+// WithAllSessions makes ListContainers list the containers created by any Testcontainers session,
+// e.g. the ones leaked by previous test runs, instead of the ones of the current session only.
+func WithAllSessions() ListOption {
+	return func(o *listOptions) {
+		o.allSessions = true
+	}
+}
+
+// ListContainers lists the containers created by the current Testcontainers session, including the
+// stopped ones and the reaper, e.g. to find the containers leaked by a test.
+func (p *DockerProvider) ListContainers(ctx context.Context, opts ...ListOption) ([]ContainerInfo, error) {
+	var listOpts listOptions
+	for _, opt := range opts {
+		opt(&listOpts)
+	}
+
+	filter := filters.NewArgs(filters.Arg("label", core.LabelBase+"=true"))
+	if !listOpts.allSessions {
+		filter.Add("label", fmt.Sprintf("%s=%s", core.LabelSessionID, core.SessionID()))
+	}
+
+	list, err := p.client.ContainerList(ctx, container.ListOptions{All: true, Filters: filter})
+	if err != nil {
+		return nil, fmt.Errorf("listing containers %w", err)
+	}
+	defer p.Close()
+
+	containers := make([]ContainerInfo, 0, len(list))
+	for _, c := range list {
+		var name string
+		if len(c.Names) > 0 {
+			name = strings.TrimPrefix(c.Names[0], "/")
+		}
+
+		containers = append(containers, ContainerInfo{
+			ID:        c.ID,
+			Image:     c.Image,
+			Name:      name,
+			State:     c.State,
+			SessionID: c.Labels[core.LabelSessionID],
+		})
+	}
+
+	return containers, nil
+}
+
 // SaveImages exports a list of images as an uncompressed tar
 func (p *DockerProvider) SaveImages(ctx context.Context, output string, images ...string) error {
 	outputFile, err := os.Create(output)
@@ -1639,6 +2985,44 @@ func (p *DockerProvider) PullImage(ctx context.Context, img string) error {
 	return p.attemptToPullImage(ctx, img, image.PullOptions{})
 }
 
+// TagAndPushImage tags the image, e.g. an image built with BuildImage, with the reference, and pushes it
+// to the registry of the reference, e.g. "localhost:5000/my-app:test" for a registry container. Without
+// auth, the credentials for the registry are retrieved from the Docker config, as for the pulls, and the
+// image is pushed without them if there are none. The errors reported while pushing are returned.
+func (p *DockerProvider) TagAndPushImage(ctx context.Context, img string, ref string, auth *registry.AuthConfig) error {
+	if err := p.client.ImageTag(ctx, img, ref); err != nil {
+		return fmt.Errorf("tag image %s as %s: %w", img, ref, err)
+	}
+
+	if auth == nil {
+		if _, imageAuth, err := DockerImageAuth(ctx, ref); err == nil {
+			auth = &imageAuth
+		}
+	}
+
+	var pushOpts image.PushOptions
+	if auth != nil {
+		encoded, err := registry.EncodeAuthConfig(*auth)
+		if err != nil {
+			return fmt.Errorf("encode registry auth: %w", err)
+		}
+		pushOpts.RegistryAuth = encoded
+	}
+
+	rc, err := p.client.ImagePush(ctx, ref, pushOpts)
+	if err != nil {
+		return fmt.Errorf("push image %s: %w", ref, err)
+	}
+	defer rc.Close()
+
+	// the push completes at EOF, and its errors are reported in the stream
+	if err := jsonmessage.DisplayJSONMessagesStream(rc, io.Discard, 0, false, nil); err != nil {
+		return fmt.Errorf("push image %s: %w", ref, err)
+	}
+
+	return nil
+}
+
 var permanentClientErrors = []func(error) bool{
 	errdefs.IsNotFound,
 	errdefs.IsInvalidParameter,
@@ -3,8 +3,12 @@ package testcontainers
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/stretchr/testify/require"
@@ -137,3 +141,142 @@ func TestExecWithNonMultiplexedResponse(t *testing.T) {
 	require.Equal(t, "stdout\n", stdout.String())
 	require.Equal(t, "stderr\n", stderr.String())
 }
+
+func TestExecWithStdin(t *testing.T) {
+	ctx := context.Background()
+	req := ContainerRequest{
+		Image: nginxAlpineImage,
+	}
+
+	container, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, container)
+
+	t.Run("consumed-until-eof", func(t *testing.T) {
+		code, _, err := container.Exec(ctx, []string{"sh", "-c", "cat > /tmp/stdin.txt"}, tcexec.WithStdin(strings.NewReader("piped input\n")))
+		require.NoError(t, err)
+		require.Zero(t, code)
+
+		code, reader, err := container.Exec(ctx, []string{"cat", "/tmp/stdin.txt"}, tcexec.Multiplexed())
+		require.NoError(t, err)
+		require.Zero(t, code)
+
+		b, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, "piped input\n", string(b))
+	})
+
+	t.Run("multiplexed", func(t *testing.T) {
+		// the input is larger than the buffers of the connection, so the output
+		// must be read while the input is written
+		input := strings.Repeat("0123456789abcdef", 256*1024)
+
+		code, reader, err := container.Exec(ctx, []string{"cat"}, tcexec.WithStdin(strings.NewReader(input)), tcexec.Multiplexed())
+		require.NoError(t, err)
+		require.Zero(t, code)
+
+		b, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.Equal(t, input, string(b))
+	})
+
+	t.Run("not-multiplexed", func(t *testing.T) {
+		// the output is larger than the pipe of the command, so it must be read
+		// while the input is written, even if nothing reads it yet
+		input := strings.Repeat("0123456789abcdef", 8*1024)
+
+		ctx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+
+		code, reader, err := container.Exec(ctx, []string{"cat"}, tcexec.WithStdin(strings.NewReader(input)))
+		require.NoError(t, err)
+		require.Zero(t, code)
+
+		var stdout, stderr bytes.Buffer
+		_, err = stdcopy.StdCopy(&stdout, &stderr, reader)
+		require.NoError(t, err)
+		require.Equal(t, input, stdout.String())
+		require.Empty(t, stderr.String())
+	})
+
+	t.Run("exit-code", func(t *testing.T) {
+		code, _, err := container.Exec(ctx, []string{"sh", "-c", "read code; exit $code"}, tcexec.WithStdin(strings.NewReader("3\n")))
+		require.NoError(t, err)
+		require.Equal(t, 3, code)
+	})
+}
+
+func TestDockerContainerAttachStdin(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("cat", func(t *testing.T) {
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ContainerRequest: ContainerRequest{
+				Image:      "docker.io/alpine:latest",
+				Entrypoint: []string{"cat"},
+				OpenStdin:  true,
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		input := "first line\nsecond line\n"
+		require.NoError(t, ctr.(*DockerContainer).AttachStdin(ctx, strings.NewReader(input)))
+
+		// cat exits once its stdin is closed, after echoing the input
+		require.Eventually(t, func() bool {
+			state, err := ctr.State(ctx)
+			return err == nil && !state.Running
+		}, 10*time.Second, 100*time.Millisecond)
+
+		r, err := ctr.Logs(ctx)
+		require.NoError(t, err)
+		defer r.Close()
+
+		logs, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Contains(t, string(logs), "first line")
+		require.Contains(t, string(logs), "second line")
+	})
+
+	t.Run("stdin-not-open", func(t *testing.T) {
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ContainerRequest: ContainerRequest{
+				Image: "docker.io/alpine:latest",
+				Cmd:   []string{"sleep", "300"},
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		err = ctr.(*DockerContainer).AttachStdin(ctx, strings.NewReader("input"))
+		require.ErrorContains(t, err, "the container was not created with OpenStdin")
+	})
+}
+
+func TestCopyExecStdin(t *testing.T) {
+	t.Run("drained", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, copyExecStdin(&buf, strings.NewReader("input")))
+		require.Equal(t, "input", buf.String())
+	})
+
+	t.Run("write-error", func(t *testing.T) {
+		// the command exited without consuming its input
+		pr, pw := io.Pipe()
+		require.NoError(t, pr.Close())
+		require.NoError(t, copyExecStdin(pw, strings.NewReader("input")))
+	})
+
+	t.Run("read-error", func(t *testing.T) {
+		readErr := errors.New("read failed")
+		err := copyExecStdin(io.Discard, iotest.ErrReader(readErr))
+		require.ErrorIs(t, err, readErr)
+	})
+}
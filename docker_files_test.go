@@ -1,12 +1,16 @@
 package testcontainers_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/container"
 	"github.com/stretchr/testify/require"
 
 	"github.com/testcontainers/testcontainers-go"
@@ -227,3 +231,178 @@ func TestCopyDirectoryToRunningContainerAsDir(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, container.Terminate(ctx))
 }
+
+func TestCopyFilesFromTempDir(t *testing.T) {
+	ctx, cnl := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cnl()
+
+	var dir string
+	t.Run("copy", func(t *testing.T) {
+		// copyFilesFromTempDir {
+		dir = testcontainers.TempDir(t)
+
+		err := os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hello"), 0o644)
+		require.NoError(t, err)
+		err = os.Mkdir(filepath.Join(dir, "nested"), 0o755)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(dir, "nested", "world.txt"), []byte("world"), 0o644)
+		require.NoError(t, err)
+
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "docker.io/bash",
+				Cmd:        []string{"bash", "-c", "cat /app/data/hello.txt /app/data/nested/world.txt && echo && echo done && sleep 30"},
+				WaitingFor: wait.ForLog("done"),
+			},
+			Started: true,
+		}
+		err = testcontainers.WithFilesFromDir(dir, "/app/data", 0o755)(&req)
+		require.NoError(t, err)
+
+		ctr, err := testcontainers.GenericContainer(ctx, req)
+		// }
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, ctr.Terminate(ctx))
+		}()
+
+		logs, err := ctr.(*testcontainers.DockerContainer).LogsText(ctx, container.LogsOptions{})
+		require.NoError(t, err)
+		require.Contains(t, logs, "helloworld")
+	})
+
+	// the temporary directory is removed once the subtest completes
+	_, err := os.Stat(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCopyDirectoryToRunningContainerWithTarTransform(t *testing.T) {
+	ctx, cnl := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cnl()
+
+	dir := filepath.Join(t.TempDir(), "conf")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.conf"), []byte("port={{PORT}}\n"), 0o644))
+
+	ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:      "docker.io/alpine",
+			Entrypoint: []string{"tail", "-f", "/dev/null"},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ctr.Terminate(ctx))
+	}()
+
+	dockerCtr, ok := ctr.(*testcontainers.DockerContainer)
+	require.True(t, ok)
+
+	// copyDirectoryWithTarTransform {
+	err = dockerCtr.CopyDirToContainerWithOptions(ctx, dir, "/etc/conf", 0o755, testcontainers.WithTarTransform(func(hdr *tar.Header, content []byte) ([]byte, error) {
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, nil
+		}
+		return bytes.ReplaceAll(content, []byte("{{PORT}}"), []byte("8080")), nil
+	}))
+	// }
+	require.NoError(t, err)
+
+	rc, err := ctr.CopyFileFromContainer(ctx, "/etc/conf/app.conf")
+	require.NoError(t, err)
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, "port=8080\n", string(content))
+}
+
+func TestExtractFilesFromContainer(t *testing.T) {
+	ctx, cnl := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cnl()
+
+	hostDir := t.TempDir()
+
+	// extractFilesOnReady {
+	ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:      "docker.io/alpine",
+			Entrypoint: []string{"sh", "-c", "echo generated > /tmp/cert.pem && echo done && sleep 30"},
+			WaitingFor: wait.ForLog("done"),
+			ExtractFiles: []testcontainers.ExtractFile{
+				{
+					ContainerFilePath: "/tmp/cert.pem",
+					HostFilePath:      filepath.Join(hostDir, "cert.pem"),
+					FileMode:          0o600,
+				},
+			},
+		},
+		Started: true,
+	})
+	// }
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ctr.Terminate(ctx))
+	}()
+
+	content, err := os.ReadFile(filepath.Join(hostDir, "cert.pem"))
+	require.NoError(t, err)
+	require.Equal(t, "generated\n", string(content))
+
+	info, err := os.Stat(filepath.Join(hostDir, "cert.pem"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	t.Run("missing", func(t *testing.T) {
+		ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "docker.io/alpine",
+				Entrypoint: []string{"tail", "-f", "/dev/null"},
+				ExtractFiles: []testcontainers.ExtractFile{
+					{ContainerFilePath: "/tmp/missing-1.pem", HostFilePath: filepath.Join(hostDir, "missing-1.pem")},
+					{ContainerFilePath: "/tmp/missing-2.pem", HostFilePath: filepath.Join(hostDir, "missing-2.pem")},
+				},
+			},
+			Started: true,
+		})
+		terminateContainerOnEnd(t, ctx, ctr)
+		require.ErrorContains(t, err, "can't copy /tmp/missing-1.pem from container")
+		require.ErrorContains(t, err, "can't copy /tmp/missing-2.pem from container")
+	})
+}
+
+func TestCopyArchiveToContainer(t *testing.T) {
+	ctx, cnl := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cnl()
+
+	ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:      "docker.io/alpine",
+			Entrypoint: []string{"tail", "-f", "/dev/null"},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ctr.Terminate(ctx))
+	}()
+
+	// copyArchiveToContainer {
+	err = ctr.(*testcontainers.DockerContainer).CopyArchiveToContainer(ctx, filepath.Join("testdata", "archives", "dataset.tar.gz"), "/tmp")
+	// }
+	require.NoError(t, err)
+
+	for path, expected := range map[string]string{
+		"/tmp/dataset/users.csv":         "id,name\n1,alice\n2,bob\n",
+		"/tmp/dataset/nested/orders.csv": "id,user_id\n1,1\n",
+	} {
+		rc, err := ctr.CopyFileFromContainer(ctx, path)
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		require.Equal(t, expected, string(content))
+	}
+}
@@ -1,6 +1,12 @@
 package testcontainers
 
-import "github.com/docker/docker/api/types/mount"
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/docker/docker/api/types/mount"
+)
 
 var mountTypeMapping = map[MountType]mount.Type{
 	MountTypeBind:   mount.TypeBind, // Deprecated, it will be removed in a future release
@@ -81,6 +87,31 @@ func (s DockerTmpfsMountSource) GetTmpfsOptions() *mount.TmpfsOptions {
 	return s.TmpfsOptions
 }
 
+// TmpfsOptions represents the typed options of a tmpfs mount
+type TmpfsOptions struct {
+	// SizeBytes is the size of the tmpfs mount in bytes. Zero means the engine default, which is unlimited.
+	SizeBytes int64
+	// Mode is the file mode of the tmpfs mount in octal, e.g. 0o1777. Zero means the engine default.
+	Mode os.FileMode
+}
+
+// validate checks the tmpfs options are valid for the given target
+func (o TmpfsOptions) validate(target string) error {
+	if !path.IsAbs(target) {
+		return fmt.Errorf("tmpfs target %q must be an absolute path", target)
+	}
+
+	if o.SizeBytes < 0 {
+		return fmt.Errorf("tmpfs size for %s must not be negative: %d", target, o.SizeBytes)
+	}
+
+	if o.Mode > 0o7777 {
+		return fmt.Errorf("tmpfs mode for %s must be at most 07777: %o", target, o.Mode)
+	}
+
+	return nil
+}
+
 // PrepareMounts maps the given []ContainerMount to the corresponding
 // []mount.Mount for further processing
 func (m ContainerMounts) PrepareMounts() []mount.Mount {
@@ -1,6 +1,8 @@
 package testcontainers
 
 import (
+	"archive/tar"
+	"bufio"
 	"bytes"
 	"context"
 	"errors"
@@ -8,23 +10,31 @@ import (
 	"io"
 	"log"
 	"math/rand"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/errdefs"
+	"github.com/docker/go-connections/nat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
+	"github.com/testcontainers/testcontainers-go/internal/core"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
@@ -465,6 +475,254 @@ func TestTwoContainersExposingTheSamePort(t *testing.T) {
 	}
 }
 
+func TestDockerContainerGatewayIP(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("bridge", func(t *testing.T) {
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image:      nginxAlpineImage,
+				Networks:   []string{Bridge},
+				WaitingFor: wait.ForListeningPort(nginxDefaultPort),
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		gateway, err := ctr.(*DockerContainer).GatewayIP(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, gateway)
+		require.NotNil(t, net.ParseIP(gateway), "gateway should be an IP address: %s", gateway)
+	})
+
+	t.Run("host", func(t *testing.T) {
+		SkipIfDockerDesktop(t, ctx)
+
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image: nginxAlpineImage,
+				HostConfigModifier: func(hc *container.HostConfig) {
+					hc.NetworkMode = "host"
+				},
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		gateway, err := ctr.(*DockerContainer).GatewayIP(ctx)
+		require.NoError(t, err)
+		require.Empty(t, gateway)
+	})
+}
+
+func TestDockerContainerLastStats(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(t *testing.T, capture bool) *DockerContainer {
+		t.Helper()
+
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image:                   nginxAlpineImage,
+				WaitingFor:              wait.ForListeningPort(nginxDefaultPort),
+				CaptureStatsOnTerminate: capture,
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+
+		// run a workload, so the container uses some CPU and memory
+		code, _, err := ctr.Exec(ctx, []string{"sh", "-c", "dd if=/dev/urandom bs=1M count=32 | md5sum"})
+		require.NoError(t, err)
+		require.Zero(t, code)
+
+		require.NoError(t, ctr.Terminate(ctx))
+
+		return ctr.(*DockerContainer)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		require.Nil(t, run(t, false).LastStats())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		stats := run(t, true).LastStats()
+		require.NotNil(t, stats)
+		require.NotZero(t, stats.MemoryStats.Usage)
+		require.NotZero(t, stats.CPUStats.CPUUsage.TotalUsage)
+	})
+}
+
+func TestDockerContainerStats(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:      nginxAlpineImage,
+			WaitingFor: wait.ForListeningPort(nginxDefaultPort),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	dockerContainer := ctr.(*DockerContainer)
+
+	t.Run("one-shot", func(t *testing.T) {
+		stats, err := dockerContainer.Stats(ctx)
+		require.NoError(t, err)
+		require.NotZero(t, stats.MemoryStats.Usage)
+		require.NotZero(t, stats.CPUStats.CPUUsage.TotalUsage)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		ch, err := dockerContainer.StatsStream(ctx)
+		require.NoError(t, err)
+
+		for i := 0; i < 2; i++ {
+			select {
+			case stats, ok := <-ch:
+				require.True(t, ok)
+				require.NotZero(t, stats.MemoryStats.Usage)
+			case <-time.After(10 * time.Second):
+				t.Fatal("no stats received")
+			}
+		}
+
+		// the channel is closed once the context is cancelled
+		cancel()
+		require.Eventually(t, func() bool {
+			select {
+			case _, ok := <-ch:
+				return !ok
+			default:
+				return false
+			}
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("stream-exited", func(t *testing.T) {
+		ch, err := dockerContainer.StatsStream(ctx)
+		require.NoError(t, err)
+
+		require.NoError(t, ctr.Stop(ctx, nil))
+
+		// the channel is closed once the container exits
+		timeout := time.After(30 * time.Second)
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+			case <-timeout:
+				t.Fatal("channel not closed")
+			}
+		}
+	})
+}
+
+func TestScrubSecret(t *testing.T) {
+	secret := []byte("s3cr3t")
+
+	err := scrubSecret(fmt.Errorf("copy failed: invalid content %q", secret), secret)
+	require.EqualError(t, err, `copy failed: invalid content "[REDACTED]"`)
+
+	original := errors.New("copy failed")
+	require.Equal(t, original, scrubSecret(original, secret))
+	require.Equal(t, original, scrubSecret(original, nil))
+}
+
+func TestNumericUser(t *testing.T) {
+	tests := []struct {
+		user    string
+		uid     int
+		gid     int
+		numeric bool
+	}{
+		{user: ""},
+		{user: "postgres"},
+		{user: "1000:staff"},
+		{user: "1000", uid: 1000, numeric: true},
+		{user: "1000:2000", uid: 1000, gid: 2000, numeric: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			uid, gid, numeric := numericUser(tt.user)
+			require.Equal(t, tt.numeric, numeric)
+			require.Equal(t, tt.uid, uid)
+			require.Equal(t, tt.gid, gid)
+		})
+	}
+}
+
+func TestDockerContainerMetadata(t *testing.T) {
+	t.Run("round-trip", func(t *testing.T) {
+		ctr := &DockerContainer{}
+
+		_, ok := ctr.Metadata("phase")
+		require.False(t, ok)
+
+		ctr.SetMetadata("phase", "setup")
+		ctr.SetMetadata("phase", "assertions")
+		ctr.SetMetadata("test", t.Name())
+
+		phase, ok := ctr.Metadata("phase")
+		require.True(t, ok)
+		require.Equal(t, "assertions", phase)
+
+		name, ok := ctr.Metadata("test")
+		require.True(t, ok)
+		require.Equal(t, t.Name(), name)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ctr := &DockerContainer{}
+		ctr.SetMetadata("phase", "multi\nline")
+
+		err := ctr.WriteMetadataFile(context.Background())
+		require.ErrorContains(t, err, "invalid metadata \"phase\"")
+	})
+
+	t.Run("file", func(t *testing.T) {
+		ctx := context.Background()
+
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image:      nginxAlpineImage,
+				WaitingFor: wait.ForListeningPort(nginxDefaultPort),
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		dockerContainer := ctr.(*DockerContainer)
+		dockerContainer.SetMetadata("phase", "setup")
+		dockerContainer.SetMetadata("owner", "tests")
+		require.NoError(t, dockerContainer.WriteMetadataFile(ctx))
+
+		fd, err := ctr.CopyFileFromContainer(ctx, MetadataFilePath)
+		require.NoError(t, err)
+		defer fd.Close()
+
+		content, err := io.ReadAll(fd)
+		require.NoError(t, err)
+		require.Equal(t, "owner=tests\nphase=setup\n", string(content))
+	})
+}
+
 func TestContainerCreation(t *testing.T) {
 	ctx := context.Background()
 
@@ -1433,6 +1691,118 @@ func TestDockerContainerCopyDirToContainer(t *testing.T) {
 	assertExtractedFiles(t, ctx, nginxC, p, "/tmp/testdata/")
 }
 
+func TestDockerContainerCopyDirToContainer_Symlinks(t *testing.T) {
+	ctx := context.Background()
+
+	nginxC, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:        nginxImage,
+			ExposedPorts: []string{nginxDefaultPort},
+			WaitingFor:   wait.ForListeningPort(nginxDefaultPort),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, nginxC)
+
+	src := filepath.Join(t.TempDir(), "symlinks")
+	require.NoError(t, os.MkdirAll(src, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "target.txt"), []byte("hello"), 0o644))
+	require.NoError(t, os.Symlink("target.txt", filepath.Join(src, "link.txt")))
+
+	t.Run("preserve", func(t *testing.T) {
+		err = nginxC.CopyDirToContainer(ctx, src, "/tmp/symlinks", 0o755)
+		require.NoError(t, err)
+
+		code, reader, err := nginxC.Exec(ctx, []string{"readlink", "/tmp/symlinks/link.txt"}, tcexec.Multiplexed())
+		require.NoError(t, err)
+		require.Zero(t, code)
+
+		target, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "target.txt\n", string(target))
+	})
+
+	t.Run("dereference", func(t *testing.T) {
+		_, _, err = nginxC.Exec(ctx, []string{"rm", "-rf", "/tmp/symlinks"})
+		require.NoError(t, err)
+
+		dockerC, ok := nginxC.(*DockerContainer)
+		require.True(t, ok)
+
+		err = dockerC.CopyDirToContainerWithOptions(ctx, src, "/tmp/symlinks", 0o755, WithDereferencedSymlinks())
+		require.NoError(t, err)
+
+		code, _, err := nginxC.Exec(ctx, []string{"test", "-L", "/tmp/symlinks/link.txt"})
+		require.NoError(t, err)
+		require.NotZero(t, code, "link.txt should be a regular file")
+
+		fd, err := nginxC.CopyFileFromContainer(ctx, "/tmp/symlinks/link.txt")
+		require.NoError(t, err)
+		defer fd.Close()
+
+		content, err := io.ReadAll(fd)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(content))
+	})
+}
+
+func TestDockerContainerCopyTarToContainer(t *testing.T) {
+	ctx := context.Background()
+
+	nginxC, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:        nginxImage,
+			ExposedPorts: []string{nginxDefaultPort},
+			WaitingFor:   wait.ForListeningPort(nginxDefaultPort),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, nginxC)
+
+	files := map[string]string{
+		"hello.txt":     "hello",
+		"dir/world.txt": "world",
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+
+	t.Run("missing destination", func(t *testing.T) {
+		err := nginxC.(*DockerContainer).CopyTarToContainer(ctx, bytes.NewReader(buf.Bytes()), "/does-not-exist")
+		require.Error(t, err)
+	})
+
+	t.Run("destination is not a directory", func(t *testing.T) {
+		err := nginxC.(*DockerContainer).CopyTarToContainer(ctx, bytes.NewReader(buf.Bytes()), "/etc/hostname")
+		require.ErrorContains(t, err, "is not a directory")
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		err := nginxC.(*DockerContainer).CopyTarToContainer(ctx, &buf, "/tmp")
+		require.NoError(t, err)
+
+		for name, expected := range files {
+			fd, err := nginxC.CopyFileFromContainer(ctx, "/tmp/"+name)
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(fd)
+			require.NoError(t, err)
+			require.NoError(t, fd.Close())
+			assert.Equal(t, expected, string(content))
+		}
+	})
+}
+
 func TestDockerCreateContainerWithFiles(t *testing.T) {
 	ctx := context.Background()
 	hostFileName := filepath.Join(".", "testdata", "hello.sh")
@@ -1668,6 +2038,127 @@ func TestDockerContainerCopyFileFromContainer(t *testing.T) {
 	assert.Equal(t, fileContent, fileContentFromContainer)
 }
 
+func TestDockerContainerCopyFileFromContainerToHost(t *testing.T) {
+	ctx := context.Background()
+
+	nginxC, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:        nginxImage,
+			ExposedPorts: []string{nginxDefaultPort},
+			WaitingFor:   wait.ForListeningPort(nginxDefaultPort),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, nginxC)
+
+	// a file of a few MB, larger than the buffers used to copy it
+	c, _, err := nginxC.Exec(ctx, []string{"sh", "-c", "head -c 5242880 /dev/urandom > /tmp/artifact.bin"})
+	require.NoError(t, err)
+	require.Zero(t, c)
+
+	dockerContainer := nginxC.(*DockerContainer)
+	hostPath := filepath.Join(t.TempDir(), "artifact.bin")
+
+	t.Run("file", func(t *testing.T) {
+		err := dockerContainer.CopyFileFromContainerToHost(ctx, "/tmp/artifact.bin", hostPath, 0o600)
+		require.NoError(t, err)
+
+		info, err := os.Stat(hostPath)
+		require.NoError(t, err)
+		require.Equal(t, int64(5242880), info.Size())
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		reader, err := nginxC.CopyFileFromContainer(ctx, "/tmp/artifact.bin")
+		require.NoError(t, err)
+		defer reader.Close()
+
+		fromContainer, err := io.ReadAll(reader)
+		require.NoError(t, err)
+
+		fromHost, err := os.ReadFile(hostPath)
+		require.NoError(t, err)
+		require.Equal(t, fromContainer, fromHost)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		err := dockerContainer.CopyFileFromContainerToHost(ctx, "/tmp", filepath.Join(t.TempDir(), "tmp"), 0o600)
+		require.ErrorContains(t, err, "is a directory")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := dockerContainer.CopyFileFromContainerToHost(ctx, "/tmp/missing.bin", filepath.Join(t.TempDir(), "missing.bin"), 0o600)
+		require.Error(t, err)
+	})
+}
+
+func TestExtractTarFile(t *testing.T) {
+	newTar := func(t *testing.T, headers ...*tar.Header) *bytes.Buffer {
+		t.Helper()
+
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		for _, hdr := range headers {
+			require.NoError(t, tw.WriteHeader(hdr))
+			if hdr.Typeflag == tar.TypeReg {
+				_, err := tw.Write([]byte(hdr.Name))
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, tw.Close())
+
+		return &buf
+	}
+
+	t.Run("first-regular-file", func(t *testing.T) {
+		r := newTar(t,
+			&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "file"},
+			&tar.Header{Name: "file", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4},
+			&tar.Header{Name: "other", Typeflag: tar.TypeReg, Mode: 0o644, Size: 5},
+		)
+
+		hostPath := filepath.Join(t.TempDir(), "file")
+		// an existing file is truncated
+		require.NoError(t, os.WriteFile(hostPath, []byte("previous content"), 0o644))
+
+		require.NoError(t, extractTarFile(r, "/file", hostPath, 0o640))
+
+		content, err := os.ReadFile(hostPath)
+		require.NoError(t, err)
+		require.Equal(t, "file", string(content))
+
+		info, err := os.Stat(hostPath)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		r := newTar(t, &tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0o755})
+
+		hostPath := filepath.Join(t.TempDir(), "dir")
+		require.ErrorContains(t, extractTarFile(r, "/dir", hostPath, 0o640), "path /dir is a directory")
+		require.NoFileExists(t, hostPath)
+	})
+
+	t.Run("no-regular-file", func(t *testing.T) {
+		r := newTar(t, &tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "file"})
+
+		hostPath := filepath.Join(t.TempDir(), "link")
+		require.ErrorContains(t, extractTarFile(r, "/link", hostPath, 0o640), "no regular file found")
+		require.NoFileExists(t, hostPath)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		r := newTar(t, &tar.Header{Name: "file", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4})
+		r.Truncate(512 + 2)
+
+		hostPath := filepath.Join(t.TempDir(), "file")
+		require.Error(t, extractTarFile(r, "/file", hostPath, 0o640))
+		require.NoFileExists(t, hostPath)
+	})
+}
+
 func TestDockerContainerCopyEmptyFileFromContainer(t *testing.T) {
 	ctx := context.Background()
 
@@ -1851,11 +2342,1123 @@ func TestContainerWithUserID(t *testing.T) {
 	assert.Equal(t, req.User, actual)
 }
 
-func TestContainerWithNoUserID(t *testing.T) {
+func TestDockerContainerLogsText(t *testing.T) {
 	ctx := context.Background()
-	req := ContainerRequest{
-		Image:      "docker.io/alpine:latest",
-		Cmd:        []string{"sh", "-c", "id -u"},
+
+	for _, tty := range []bool{false, true} {
+		t.Run(fmt.Sprintf("tty-%t", tty), func(t *testing.T) {
+			ctr, err := GenericContainer(ctx, GenericContainerRequest{
+				ProviderType: providerType,
+				ContainerRequest: ContainerRequest{
+					Image:      "docker.io/alpine:latest",
+					Cmd:        []string{"sh", "-c", "echo to-stdout; sleep 0.2; echo to-stderr >&2"},
+					WaitingFor: wait.ForExit(),
+					ConfigModifier: func(config *container.Config) {
+						config.Tty = tty
+					},
+				},
+				Started: true,
+			})
+			require.NoError(t, err)
+			terminateContainerOnEnd(t, ctx, ctr)
+
+			logs, err := ctr.(*DockerContainer).LogsText(ctx, container.LogsOptions{})
+			require.NoError(t, err)
+
+			lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(logs, "\r\n", "\n")), "\n")
+			require.Equal(t, []string{"to-stdout", "to-stderr"}, lines)
+
+			// no stream framing bytes are present
+			require.NotContains(t, logs, "\x00")
+			require.NotContains(t, logs, "\x01")
+			require.NotContains(t, logs, "\x02")
+		})
+	}
+
+	t.Run("follow", func(t *testing.T) {
+		ctr := &DockerContainer{}
+		_, err := ctr.LogsText(ctx, container.LogsOptions{Follow: true})
+		require.Error(t, err)
+	})
+}
+
+func TestWaitForLogWithStream(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: "docker.io/alpine:latest",
+			// stdout is noisy and prints the ready message way before stderr does
+			Cmd:        []string{"sh", "-c", "echo ready; echo noise; sleep 2; echo ready >&2; sleep 300"},
+			WaitingFor: wait.ForLog("ready").WithStream(wait.LogStreamStderr).WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	// the container is only ready once the message is printed to stderr
+	stderr, err := ctr.(*DockerContainer).LogsText(ctx, container.LogsOptions{ShowStderr: true})
+	require.NoError(t, err)
+	require.Equal(t, "ready\n", stderr)
+}
+
+func TestDockerContainerDiff(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:      "docker.io/alpine:latest",
+			Cmd:        []string{"sh", "-c", "echo hello > /tmp/created.txt"},
+			WaitingFor: wait.ForExit(),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	changes, err := ctr.(*DockerContainer).Diff(ctx)
+	require.NoError(t, err)
+	require.Contains(t, changes, container.FilesystemChange{Kind: container.ChangeAdd, Path: "/tmp/created.txt"})
+}
+
+func TestPortOnHostIP(t *testing.T) {
+	ports := nat.PortMap{
+		"80/tcp": []nat.PortBinding{
+			{HostIP: "0.0.0.0", HostPort: "32768"},
+			{HostIP: "::", HostPort: "32769"},
+			{HostIP: "127.0.0.1", HostPort: "32770"},
+		},
+		"53/udp": []nat.PortBinding{
+			{HostIP: "127.0.0.1", HostPort: "32771"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		port    nat.Port
+		hostIP  string
+		want    nat.Port
+		wantErr bool
+	}{
+		{name: "ipv4", port: "80/tcp", hostIP: "0.0.0.0", want: "32768/tcp"},
+		{name: "ipv6", port: "80/tcp", hostIP: "::", want: "32769/tcp"},
+		{name: "ipv6-expanded", port: "80/tcp", hostIP: "0:0:0:0:0:0:0:0", want: "32769/tcp"},
+		{name: "loopback", port: "80/tcp", hostIP: "127.0.0.1", want: "32770/tcp"},
+		{name: "udp", port: "53/udp", hostIP: "127.0.0.1", want: "32771/udp"},
+		{name: "no-binding-for-ip", port: "53/udp", hostIP: "0.0.0.0", wantErr: true},
+		{name: "wrong-protocol", port: "80/udp", hostIP: "0.0.0.0", wantErr: true},
+		{name: "not-bound", port: "8080/tcp", hostIP: "0.0.0.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := portOnHostIP(ports, tt.port, net.ParseIP(tt.hostIP))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDockerContainerMappedPortOnHostIP(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:        nginxAlpineImage,
+			ExposedPorts: []string{"127.0.0.1::" + nginxDefaultPort},
+			WaitingFor:   wait.ForListeningPort(nginxDefaultPort),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	dc := ctr.(*DockerContainer)
+
+	port, err := dc.MappedPortOnHostIP(ctx, nginxDefaultPort, "127.0.0.1")
+	require.NoError(t, err)
+
+	inspect, err := dc.Inspect(ctx)
+	require.NoError(t, err)
+	bindings := inspect.NetworkSettings.Ports[nginxDefaultPort]
+	require.Len(t, bindings, 1)
+	require.Equal(t, bindings[0].HostPort, port.Port())
+
+	_, err = dc.MappedPortOnHostIP(ctx, nginxDefaultPort, "0.0.0.0")
+	require.Error(t, err)
+
+	_, err = dc.MappedPortOnHostIP(ctx, nginxDefaultPort, "not-an-ip")
+	require.Error(t, err)
+}
+
+func TestDockerContainerStopStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	var events []string
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: nginxAlpineImage,
+			StopStrategy: StopStrategyFunc(func(ctx context.Context, c Container) error {
+				// the container is still running, so the drain command can be executed
+				code, _, err := c.Exec(ctx, []string{"touch", "/tmp/drained"})
+				if err != nil {
+					return err
+				}
+				if code != 0 {
+					return fmt.Errorf("drain exit code %d", code)
+				}
+
+				events = append(events, "drained")
+				return nil
+			}),
+			LifecycleHooks: []ContainerLifecycleHooks{
+				{
+					PostStops: []ContainerHook{
+						func(ctx context.Context, c Container) error {
+							events = append(events, "stopped")
+							return nil
+						},
+					},
+				},
+			},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	timeout := 10 * time.Second
+	require.NoError(t, ctr.Stop(ctx, &timeout))
+	require.Equal(t, []string{"drained", "stopped"}, events)
+
+	t.Run("error-aborts-stop", func(t *testing.T) {
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image: nginxAlpineImage,
+				StopStrategy: StopStrategyFunc(func(context.Context, Container) error {
+					return errors.New("drain failed")
+				}),
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		err = ctr.Stop(ctx, &timeout)
+		require.ErrorContains(t, err, "stop strategy: drain failed")
+
+		state, err := ctr.State(ctx)
+		require.NoError(t, err)
+		require.True(t, state.Running)
+	})
+}
+
+func TestDockerContainerReadOnlyRootfs(t *testing.T) {
+	ctx := context.Background()
+
+	req := GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:          "docker.io/alpine:latest",
+			Entrypoint:     []string{"tail", "-f", "/dev/null"},
+			ReadOnlyRootfs: true,
+		},
+		Started: true,
+	}
+	require.NoError(t, WithTmpfsMount("/scratch", TmpfsOptions{})(&req))
+
+	ctr, err := GenericContainer(ctx, req)
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	inspect, err := ctr.Inspect(ctx)
+	require.NoError(t, err)
+	require.True(t, inspect.HostConfig.ReadonlyRootfs)
+
+	code, _, err := ctr.Exec(ctx, []string{"touch", "/file"})
+	require.NoError(t, err)
+	require.NotZero(t, code, "writing to the root filesystem should fail")
+
+	code, _, err = ctr.Exec(ctx, []string{"touch", "/scratch/file"})
+	require.NoError(t, err)
+	require.Zero(t, code, "writing to the tmpfs mount should succeed")
+}
+
+func TestDockerContainerName(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("requested", func(t *testing.T) {
+		name := fmt.Sprintf("tc-name-%d", rand.Int())
+
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image: nginxAlpineImage,
+				Name:  name,
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		got, err := ctr.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, name, got)
+
+		// the name reflects the renames of the container
+		renamed := name + "-renamed"
+		dc := ctr.(*DockerContainer)
+		require.NoError(t, dc.provider.client.ContainerRename(ctx, dc.ID, renamed))
+
+		got, err = ctr.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, renamed, got)
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image: nginxAlpineImage,
+			},
+			Started: true,
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		inspect, err := ctr.Inspect(ctx)
+		require.NoError(t, err)
+
+		got, err := ctr.Name(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, got)
+		require.Equal(t, "/"+got, inspect.Name)
+	})
+}
+
+func TestDockerContainerWaitForExecOutput(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: "docker.io/alpine:latest",
+			// the status changes after a delay
+			Cmd: []string{"sh", "-c", "echo DN > /tmp/status; sleep 2; echo UN > /tmp/status; sleep 300"},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	dc := ctr.(*DockerContainer)
+
+	err = dc.WaitForExecOutput(ctx, []string{"cat", "/tmp/status"}, regexp.MustCompile(`^UN`), 500*time.Millisecond)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, `last output: "DN\n"`)
+
+	err = dc.WaitForExecOutput(ctx, []string{"cat", "/tmp/status"}, regexp.MustCompile(`^UN`), 10*time.Second)
+	require.NoError(t, err)
+}
+
+func TestDockerContainerResizeExecTTY(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: nginxAlpineImage,
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	dc := ctr.(*DockerContainer)
+
+	var resizeErr error
+	// the command waits for the resize before printing the columns of the terminal
+	code, reader, err := dc.Exec(ctx, []string{"sh", "-c", "sleep 1; stty size | cut -d ' ' -f 2"},
+		tcexec.WithTTY(),
+		tcexec.WithExecIDHandler(func(execID string) {
+			resizeErr = dc.ResizeExecTTY(ctx, execID, 40, 123)
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, resizeErr)
+	require.Zero(t, code)
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "123", strings.TrimSpace(string(output)))
+}
+
+func TestDockerContainerTerminateAutoRemoved(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: "docker.io/alpine:latest",
+			Cmd:   []string{"sleep", "1"},
+			HostConfigModifier: func(hc *container.HostConfig) {
+				hc.AutoRemove = true
+			},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+
+	// wait for the container to exit and be removed by the Docker engine
+	var notFoundErr *ContainerNotFoundError
+	require.Eventually(t, func() bool {
+		_, err := ctr.State(ctx)
+		return errors.As(err, &notFoundErr)
+	}, 30*time.Second, 100*time.Millisecond)
+
+	require.Equal(t, ctr.GetContainerID(), notFoundErr.ID)
+	require.True(t, errdefs.IsNotFound(notFoundErr))
+
+	require.NoError(t, ctr.Terminate(ctx))
+}
+
+func TestDockerContainerTop(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: "docker.io/alpine:latest",
+			Cmd:   []string{"sleep", "1234"},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	top, err := ctr.(*DockerContainer).Top(ctx)
+	require.NoError(t, err)
+
+	cmdIndex := slices.Index(top.Titles, "CMD")
+	require.GreaterOrEqual(t, cmdIndex, 0, "titles: %v", top.Titles)
+
+	found := false
+	for _, process := range top.Processes {
+		if process[cmdIndex] == "sleep 1234" {
+			found = true
+			break
+		}
+	}
+	require.True(t, found, "process not found: %v", top.Processes)
+
+	// the ps arguments select the columns
+	top, err = ctr.(*DockerContainer).Top(ctx, "-o", "pid,comm")
+	require.NoError(t, err)
+	require.Equal(t, []string{"PID", "COMMAND"}, top.Titles)
+}
+
+func TestDockerContainerRestart(t *testing.T) {
+	ctx := context.Background()
+
+	var mtx sync.Mutex
+	var hooks []string
+	recordHook := func(name string) ContainerHook {
+		return func(_ context.Context, _ Container) error {
+			mtx.Lock()
+			defer mtx.Unlock()
+
+			hooks = append(hooks, name)
+			return nil
+		}
+	}
+
+	consumer := &restartLogConsumer{}
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:      "docker.io/alpine:latest",
+			Entrypoint: []string{"sh", "-c", "echo started; sleep 300"},
+			WaitingFor: wait.ForLog("started"),
+			LogConsumerCfg: &LogConsumerConfig{
+				Consumers: []LogConsumer{consumer},
+			},
+			LifecycleHooks: []ContainerLifecycleHooks{
+				{
+					PreStarts:   []ContainerHook{recordHook("pre-start")},
+					PostStarts:  []ContainerHook{recordHook("post-start")},
+					PostReadies: []ContainerHook{recordHook("post-ready")},
+					PreStops:    []ContainerHook{recordHook("pre-stop")},
+					PostStops:   []ContainerHook{recordHook("post-stop")},
+				},
+			},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	inspect, err := ctr.Inspect(ctx)
+	require.NoError(t, err)
+	startedAt := inspect.State.StartedAt
+
+	timeout := time.Second
+	require.NoError(t, ctr.(*DockerContainer).Restart(ctx, &timeout))
+
+	inspect, err = ctr.Inspect(ctx)
+	require.NoError(t, err)
+	require.True(t, inspect.State.Running)
+	require.NotEqual(t, startedAt, inspect.State.StartedAt)
+	require.True(t, ctr.(*DockerContainer).LifecycleState().Ready)
+
+	mtx.Lock()
+	require.Equal(t, []string{"pre-start", "post-start", "post-ready", "post-start", "post-ready"}, hooks)
+	mtx.Unlock()
+
+	// the logs of each run are consumed once
+	require.Eventually(t, func() bool {
+		return consumer.count() == 2
+	}, 10*time.Second, 100*time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	require.Equal(t, 2, consumer.count())
+}
+
+// restartLogConsumer counts the "started" lines of the logs.
+type restartLogConsumer struct {
+	mtx     sync.Mutex
+	started int
+}
+
+func (lc *restartLogConsumer) Accept(l Log) {
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+
+	lc.started += strings.Count(string(l.Content), "started")
+}
+
+func (lc *restartLogConsumer) count() int {
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+
+	return lc.started
+}
+
+func TestDockerContainerUnixSocketPath(t *testing.T) {
+	ctx := context.Background()
+
+	socketDir := t.TempDir()
+
+	// the socket is world-writable, so the host can dial it even if the tests do not run as root
+	req := GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			Image:      "docker.io/alpine/socat:1.8.0.0",
+			Cmd:        []string{"UNIX-LISTEN:/sockets/echo.sock,fork,mode=777", "EXEC:cat"},
+			WaitingFor: wait.ForExec([]string{"test", "-S", "/sockets/echo.sock"}),
+		},
+		Started: true,
+	}
+	require.NoError(t, WithUnixSocketDir(socketDir, "/sockets")(&req))
+
+	ctr, err := GenericContainer(ctx, req)
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.NoError(t, err)
+
+	socketPath, err := ctr.(*DockerContainer).UnixSocketPath(ctx, "/sockets/echo.sock")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(socketDir, "echo.sock"), socketPath)
+
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("hello\n"))
+	require.NoError(t, err)
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "hello\n", line)
+
+	_, err = ctr.(*DockerContainer).UnixSocketPath(ctx, "/tmp/other.sock")
+	require.Error(t, err)
+}
+
+func TestDockerContainerRestartCount(t *testing.T) {
+	ctx := context.Background()
+
+	// the workload exhausts the memory of the container once it's ready, so it's OOM-killed
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:         "docker.io/alpine:latest",
+			Entrypoint:    []string{"sh", "-c", "echo started; sleep 1; tail /dev/zero"},
+			WaitingFor:    wait.ForLog("started"),
+			RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 5},
+			HostConfigModifier: func(hc *container.HostConfig) {
+				hc.Memory = 16 * 1024 * 1024
+				hc.MemorySwap = hc.Memory
+			},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	dockerContainer := ctr.(*DockerContainer)
+
+	count, err := dockerContainer.RestartCount(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	require.Eventually(t, func() bool {
+		count, err := dockerContainer.RestartCount(ctx)
+		return err == nil && count > 0
+	}, 30*time.Second, 500*time.Millisecond)
+}
+
+func TestDockerContainerWaitForRestartCount(t *testing.T) {
+	ctx := context.Background()
+
+	// the container crashes right after starting, so it's restarted by the on-failure policy
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:         "docker.io/alpine:latest",
+			Entrypoint:    []string{"sh", "-c", "echo crashing; sleep 0.2; exit 1"},
+			WaitingFor:    wait.ForRestartCount(2).WithStartupTimeout(30 * time.Second),
+			RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 10},
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.NoError(t, err)
+
+	count, err := ctr.(*DockerContainer).RestartCount(ctx)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count, 2)
+
+	t.Run("stopped-restarting", func(t *testing.T) {
+		ctr, err := GenericContainer(ctx, GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image:         "docker.io/alpine:latest",
+				Entrypoint:    []string{"sh", "-c", "echo crashing; sleep 0.2; exit 1"},
+				WaitingFor:    wait.ForRestartCount(5).WithStartupTimeout(30 * time.Second),
+				RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 1},
+			},
+			Started: true,
+		})
+		terminateContainerOnEnd(t, ctx, ctr)
+		require.ErrorContains(t, err, "container stopped restarting after 1 restarts, expected 5")
+	})
+}
+
+func TestDockerContainerWaitForAndAfterRestart(t *testing.T) {
+	ctx := context.Background()
+
+	// the first run logs the ready message and crashes, and the next runs never log it again
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: "docker.io/alpine:latest",
+			Entrypoint: []string{"sh", "-c", "if [ -f /tmp/started ]; then sleep 3600; fi; " +
+				"touch /tmp/started; echo ready; sleep 1; exit 1"},
+			WaitingFor:    wait.ForLog("ready"),
+			RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyOnFailure, MaximumRetryCount: 1},
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		count, err := ctr.(*DockerContainer).RestartCount(ctx)
+		return err == nil && count > 0
+	}, 30*time.Second, 500*time.Millisecond)
+
+	// the ready message of the previous run must not be matched
+	err = wait.And(wait.ForLog("ready")).WithStartupTimeout(3*time.Second).WaitUntilReady(ctx, ctr)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDockerContainerRestartAutoRemove(t *testing.T) {
+	ctr := &DockerContainer{ID: "1234", autoRemove: true}
+
+	err := ctr.Restart(context.Background(), nil)
+	require.ErrorContains(t, err, "not supported for containers created with AutoRemove")
+}
+
+func TestIsImageDigest(t *testing.T) {
+	const digest = "sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1"
+
+	tests := []struct {
+		img  string
+		want bool
+	}{
+		{img: digest, want: true},
+		{img: "redis@" + digest, want: true},
+		{img: "docker.io/library/redis:7@" + digest, want: true},
+		{img: "redis:7", want: false},
+		{img: "redis", want: false},
+		{img: "sha256:4bcff639", want: false},
+		{img: "localhost:5000/sha256:latest", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.img, func(t *testing.T) {
+			require.Equal(t, tt.want, isImageDigest(tt.img))
+		})
+	}
+}
+
+func TestDockerProviderListContainers(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := NewDockerProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:      "docker.io/alpine:latest",
+			Entrypoint: []string{"tail", "-f", "/dev/null"},
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.NoError(t, err)
+
+	name, err := ctr.Name(ctx)
+	require.NoError(t, err)
+
+	expected := ContainerInfo{
+		ID:        ctr.GetContainerID(),
+		Image:     "docker.io/alpine:latest",
+		Name:      strings.TrimPrefix(name, "/"),
+		State:     "running",
+		SessionID: core.SessionID(),
+	}
+
+	containers, err := provider.ListContainers(ctx)
+	require.NoError(t, err)
+	require.Contains(t, containers, expected)
+	for _, c := range containers {
+		require.Equal(t, core.SessionID(), c.SessionID)
+	}
+
+	containers, err = provider.ListContainers(ctx, WithAllSessions())
+	require.NoError(t, err)
+	require.Contains(t, containers, expected)
+}
+
+func TestImageLocalOnly(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := NewDockerProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	// make sure the image is present locally, as if it was pre-loaded
+	require.NoError(t, provider.PullImage(ctx, "docker.io/alpine:latest"))
+
+	img, _, err := provider.client.ImageInspectWithRaw(ctx, "docker.io/alpine:latest")
+	require.NoError(t, err)
+
+	newRequest := func(t *testing.T, image string) GenericContainerRequest {
+		t.Helper()
+
+		req := GenericContainerRequest{
+			ProviderType: providerType,
+			ContainerRequest: ContainerRequest{
+				Image: image,
+				Cmd:   []string{"echo", "hello"},
+			},
+		}
+		require.NoError(t, WithImagePlatformAndLocalOnly()(&req))
+
+		return req
+	}
+
+	t.Run("present", func(t *testing.T) {
+		ctr, err := GenericContainer(ctx, newRequest(t, img.ID))
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := GenericContainer(ctx, newRequest(t, "sha256:0000000000000000000000000000000000000000000000000000000000000000"))
+		require.ErrorContains(t, err, "not present locally")
+	})
+
+	t.Run("always-pull", func(t *testing.T) {
+		req := newRequest(t, img.ID)
+		req.AlwaysPullImage = true
+
+		_, err := GenericContainer(ctx, req)
+		require.ErrorContains(t, err, "ImageLocalOnly and AlwaysPullImage cannot be used together")
+	})
+}
+
+func TestContainerWithExitCode(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		cmd     []string
+		wantErr bool
+	}{
+		{name: "exit-0", cmd: []string{"sh", "-c", "exit 0"}},
+		{name: "exit-2", cmd: []string{"sh", "-c", "exit 2"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr, err := GenericContainer(ctx, GenericContainerRequest{
+				ProviderType: providerType,
+				ContainerRequest: ContainerRequest{
+					Image:      "docker.io/alpine:latest",
+					Cmd:        tt.cmd,
+					WaitingFor: wait.ForExit().WithExitCode(0),
+				},
+				Started: true,
+			})
+			terminateContainerOnEnd(t, ctx, ctr)
+
+			if tt.wantErr {
+				require.ErrorContains(t, err, "container exited with code 2, expected 0")
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDockerContainerStopWithForcedKill(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: "docker.io/alpine:latest",
+			// the shell ignores SIGTERM, so the container can only be killed
+			Cmd:        []string{"sh", "-c", "trap '' TERM; while true; do sleep 1; done"},
+			WaitingFor: wait.ForExec([]string{"true"}),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	timeout := time.Second
+	err = ctr.(*DockerContainer).StopWithOptions(ctx, &timeout, WithForcedKill(10*time.Second))
+	require.NoError(t, err)
+
+	state, err := ctr.State(ctx)
+	require.NoError(t, err)
+	require.False(t, state.Running)
+	require.Equal(t, 137, state.ExitCode, "container should have been killed")
+}
+
+// inspectMockCli is a client which counts the inspections of a container,
+// mapping its port to a different host port each time it's started.
+type inspectMockCli struct {
+	client.APIClient
+
+	inspects int
+	hostPort int
+}
+
+func (f *inspectMockCli) ContainerInspect(_ context.Context, _ string) (types.ContainerJSON, error) {
+	f.inspects++
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{HostConfig: &container.HostConfig{}},
+		NetworkSettings: &types.NetworkSettings{
+			NetworkSettingsBase: types.NetworkSettingsBase{
+				Ports: nat.PortMap{"6379/tcp": {{HostIP: "0.0.0.0", HostPort: strconv.Itoa(f.hostPort)}}},
+			},
+		},
+	}, nil
+}
+
+func (f *inspectMockCli) ContainerStart(_ context.Context, _ string, _ container.StartOptions) error {
+	f.hostPort++
+	return nil
+}
+
+func (f *inspectMockCli) Close() error {
+	return nil
+}
+
+func TestDockerContainer_MappedPortInspectsOnce(t *testing.T) {
+	ctx := context.Background()
+
+	m := &inspectMockCli{hostPort: 32768}
+	c := &DockerContainer{ID: "0123456789abcdef", provider: &DockerProvider{client: m}, logger: Logger}
+
+	for i := 0; i < 2; i++ {
+		port, err := c.MappedPort(ctx, "6379/tcp")
+		require.NoError(t, err)
+		require.Equal(t, "32768", port.Port())
+	}
+	require.Equal(t, 1, m.inspects)
+
+	// starting the container again invalidates the cached details
+	require.NoError(t, c.Start(ctx))
+
+	port, err := c.MappedPort(ctx, "6379/tcp")
+	require.NoError(t, err)
+	require.Equal(t, "32769", port.Port())
+	require.Equal(t, 2, m.inspects)
+}
+
+// stopMockCli is a client whose stop requests time out, reporting the container
+// as running or not, and tracking the deadline of the stop request and the kills.
+type stopMockCli struct {
+	client.APIClient
+
+	running   bool
+	stopAfter time.Duration
+	kills     int
+}
+
+func (f *stopMockCli) ContainerStop(ctx context.Context, _ string, _ container.StopOptions) error {
+	deadline, _ := ctx.Deadline()
+	f.stopAfter = time.Until(deadline)
+	return context.DeadlineExceeded
+}
+
+func (f *stopMockCli) ContainerInspect(_ context.Context, _ string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{State: &types.ContainerState{Running: f.running}},
+	}, nil
+}
+
+func (f *stopMockCli) ContainerKill(_ context.Context, _ string, _ string) error {
+	f.kills++
+	return nil
+}
+
+func (f *stopMockCli) ContainerWait(_ context.Context, _ string, _ container.WaitCondition) (<-chan container.WaitResponse, <-chan error) {
+	waitC := make(chan container.WaitResponse, 1)
+	waitC <- container.WaitResponse{}
+	return waitC, make(chan error)
+}
+
+func (f *stopMockCli) Close() error {
+	return nil
+}
+
+func TestDockerContainer_stopOrKill(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		running   bool
+		wantKills int
+	}{
+		{
+			name:      "kill when still running",
+			timeout:   time.Second,
+			running:   true,
+			wantKills: 1,
+		},
+		{
+			name:      "no kill when stopped by the engine",
+			timeout:   time.Second,
+			running:   false,
+			wantKills: 0,
+		},
+		{
+			name:      "negative timeout is clamped",
+			timeout:   -time.Second,
+			running:   true,
+			wantKills: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &stopMockCli{running: tt.running}
+			c := &DockerContainer{ID: "0123456789abcdef", provider: &DockerProvider{client: m}, logger: Logger}
+
+			err := c.stopOrKill(context.Background(), &tt.timeout, container.StopOptions{}, time.Second)
+			require.NoError(t, err)
+
+			grace := max(tt.timeout, 0)
+			assert.Greater(t, m.stopAfter, grace+stopRequestMargin-time.Second, "the stop request must outlive the grace period")
+			assert.LessOrEqual(t, m.stopAfter, grace+stopRequestMargin)
+			assert.Equal(t, tt.wantKills, m.kills)
+		})
+	}
+}
+
+func TestStopTimeoutSeconds(t *testing.T) {
+	tests := []struct {
+		grace time.Duration
+		want  int
+	}{
+		{grace: 0, want: 0},
+		{grace: 300 * time.Millisecond, want: 1},
+		{grace: time.Second, want: 1},
+		{grace: 1500 * time.Millisecond, want: 2},
+		{grace: 30 * time.Second, want: 30},
+		{grace: -500 * time.Millisecond, want: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.grace.String(), func(t *testing.T) {
+			require.Equal(t, tt.want, stopTimeoutSeconds(tt.grace))
+		})
+	}
+}
+
+func TestStopTimeoutOf(t *testing.T) {
+	seconds := func(n int) *int { return &n }
+
+	require.Nil(t, stopTimeoutOf(nil))
+	require.Nil(t, stopTimeoutOf(&container.Config{}))
+	require.Nil(t, stopTimeoutOf(&container.Config{StopTimeout: seconds(0)}))
+	// a negative timeout makes the engine wait forever, so it keeps the default
+	require.Nil(t, stopTimeoutOf(&container.Config{StopTimeout: seconds(-1)}))
+
+	timeout := stopTimeoutOf(&container.Config{StopTimeout: seconds(5)})
+	require.NotNil(t, timeout)
+	require.Equal(t, 5*time.Second, *timeout)
+}
+
+// networkConnectMockCli is a mock implementation of client.APIClient, failing to connect
+// the containers to networks and recording the containers that are removed.
+type networkConnectMockCli struct {
+	client.APIClient
+
+	connectErr error
+	removeErr  error
+	removed    []string
+}
+
+func (m *networkConnectMockCli) NetworkConnect(_ context.Context, _, _ string, _ *network.EndpointSettings) error {
+	return m.connectErr
+}
+
+func (m *networkConnectMockCli) ContainerRemove(_ context.Context, containerID string, _ container.RemoveOptions) error {
+	m.removed = append(m.removed, containerID)
+	return m.removeErr
+}
+
+func TestDockerProvider_connectToReaperNetwork(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		m := &networkConnectMockCli{}
+		p := &DockerProvider{client: m, DockerProviderOptions: &DockerProviderOptions{reaperNetwork: "reaper-net"}}
+
+		require.NoError(t, p.connectToReaperNetwork(context.Background(), "0123456789ab"))
+		require.Empty(t, m.removed)
+	})
+
+	t.Run("connect-error/removes-container", func(t *testing.T) {
+		connectErr := errors.New("connect failed")
+		m := &networkConnectMockCli{connectErr: connectErr}
+		p := &DockerProvider{client: m, DockerProviderOptions: &DockerProviderOptions{reaperNetwork: "reaper-net"}}
+
+		err := p.connectToReaperNetwork(context.Background(), "0123456789ab")
+		require.ErrorIs(t, err, connectErr)
+		require.ErrorContains(t, err, "connect container to the reaper network reaper-net")
+		require.Equal(t, []string{"0123456789ab"}, m.removed)
+	})
+
+	t.Run("connect-error/remove-error", func(t *testing.T) {
+		connectErr := errors.New("connect failed")
+		removeErr := errors.New("remove failed")
+		m := &networkConnectMockCli{connectErr: connectErr, removeErr: removeErr}
+		p := &DockerProvider{client: m, DockerProviderOptions: &DockerProviderOptions{reaperNetwork: "reaper-net"}}
+
+		err := p.connectToReaperNetwork(context.Background(), "0123456789ab")
+		require.ErrorIs(t, err, connectErr)
+		require.ErrorIs(t, err, removeErr)
+	})
+}
+
+func TestDockerContainerStopTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	stopTimeout := 2 * time.Second
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image: "docker.io/alpine:latest",
+			// the shell ignores SIGTERM, so the container is killed once the grace period is over
+			Cmd:         []string{"sh", "-c", "trap '' TERM; while true; do sleep 1; done"},
+			WaitingFor:  wait.ForExec([]string{"true"}),
+			StopTimeout: &stopTimeout,
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.NoError(t, err)
+
+	inspect, err := ctr.Inspect(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, inspect.Config.StopTimeout)
+	require.Equal(t, 2, *inspect.Config.StopTimeout)
+
+	t.Run("stop", func(t *testing.T) {
+		start := time.Now()
+		require.NoError(t, ctr.Stop(ctx, nil))
+		require.GreaterOrEqual(t, time.Since(start), stopTimeout)
+		require.Less(t, time.Since(start), 10*time.Second, "the engine default should not be used")
+
+		state, err := ctr.State(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 137, state.ExitCode, "container should have been killed")
+	})
+
+	t.Run("terminate", func(t *testing.T) {
+		require.NoError(t, ctr.Start(ctx))
+
+		start := time.Now()
+		require.NoError(t, ctr.Terminate(ctx))
+		require.GreaterOrEqual(t, time.Since(start), stopTimeout, "the container should be stopped before being removed")
+	})
+}
+
+func TestContainerExitingDuringStartupFailsFast(t *testing.T) {
+	ctx := context.Background()
+
+	start := time.Now()
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ProviderType: providerType,
+		ContainerRequest: ContainerRequest{
+			Image:        "docker.io/alpine:latest",
+			ExposedPorts: []string{"8080/tcp"},
+			Cmd:          []string{"sh", "-c", "echo booting; sleep 1; echo fatal error; exit 3"},
+			WaitingFor: wait.ForAll(
+				wait.ForLog("ready"),
+				wait.ForListeningPort("8080/tcp"),
+			).WithDeadline(60 * time.Second),
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+
+	require.ErrorContains(t, err, "container exited with code 3")
+	require.ErrorContains(t, err, "fatal error")
+	require.Less(t, time.Since(start), 30*time.Second)
+}
+
+func TestContainerWithNoUserID(t *testing.T) {
+	ctx := context.Background()
+	req := ContainerRequest{
+		Image:      "docker.io/alpine:latest",
+		Cmd:        []string{"sh", "-c", "id -u"},
 		WaitingFor: wait.ForExit(),
 	}
 	ctr, err := GenericContainer(ctx, GenericContainerRequest{
@@ -2232,6 +3835,120 @@ func TestDockerProvider_waitContainerCreation_retries(t *testing.T) {
 	}
 }
 
+// slowPullMockCli is a client whose image pulls take some time, tracking
+// the number of pulls per image and the maximum number of concurrent pulls.
+type slowPullMockCli struct {
+	client.APIClient
+
+	mu            sync.Mutex
+	pulls         map[string]int
+	active        int
+	maxConcurrent int
+}
+
+func (f *slowPullMockCli) ImagePull(ctx context.Context, ref string, _ image.PullOptions) (io.ReadCloser, error) {
+	f.mu.Lock()
+	f.pulls[ref]++
+	f.active++
+	if f.active > f.maxConcurrent {
+		f.maxConcurrent = f.active
+	}
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.active--
+		f.mu.Unlock()
+	}()
+
+	select {
+	case <-time.After(100 * time.Millisecond):
+		return io.NopCloser(&bytes.Buffer{}), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (f *slowPullMockCli) Close() error {
+	return nil
+}
+
+func TestDockerProvider_attemptToPullImage_concurrency(t *testing.T) {
+	t.Run("same image is pulled once", func(t *testing.T) {
+		p, err := NewDockerProvider()
+		require.NoError(t, err)
+		m := &slowPullMockCli{pulls: map[string]int{}}
+		p.client = m
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, p.attemptToPullImage(context.Background(), "sameTag", image.PullOptions{}))
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, map[string]int{"sameTag": 1}, m.pulls)
+	})
+
+	t.Run("concurrent pulls are limited", func(t *testing.T) {
+		SetGlobalMaxConcurrentPulls(2)
+		t.Cleanup(func() {
+			SetGlobalMaxConcurrentPulls(0)
+		})
+
+		p, err := NewDockerProvider()
+		require.NoError(t, err)
+		m := &slowPullMockCli{pulls: map[string]int{}}
+		p.client = m
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, p.attemptToPullImage(context.Background(), fmt.Sprintf("tag%d", i), image.PullOptions{}))
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Len(t, m.pulls, 6)
+		assert.Equal(t, 2, m.maxConcurrent)
+	})
+
+	t.Run("cancelling the first caller does not fail the others", func(t *testing.T) {
+		p, err := NewDockerProvider()
+		require.NoError(t, err)
+		m := &slowPullMockCli{pulls: map[string]int{}}
+		p.client = m
+
+		firstCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		firstErr := make(chan error, 1)
+		go func() {
+			firstErr <- p.attemptToPullImage(firstCtx, "sharedTag", image.PullOptions{})
+		}()
+
+		// let the first caller start the pull before joining it
+		time.Sleep(20 * time.Millisecond)
+
+		secondErr := make(chan error, 1)
+		go func() {
+			secondErr <- p.attemptToPullImage(context.Background(), "sharedTag", image.PullOptions{})
+		}()
+
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+
+		require.ErrorIs(t, <-firstErr, context.Canceled)
+		require.NoError(t, <-secondErr)
+		assert.Equal(t, map[string]int{"sharedTag": 1}, m.pulls)
+	})
+}
+
 func TestDockerProvider_attemptToPullImage_retries(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -2292,3 +4009,51 @@ func TestDockerProvider_attemptToPullImage_retries(t *testing.T) {
 		})
 	}
 }
+
+func TestDockerProvider_attemptToPullImage_pullError(t *testing.T) {
+	tests := []struct {
+		name         string
+		errReturned  error
+		unauthorized bool
+	}{
+		{
+			name:         "unauthorized",
+			errReturned:  errdefs.Unauthorized(errors.New("authentication required")),
+			unauthorized: true,
+		},
+		{
+			name:         "forbidden",
+			errReturned:  errdefs.Forbidden(errors.New("forbidden")),
+			unauthorized: true,
+		},
+		{
+			name:         "private image reported as not found",
+			errReturned:  errdefs.NotFound(errors.New("pull access denied for private/image, repository does not exist or may require 'docker login'")),
+			unauthorized: true,
+		},
+		{
+			name:         "not found",
+			errReturned:  errdefs.NotFound(errors.New("manifest for redis:0.0.0 not found: manifest unknown")),
+			unauthorized: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewDockerProvider()
+			require.NoError(t, err)
+			p.client = &errMockCli{err: tt.errReturned}
+
+			err = p.attemptToPullImage(context.Background(), "registry.example.com/private/image:1.0", image.PullOptions{})
+
+			var pullErr *PullError
+			require.ErrorAs(t, err, &pullErr)
+			require.Equal(t, "registry.example.com", pullErr.Registry)
+			require.Equal(t, "registry.example.com/private/image:1.0", pullErr.Reference)
+			require.False(t, pullErr.CredentialsAttempted)
+			require.Equal(t, tt.unauthorized, pullErr.Unauthorized)
+			require.Equal(t, tt.unauthorized, errors.Is(err, ErrImagePullUnauthorized))
+			require.ErrorIs(t, err, tt.errReturned)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// EnvSource is where the value of an environment variable of a container comes from.
+type EnvSource string
+
+const (
+	// EnvSourceImage is an environment variable declared by the image, e.g. with ENV in its Dockerfile.
+	EnvSourceImage EnvSource = "image"
+	// EnvSourceRequest is an environment variable set by the container request, e.g. its Env field
+	// or a config modifier, which takes precedence over the value declared by the image.
+	EnvSourceRequest EnvSource = "request"
+	// EnvSourceEngine is an environment variable added by the Docker engine, e.g. a default PATH.
+	EnvSourceEngine EnvSource = "engine"
+)
+
+// EnvVar is an environment variable of a container, with the source of its value.
+type EnvVar struct {
+	Value  string    // the value in the container
+	Source EnvSource // where the value comes from
+	// ImageValue is the value declared by the image, which differs from Value
+	// if the request overrides it. It's empty if the image does not declare it.
+	ImageValue string
+}
+
+// EffectiveEnv returns the environment variables of the container, by name, with the source
+// of their value, to find out whether the request overrides a value declared by the image.
+// For a container which was not created by the provider, e.g. one reused by name, the
+// request is unknown, so a value differing from the one of the image is tagged as a request one.
+func (c *DockerContainer) EffectiveEnv(ctx context.Context) (map[string]EnvVar, error) {
+	inspect, err := c.inspectRawContainer(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("inspect container: %w", err)
+	}
+
+	img, _, err := c.provider.client.ImageInspectWithRaw(ctx, inspect.Image)
+	if err != nil {
+		return nil, fmt.Errorf("inspect image: %w", err)
+	}
+
+	var containerEnv, imageEnv []string
+	if inspect.Config != nil {
+		containerEnv = inspect.Config.Env
+	}
+	if img.Config != nil {
+		imageEnv = img.Config.Env
+	}
+
+	return effectiveEnv(containerEnv, imageEnv, c.requestEnv), nil
+}
+
+// effectiveEnv tags the environment variables of the container with the source of their value.
+// The request environment is nil if it's unknown.
+func effectiveEnv(containerEnv, imageEnv, requestEnv []string) map[string]EnvVar {
+	image := envMap(imageEnv)
+	request := envMap(requestEnv)
+
+	env := make(map[string]EnvVar, len(containerEnv))
+	for k, v := range envMap(containerEnv) {
+		imageValue, inImage := image[k]
+		_, inRequest := request[k]
+
+		source := EnvSourceEngine
+		switch {
+		case inRequest:
+			source = EnvSourceRequest
+		case inImage && (requestEnv != nil || imageValue == v):
+			source = EnvSourceImage
+		case requestEnv == nil:
+			source = EnvSourceRequest
+		}
+
+		env[k] = EnvVar{Value: v, Source: source, ImageValue: imageValue}
+	}
+
+	return env
+}
+
+// envMap converts a list of KEY=value environment variables to a map, or nil if the list is empty.
+func envMap(env []string) map[string]string {
+	if len(env) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		m[k] = v
+	}
+
+	return m
+}
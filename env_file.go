@@ -0,0 +1,127 @@
+package testcontainers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// envFileKeyRegex matches the valid names of the variables of a dotenv file
+var envFileKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseEnvFile parses the content of a dotenv file, with a KEY=VALUE entry per line.
+// Empty lines and lines starting with "#" are ignored, and each entry can be prefixed with "export ".
+// Values can be single-quoted, taken literally, or double-quoted, supporting the \n, \t, \" and \\ escapes.
+// Unquoted values end at the first "#" preceded by a whitespace, which starts a comment.
+func parseEnvFile(r io.Reader) (map[string]string, error) {
+	env := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	number := 0
+	for scanner.Scan() {
+		number++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: missing '=' in %q", number, line)
+		}
+
+		key = strings.TrimSpace(key)
+		if !envFileKeyRegex.MatchString(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", number, key)
+		}
+
+		value, err := parseEnvFileValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", number, key, err)
+		}
+
+		env[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+// parseEnvFileValue parses the value of an entry of a dotenv file, removing its quotes and trailing comment.
+func parseEnvFileValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch quote := value[0]; quote {
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", errors.New("unterminated single-quoted value")
+		}
+
+		if err := checkEnvFileTrailer(value[end+2:]); err != nil {
+			return "", err
+		}
+
+		return value[1 : end+1], nil
+	case '"':
+		var sb strings.Builder
+		for i := 1; i < len(value); i++ {
+			c := value[i]
+			switch {
+			case c == '"':
+				if err := checkEnvFileTrailer(value[i+1:]); err != nil {
+					return "", err
+				}
+				return sb.String(), nil
+			case c == '\\' && i+1 < len(value):
+				i++
+				switch value[i] {
+				case 'n':
+					sb.WriteByte('\n')
+				case 't':
+					sb.WriteByte('\t')
+				case '"', '\\':
+					sb.WriteByte(value[i])
+				default:
+					sb.WriteByte('\\')
+					sb.WriteByte(value[i])
+				}
+			default:
+				sb.WriteByte(c)
+			}
+		}
+
+		return "", errors.New("unterminated double-quoted value")
+	}
+
+	// an unquoted value ends at the first comment
+	for i := 1; i < len(value); i++ {
+		if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+			value = value[:i]
+			break
+		}
+	}
+
+	return strings.TrimSpace(value), nil
+}
+
+// checkEnvFileTrailer checks that only a comment follows a quoted value.
+func checkEnvFileTrailer(trailer string) error {
+	trailer = strings.TrimSpace(trailer)
+	if trailer != "" && !strings.HasPrefix(trailer, "#") {
+		return fmt.Errorf("unexpected characters after the quoted value: %q", trailer)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package testcontainers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		content := `# comment
+EMPTY=
+PLAIN=value
+export EXPORTED=exported
+  SPACED = spaced value  
+COMMENTED=value # comment
+HASH=value#not-a-comment
+SINGLE='single $quoted # not a comment'
+DOUBLE="double \"quoted\"\nvalue" # comment
+EQUALS=a=b
+`
+
+		env, err := parseEnvFile(strings.NewReader(content))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"EMPTY":     "",
+			"PLAIN":     "value",
+			"EXPORTED":  "exported",
+			"SPACED":    "spaced value",
+			"COMMENTED": "value",
+			"HASH":      "value#not-a-comment",
+			"SINGLE":    "single $quoted # not a comment",
+			"DOUBLE":    "double \"quoted\"\nvalue",
+			"EQUALS":    "a=b",
+		}, env)
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "missing-equals", content: "KEY=value\nINVALID\n", wantErr: "line 2: missing '='"},
+		{name: "invalid-name", content: "1KEY=value\n", wantErr: `line 1: invalid variable name "1KEY"`},
+		{name: "unterminated-single-quote", content: "KEY='value\n", wantErr: "line 1: KEY: unterminated single-quoted value"},
+		{name: "unterminated-double-quote", content: "KEY=\"value\n", wantErr: "line 1: KEY: unterminated double-quoted value"},
+		{name: "trailing-characters", content: "KEY=\"value\" other\n", wantErr: "line 1: KEY: unexpected characters after the quoted value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseEnvFile(strings.NewReader(tt.content))
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package testcontainers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveEnv(t *testing.T) {
+	containerEnv := []string{"PATH=/usr/bin", "LANG=C.UTF-8", "APP_MODE=test", "DEBUG=1", "HOSTNAME_SUFFIX="}
+	imageEnv := []string{"LANG=C.UTF-8", "APP_MODE=production"}
+
+	t.Run("known-request", func(t *testing.T) {
+		env := effectiveEnv(containerEnv, imageEnv, []string{"APP_MODE=test", "DEBUG=1", "HOSTNAME_SUFFIX="})
+
+		require.Equal(t, map[string]EnvVar{
+			"PATH":            {Value: "/usr/bin", Source: EnvSourceEngine},
+			"LANG":            {Value: "C.UTF-8", Source: EnvSourceImage, ImageValue: "C.UTF-8"},
+			"APP_MODE":        {Value: "test", Source: EnvSourceRequest, ImageValue: "production"},
+			"DEBUG":           {Value: "1", Source: EnvSourceRequest},
+			"HOSTNAME_SUFFIX": {Value: "", Source: EnvSourceRequest},
+		}, env)
+	})
+
+	t.Run("unknown-request", func(t *testing.T) {
+		env := effectiveEnv(containerEnv, imageEnv, nil)
+
+		require.Equal(t, EnvVar{Value: "C.UTF-8", Source: EnvSourceImage, ImageValue: "C.UTF-8"}, env["LANG"])
+		require.Equal(t, EnvVar{Value: "test", Source: EnvSourceRequest, ImageValue: "production"}, env["APP_MODE"])
+		require.Equal(t, EnvVar{Value: "1", Source: EnvSourceRequest}, env["DEBUG"])
+	})
+}
+
+func TestDockerContainer_EffectiveEnv(t *testing.T) {
+	ctx := context.Background()
+
+	// the nginx image declares the NGINX_VERSION and PKG_RELEASE environment variables
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			Image: nginxAlpineImage,
+			Env: map[string]string{
+				"NGINX_VERSION": "overridden",
+				"FOO":           "bar",
+			},
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.NoError(t, err)
+
+	env, err := ctr.(*DockerContainer).EffectiveEnv(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, "overridden", env["NGINX_VERSION"].Value)
+	require.Equal(t, EnvSourceRequest, env["NGINX_VERSION"].Source)
+	require.NotEmpty(t, env["NGINX_VERSION"].ImageValue)
+	require.NotEqual(t, "overridden", env["NGINX_VERSION"].ImageValue)
+
+	require.Equal(t, EnvVar{Value: "bar", Source: EnvSourceRequest}, env["FOO"])
+
+	require.Equal(t, EnvSourceImage, env["PKG_RELEASE"].Source)
+	require.Equal(t, env["PKG_RELEASE"].ImageValue, env["PKG_RELEASE"].Value)
+}
@@ -11,7 +11,9 @@ import (
 // ProcessOptions defines options applicable to the reader processor
 type ProcessOptions struct {
 	ExecConfig container.ExecOptions
+	ExecID     string
 	Reader     io.Reader
+	Stdin      io.Reader
 }
 
 // NewProcessOptions returns a new ProcessOptions instance
@@ -60,6 +62,43 @@ func WithEnv(env []string) ProcessOption {
 	})
 }
 
+// WithStdin returns a [ProcessOption] that writes the content of the reader to the stdin
+// of the command, closing it once the reader is drained, so commands consuming their input
+// until EOF, e.g. "cat > /tmp/file", can complete. It can be combined with [Multiplexed].
+// The stdin is written while the output of the command is read, by [Multiplexed] if it's passed,
+// or otherwise into a buffer holding the whole output, with Docker's multiplexing headers, until
+// the command exits. The exit code is only read once the reader is drained, or the context of the
+// execution is done, so the exit code reflects the command having received its whole input.
+func WithStdin(stdin io.Reader) ProcessOption {
+	return ProcessOptionFunc(func(opts *ProcessOptions) {
+		opts.ExecConfig.AttachStdin = true
+		opts.Stdin = stdin
+	})
+}
+
+// WithTTY returns a [ProcessOption] that allocates a pseudo-TTY for the command,
+// so programs that adapt to the size of the terminal can be run.
+// The output of a TTY is raw, so it must not be combined with [Multiplexed].
+func WithTTY() ProcessOption {
+	return ProcessOptionFunc(func(opts *ProcessOptions) {
+		opts.ExecConfig.Tty = true
+	})
+}
+
+// WithExecIDHandler returns a [ProcessOption] that calls the handler with the ID of the exec
+// once the command is running, and before waiting for it to finish. E.g. it can be used to
+// resize the TTY of the command with the ResizeExecTTY method of the container.
+func WithExecIDHandler(handler func(execID string)) ProcessOption {
+	return ProcessOptionFunc(func(opts *ProcessOptions) {
+		// the exec ID is only known once the exec is created
+		if opts.ExecID == "" {
+			return
+		}
+
+		handler(opts.ExecID)
+	})
+}
+
 // Multiplexed returns a [ProcessOption] that configures the command execution
 // to combine stdout and stderr into a single stream without Docker's multiplexing headers.
 func Multiplexed() ProcessOption {
@@ -2,11 +2,14 @@ package testcontainers
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 )
@@ -30,8 +33,42 @@ func isDir(path string) (bool, error) {
 	return false, nil
 }
 
-// tarDir compress a directory using tar + gzip algorithms
-func tarDir(src string, fileMode int64) (*bytes.Buffer, error) {
+// CopyOption is a functional option to configure how files are copied into a container.
+type CopyOption func(*copyOptions)
+
+// copyOptions holds the configuration applied when copying files into a container.
+type copyOptions struct {
+	dereferenceSymlinks bool
+	tarTransform        TarTransform
+}
+
+// TarTransform transforms an entry of the tar stream copied into a container, returning its new content.
+// The header can be modified, e.g. to change the mode of the entry, and its size is updated to the one
+// of the returned content. For the entries that are not regular files, e.g. directories or symlinks,
+// the content is nil and the returned one is ignored. Returning an error aborts the copy.
+type TarTransform func(hdr *tar.Header, content []byte) ([]byte, error)
+
+// WithDereferencedSymlinks makes the copy follow the symlinks, copying the content of the
+// files and directories they point to, instead of copying the symlinks themselves.
+func WithDereferencedSymlinks() CopyOption {
+	return func(o *copyOptions) {
+		o.dereferenceSymlinks = true
+	}
+}
+
+// WithTarTransform transforms each entry of the directory while it's copied into the container,
+// e.g. to apply a template substitution to the content of the files, or to rewrite their mode.
+func WithTarTransform(transform TarTransform) CopyOption {
+	return func(o *copyOptions) {
+		o.tarTransform = transform
+	}
+}
+
+// tarDir compress a directory using tar + gzip algorithms.
+// Symlinks are stored as symlink entries, unless dereferenceSymlinks is set,
+// in which case the content they point to is stored instead. The entries are
+// transformed with the tar transform of the options, if any.
+func tarDir(src string, fileMode int64, options copyOptions) (*bytes.Buffer, error) {
 	// always pass src as absolute path
 	abs, err := filepath.Abs(src)
 	if err != nil {
@@ -39,32 +76,98 @@ func tarDir(src string, fileMode int64) (*bytes.Buffer, error) {
 	}
 	src = abs
 
-	buffer := &bytes.Buffer{}
-
 	Logger.Printf(">> creating TAR file from directory: %s\n", src)
 
+	_, baseDir := filepath.Split(src)
+	// keep the path relative to the parent directory
+	index := strings.LastIndex(src, baseDir)
+
+	return tarDirAs(src, filepath.ToSlash(src[index:]), fileMode, options)
+}
+
+// tarDirAs compress a directory using tar + gzip algorithms, naming its entries
+// relative to the given name instead of the name of the directory.
+func tarDirAs(src string, name string, fileMode int64, options copyOptions) (*bytes.Buffer, error) {
+	buffer := &bytes.Buffer{}
+
 	// tar > gzip > buffer
 	zr := gzip.NewWriter(buffer)
 	tw := tar.NewWriter(zr)
 
-	_, baseDir := filepath.Split(src)
-	// keep the path relative to the parent directory
-	index := strings.LastIndex(src, baseDir)
+	err := tarDirEntries(tw, src, name, fileMode, options, map[string]bool{})
+	if err != nil {
+		return buffer, err
+	}
+
+	// produce tar
+	if err := tw.Close(); err != nil {
+		return buffer, fmt.Errorf("error closing tar file: %w", err)
+	}
+	// produce gzip
+	if err := zr.Close(); err != nil {
+		return buffer, fmt.Errorf("error closing gzip file: %w", err)
+	}
+
+	return buffer, nil
+}
+
+// tarDirEntries writes the entries of the src directory into the tar writer, naming them
+// relative to the given name. The visited map holds the directories being walked, so
+// dereferenced symlinks pointing to any of their parents do not cause an endless loop.
+func tarDirEntries(tw *tar.Writer, src string, name string, fileMode int64, options copyOptions, visited map[string]bool) error {
+	resolved, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return fmt.Errorf("error evaluating path: %w", err)
+	}
+	visited[resolved] = true
+	defer delete(visited, resolved)
 
 	// walk through every file in the folder
-	err = filepath.Walk(src, func(file string, fi os.FileInfo, errFn error) error {
+	return filepath.Walk(src, func(file string, fi os.FileInfo, errFn error) error {
 		if errFn != nil {
 			return fmt.Errorf("error traversing the file system: %w", errFn)
 		}
 
-		// if a symlink, skip file
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return fmt.Errorf("error getting relative path: %w", err)
+		}
+		entryName := path.Join(name, filepath.ToSlash(rel))
+
+		link := ""
 		if fi.Mode().Type() == os.ModeSymlink {
-			Logger.Printf(">> skipping symlink: %s\n", file)
-			return nil
+			if !options.dereferenceSymlinks {
+				link, err = os.Readlink(file)
+				if err != nil {
+					return fmt.Errorf("error reading symlink: %w", err)
+				}
+				// the target is resolved inside the container, so it must use forward slashes
+				link = filepath.ToSlash(link)
+			} else {
+				target, err := filepath.EvalSymlinks(file)
+				if err != nil {
+					return fmt.Errorf("error evaluating symlink: %w", err)
+				}
+
+				fi, err = os.Stat(target)
+				if err != nil {
+					return fmt.Errorf("error getting symlink target info: %w", err)
+				}
+
+				if fi.IsDir() {
+					if visited[target] {
+						return fmt.Errorf("symlink %s points to one of its parent directories", file)
+					}
+
+					return tarDirEntries(tw, target, entryName, fileMode, options, visited)
+				}
+
+				file = target
+			}
 		}
 
 		// generate tar header
-		header, err := tar.FileInfoHeader(fi, file)
+		header, err := tar.FileInfoHeader(fi, link)
 		if err != nil {
 			return fmt.Errorf("error getting file info header: %w", err)
 		}
@@ -72,16 +175,20 @@ func tarDir(src string, fileMode int64) (*bytes.Buffer, error) {
 		// see https://pkg.go.dev/archive/tar#FileInfoHeader:
 		// Since fs.FileInfo's Name method only returns the base name of the file it describes,
 		// it may be necessary to modify Header.Name to provide the full path name of the file.
-		header.Name = filepath.ToSlash(file[index:])
+		header.Name = entryName
 		header.Mode = fileMode
 
+		if options.tarTransform != nil {
+			return writeTransformedEntry(tw, header, file, fi.Mode().IsRegular(), options.tarTransform)
+		}
+
 		// write header
 		if err := tw.WriteHeader(header); err != nil {
 			return fmt.Errorf("error writing header: %w", err)
 		}
 
-		// if not a dir, write file content
-		if !fi.IsDir() {
+		// if a regular file, write file content
+		if fi.Mode().IsRegular() {
 			data, err := os.Open(file)
 			if err != nil {
 				return fmt.Errorf("error opening file: %w", err)
@@ -93,24 +200,44 @@ func tarDir(src string, fileMode int64) (*bytes.Buffer, error) {
 		}
 		return nil
 	})
+}
+
+// writeTransformedEntry writes the entry of the file into the tar writer, once transformed.
+// The content of regular files is read into memory, to be passed to the transform.
+func writeTransformedEntry(tw *tar.Writer, header *tar.Header, file string, regular bool, transform TarTransform) error {
+	var content []byte
+	if regular {
+		var err error
+		content, err = os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+	}
+
+	content, err := transform(header, content)
 	if err != nil {
-		return buffer, err
+		return fmt.Errorf("transform %s: %w", header.Name, err)
 	}
 
-	// produce tar
-	if err := tw.Close(); err != nil {
-		return buffer, fmt.Errorf("error closing tar file: %w", err)
+	if regular {
+		header.Size = int64(len(content))
 	}
-	// produce gzip
-	if err := zr.Close(); err != nil {
-		return buffer, fmt.Errorf("error closing gzip file: %w", err)
+
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing header: %w", err)
 	}
 
-	return buffer, nil
+	if regular {
+		if _, err := tw.Write(content); err != nil {
+			return fmt.Errorf("error compressing file: %w", err)
+		}
+	}
+
+	return nil
 }
 
 // tarFile compress a single file using tar + gzip algorithms
-func tarFile(basePath string, fileContent func(tw io.Writer) error, fileContentSize int64, fileMode int64) (*bytes.Buffer, error) {
+func tarFile(basePath string, fileContent func(tw io.Writer) error, fileContentSize int64, fileMode int64, hdrModifiers ...func(hdr *tar.Header)) (*bytes.Buffer, error) {
 	buffer := &bytes.Buffer{}
 
 	zr := gzip.NewWriter(buffer)
@@ -121,6 +248,9 @@ func tarFile(basePath string, fileContent func(tw io.Writer) error, fileContentS
 		Mode: fileMode,
 		Size: fileContentSize,
 	}
+	for _, modifier := range hdrModifiers {
+		modifier(hdr)
+	}
 	if err := tw.WriteHeader(hdr); err != nil {
 		return buffer, err
 	}
@@ -139,3 +269,238 @@ func tarFile(basePath string, fileContent func(tw io.Writer) error, fileContentS
 
 	return buffer, nil
 }
+
+// archiveToTar converts the archive at the host path, a .tar, .tar.gz, .tgz or .zip file detected by
+// its extension, into an uncompressed tar stream. The whole archive is read, so a corrupted one returns
+// an error, as do the entries which would be extracted outside of the destination directory, e.g.
+// "../etc/passwd", including the links pointing outside of it.
+func archiveToTar(archivePath string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+
+	name := strings.ToLower(archivePath)
+	var err error
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		err = copyTarArchive(archivePath, true, tw)
+	case strings.HasSuffix(name, ".tar"):
+		err = copyTarArchive(archivePath, false, tw)
+	case strings.HasSuffix(name, ".zip"):
+		err = copyZipArchive(archivePath, tw)
+	default:
+		return fmt.Errorf("unsupported archive %s, expected a .tar, .tar.gz, .tgz or .zip file", archivePath)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("error closing tar file: %w", err)
+	}
+
+	return nil
+}
+
+// copyTarArchive copies the entries of the tar archive, gzip'ed if compressed, into the tar writer.
+func copyTarArchive(archivePath string, compressed bool, tw *tar.Writer) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if compressed {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return fmt.Errorf("error reading gzip file: %w", err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+
+	v := newArchiveValidator()
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading tar file: %w", err)
+		}
+
+		// the global headers, e.g. the ones written by "git archive", hold no file
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+
+		if err := v.validate(header); err != nil {
+			return err
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("error writing header: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeReg {
+			if _, err := io.Copy(tw, tr); err != nil {
+				return fmt.Errorf("error reading %s: %w", header.Name, err)
+			}
+		}
+	}
+}
+
+// copyZipArchive copies the entries of the zip archive into the tar writer.
+func copyZipArchive(archivePath string, tw *tar.Writer) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("error reading zip file: %w", err)
+	}
+	defer zr.Close()
+
+	v := newArchiveValidator()
+	for _, f := range zr.File {
+		if err := copyZipEntry(f, v, tw); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// copyZipEntry copies the entry of a zip archive into the tar writer, once validated. The content
+// of the symlinks is their target, and the checksum of the content is verified once it's read.
+func copyZipEntry(f *zip.File, v *archiveValidator, tw *tar.Writer) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	fi := f.FileInfo()
+
+	link := ""
+	if fi.Mode().Type() == os.ModeSymlink {
+		target, err := io.ReadAll(rc)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", f.Name, err)
+		}
+		link = string(target)
+	}
+
+	header, err := tar.FileInfoHeader(fi, link)
+	if err != nil {
+		return fmt.Errorf("error getting file info header: %w", err)
+	}
+	header.Name = f.Name
+
+	if err := v.validate(header); err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("error writing header: %w", err)
+	}
+
+	if header.Typeflag == tar.TypeReg {
+		if _, err := io.Copy(tw, rc); err != nil {
+			return fmt.Errorf("error reading %s: %w", f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// archiveValidator validates the entries of an archive, keeping track of its symlinks: checking
+// each entry on its own is not enough, as an entry whose path goes through a symlink of a previous
+// entry, e.g. "a/b/x" after "a -> ." and "a/b -> ..", would be extracted outside of the destination
+// directory.
+type archiveValidator struct {
+	symlinks map[string]bool // the cleaned paths of the symlinks of the archive
+}
+
+// newArchiveValidator returns a validator for the entries of an archive.
+func newArchiveValidator() *archiveValidator {
+	return &archiveValidator{symlinks: map[string]bool{}}
+}
+
+// validate returns an error if the entry is not valid on its own, see validateArchiveEntry, or if its
+// path, or the target of a link, goes through a symlink of a previous entry.
+func (v *archiveValidator) validate(header *tar.Header) error {
+	if err := validateArchiveEntry(header); err != nil {
+		return err
+	}
+
+	if link, ok := v.throughSymlink(header.Name); ok {
+		return fmt.Errorf("entry %s is under the symlink %s", header.Name, link)
+	}
+
+	switch header.Typeflag {
+	case tar.TypeLink:
+		if link, ok := v.throughSymlink(header.Linkname); ok {
+			return fmt.Errorf("link %s points through the symlink %s: %s", header.Name, link, header.Linkname)
+		}
+	case tar.TypeSymlink:
+		if link, ok := v.throughSymlink(path.Dir(header.Name) + "/" + header.Linkname); ok {
+			return fmt.Errorf("symlink %s points through the symlink %s: %s", header.Name, link, header.Linkname)
+		}
+		v.symlinks[path.Clean(header.Name)] = true
+	}
+
+	return nil
+}
+
+// throughSymlink returns the symlink that the slash-separated path goes through, if any, resolving
+// its elements one at a time, so a symlink followed by ".." is found too. The last element is not
+// resolved, as it's the entry itself, or the target of a link.
+func (v *archiveValidator) throughSymlink(p string) (string, bool) {
+	elems := strings.Split(p, "/")
+
+	current := ""
+	for _, elem := range elems[:len(elems)-1] {
+		current = path.Join(current, elem)
+		if v.symlinks[current] {
+			return current, true
+		}
+	}
+
+	return "", false
+}
+
+// validateArchiveEntry returns an error if the entry of an archive is not a file, a directory
+// or a link, or if it would be extracted outside of the destination directory.
+func validateArchiveEntry(header *tar.Header) error {
+	if escapesDir(header.Name) {
+		return fmt.Errorf("entry %s is outside of the destination directory", header.Name)
+	}
+
+	switch header.Typeflag {
+	case tar.TypeReg, tar.TypeDir:
+		return nil
+	case tar.TypeLink:
+		// the target of hard links is relative to the root of the archive
+		if escapesDir(header.Linkname) {
+			return fmt.Errorf("link %s points outside of the destination directory: %s", header.Name, header.Linkname)
+		}
+		return nil
+	case tar.TypeSymlink:
+		// the target of symlinks is relative to the directory of the link
+		if path.IsAbs(header.Linkname) || escapesDir(path.Join(path.Dir(header.Name), header.Linkname)) {
+			return fmt.Errorf("symlink %s points outside of the destination directory: %s", header.Name, header.Linkname)
+		}
+		return nil
+	default:
+		return fmt.Errorf("entry %s has an unsupported type %q", header.Name, header.Typeflag)
+	}
+}
+
+// escapesDir returns true if the slash-separated path is absolute, or if it points
+// to a parent of the directory it's relative to.
+func escapesDir(p string) bool {
+	if path.IsAbs(p) {
+		return true
+	}
+
+	clean := path.Clean(p)
+	return clean == ".." || strings.HasPrefix(clean, "../")
+}
@@ -4,12 +4,15 @@ package testcontainers
 
 import (
 	"archive/tar"
+	"archive/zip"
 	"bytes"
 	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"path"
 	"path/filepath"
 	"testing"
 
@@ -78,7 +81,7 @@ func Test_TarDir(t *testing.T) {
 				src = absSrc
 			}
 
-			buff, err := tarDir(src, 0o755)
+			buff, err := tarDir(src, 0o755, copyOptions{})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -113,6 +116,73 @@ func Test_TarDir(t *testing.T) {
 	}
 }
 
+func Test_TarDir_Symlinks(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "symlinks")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "dir"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "dir", "target.txt"), []byte("hello"), 0o644))
+	require.NoError(t, os.Symlink(filepath.Join("dir", "target.txt"), filepath.Join(src, "link.txt")))
+	require.NoError(t, os.Symlink("dir", filepath.Join(src, "linkdir")))
+
+	readEntries := func(t *testing.T, dereference bool) map[string]*tar.Header {
+		t.Helper()
+
+		buff, err := tarDir(src, 0o755, copyOptions{dereferenceSymlinks: dereference})
+		require.NoError(t, err)
+
+		gzr, err := gzip.NewReader(buff)
+		require.NoError(t, err)
+		defer gzr.Close()
+
+		entries := map[string]*tar.Header{}
+		tr := tar.NewReader(gzr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			entries[hdr.Name] = hdr
+		}
+
+		return entries
+	}
+
+	t.Run("preserve", func(t *testing.T) {
+		entries := readEntries(t, false)
+
+		require.Contains(t, entries, "symlinks/link.txt")
+		assert.Equal(t, byte(tar.TypeSymlink), entries["symlinks/link.txt"].Typeflag)
+		assert.Equal(t, path.Join("dir", "target.txt"), entries["symlinks/link.txt"].Linkname)
+
+		require.Contains(t, entries, "symlinks/linkdir")
+		assert.Equal(t, byte(tar.TypeSymlink), entries["symlinks/linkdir"].Typeflag)
+		assert.Equal(t, "dir", entries["symlinks/linkdir"].Linkname)
+	})
+
+	t.Run("dereference", func(t *testing.T) {
+		entries := readEntries(t, true)
+
+		require.Contains(t, entries, "symlinks/link.txt")
+		assert.Equal(t, byte(tar.TypeReg), entries["symlinks/link.txt"].Typeflag)
+		assert.Equal(t, int64(len("hello")), entries["symlinks/link.txt"].Size)
+
+		require.Contains(t, entries, "symlinks/linkdir/target.txt")
+		assert.Equal(t, byte(tar.TypeReg), entries["symlinks/linkdir/target.txt"].Typeflag)
+	})
+
+	t.Run("dereference-loop", func(t *testing.T) {
+		loop := filepath.Join(src, "dir", "loop")
+		require.NoError(t, os.Symlink("..", loop))
+		t.Cleanup(func() {
+			require.NoError(t, os.Remove(loop))
+		})
+
+		_, err := tarDir(src, 0o755, copyOptions{dereferenceSymlinks: true})
+		require.Error(t, err)
+	})
+}
+
 func Test_TarFile(t *testing.T) {
 	b, err := os.ReadFile(filepath.Join(".", "testdata", "Dockerfile"))
 	if err != nil {
@@ -205,3 +275,211 @@ func untar(dst string, r io.Reader) error {
 		}
 	}
 }
+
+func Test_TarDir_Transform(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "templates")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "conf"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "conf", "app.conf"), []byte("port={{PORT}}"), 0o644))
+
+	t.Run("transform", func(t *testing.T) {
+		transform := func(hdr *tar.Header, content []byte) ([]byte, error) {
+			if hdr.Typeflag != tar.TypeReg {
+				require.Nil(t, content)
+				return nil, nil
+			}
+
+			hdr.Mode = 0o600
+			return bytes.ReplaceAll(content, []byte("{{PORT}}"), []byte("8080")), nil
+		}
+
+		buff, err := tarDir(src, 0o755, copyOptions{tarTransform: transform})
+		require.NoError(t, err)
+
+		gzr, err := gzip.NewReader(buff)
+		require.NoError(t, err)
+		defer gzr.Close()
+
+		tr := tar.NewReader(gzr)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+
+			if hdr.Name != "templates/conf/app.conf" {
+				assert.Equal(t, int64(0o755), hdr.Mode)
+				continue
+			}
+
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			assert.Equal(t, "port=8080", string(content))
+			assert.Equal(t, int64(len("port=8080")), hdr.Size)
+			assert.Equal(t, int64(0o600), hdr.Mode)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		transform := func(*tar.Header, []byte) ([]byte, error) {
+			return nil, errors.New("template error")
+		}
+
+		_, err := tarDir(src, 0o755, copyOptions{tarTransform: transform})
+		require.ErrorContains(t, err, "template error")
+	})
+}
+
+func Test_ArchiveToTar(t *testing.T) {
+	// tarEntries returns the content of the entries of the tar stream by name, empty for the directories
+	tarEntries := func(t *testing.T, r io.Reader) map[string]string {
+		t.Helper()
+
+		entries := map[string]string{}
+		tr := tar.NewReader(r)
+		for {
+			header, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				return entries
+			}
+			require.NoError(t, err)
+
+			content, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			entries[header.Name] = string(content)
+		}
+	}
+
+	// writeTar writes a tar archive with the given headers, and the content of the regular files
+	writeTar := func(t *testing.T, name string, headers ...*tar.Header) string {
+		t.Helper()
+
+		buf := &bytes.Buffer{}
+		tw := tar.NewWriter(buf)
+		for _, header := range headers {
+			require.NoError(t, tw.WriteHeader(header))
+			if header.Typeflag == tar.TypeReg {
+				_, err := tw.Write(bytes.Repeat([]byte("a"), int(header.Size)))
+				require.NoError(t, err)
+			}
+		}
+		require.NoError(t, tw.Close())
+
+		archivePath := filepath.Join(t.TempDir(), name)
+		require.NoError(t, os.WriteFile(archivePath, buf.Bytes(), 0o644))
+		return archivePath
+	}
+
+	t.Run("tar.gz", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		err := archiveToTar(filepath.Join("testdata", "archives", "dataset.tar.gz"), buf)
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]string{
+			"dataset/":                  "",
+			"dataset/users.csv":         "id,name\n1,alice\n2,bob\n",
+			"dataset/nested/":           "",
+			"dataset/nested/orders.csv": "id,user_id\n1,1\n",
+		}, tarEntries(t, buf))
+	})
+
+	t.Run("tar", func(t *testing.T) {
+		archivePath := writeTar(t, "data.tar",
+			&tar.Header{Name: "data.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3},
+			&tar.Header{Name: "link.txt", Typeflag: tar.TypeSymlink, Linkname: "data.txt"},
+		)
+
+		buf := &bytes.Buffer{}
+		err := archiveToTar(archivePath, buf)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"data.txt": "aaa", "link.txt": ""}, tarEntries(t, buf))
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		zw := zip.NewWriter(buf)
+		_, err := zw.Create("data/")
+		require.NoError(t, err)
+		w, err := zw.Create("data/hello.txt")
+		require.NoError(t, err)
+		_, err = w.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+
+		archivePath := filepath.Join(t.TempDir(), "data.zip")
+		require.NoError(t, os.WriteFile(archivePath, buf.Bytes(), 0o644))
+
+		out := &bytes.Buffer{}
+		err = archiveToTar(archivePath, out)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"data/": "", "data/hello.txt": "hello"}, tarEntries(t, out))
+	})
+
+	t.Run("path-traversal", func(t *testing.T) {
+		archivePath := writeTar(t, "evil.tar", &tar.Header{Name: "data/../../etc/passwd", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3})
+
+		err := archiveToTar(archivePath, io.Discard)
+		require.EqualError(t, err, "entry data/../../etc/passwd is outside of the destination directory")
+	})
+
+	t.Run("absolute-path", func(t *testing.T) {
+		archivePath := writeTar(t, "evil.tar", &tar.Header{Name: "/etc/passwd", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3})
+
+		err := archiveToTar(archivePath, io.Discard)
+		require.EqualError(t, err, "entry /etc/passwd is outside of the destination directory")
+	})
+
+	t.Run("symlink-outside", func(t *testing.T) {
+		archivePath := writeTar(t, "evil.tar", &tar.Header{Name: "data/etc", Typeflag: tar.TypeSymlink, Linkname: "../../etc"})
+
+		err := archiveToTar(archivePath, io.Discard)
+		require.EqualError(t, err, "symlink data/etc points outside of the destination directory: ../../etc")
+	})
+
+	t.Run("entry-under-symlink", func(t *testing.T) {
+		archivePath := writeTar(t, "evil.tar",
+			&tar.Header{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+			&tar.Header{Name: "a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+			&tar.Header{Name: "a/b/x", Typeflag: tar.TypeReg, Mode: 0o644, Size: 3},
+		)
+
+		err := archiveToTar(archivePath, io.Discard)
+		require.EqualError(t, err, "entry a/b is under the symlink a")
+	})
+
+	t.Run("symlink-through-symlink", func(t *testing.T) {
+		archivePath := writeTar(t, "evil.tar",
+			&tar.Header{Name: "data/a", Typeflag: tar.TypeSymlink, Linkname: "."},
+			&tar.Header{Name: "data/b", Typeflag: tar.TypeSymlink, Linkname: "a/../.."},
+		)
+
+		err := archiveToTar(archivePath, io.Discard)
+		require.EqualError(t, err, "symlink data/b points through the symlink data/a: a/../..")
+	})
+
+	t.Run("hard-link-through-symlink", func(t *testing.T) {
+		archivePath := writeTar(t, "evil.tar",
+			&tar.Header{Name: "a", Typeflag: tar.TypeSymlink, Linkname: "."},
+			&tar.Header{Name: "passwd", Typeflag: tar.TypeLink, Linkname: "a/passwd"},
+		)
+
+		err := archiveToTar(archivePath, io.Discard)
+		require.EqualError(t, err, "link passwd points through the symlink a: a/passwd")
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		b, err := os.ReadFile(filepath.Join("testdata", "archives", "dataset.tar.gz"))
+		require.NoError(t, err)
+
+		archivePath := filepath.Join(t.TempDir(), "truncated.tar.gz")
+		require.NoError(t, os.WriteFile(archivePath, b[:len(b)/2], 0o644))
+
+		err = archiveToTar(archivePath, io.Discard)
+		require.Error(t, err)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		err := archiveToTar(filepath.Join("testdata", "hello.sh"), io.Discard)
+		require.ErrorContains(t, err, "unsupported archive")
+	})
+}
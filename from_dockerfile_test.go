@@ -2,17 +2,23 @@ package testcontainers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/errdefs"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
 )
 
 func TestBuildImageFromDockerfile(t *testing.T) {
@@ -216,3 +222,415 @@ func TestBuildImageFromDockerfile_TargetDoesNotExist(t *testing.T) {
 	})
 	require.Error(t, err)
 }
+
+func TestBuildImageFromDockerfile_AdditionalBuildContexts(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			// fromDockerfileWithAdditionalBuildContexts {
+			FromDockerfile: FromDockerfile{
+				Context: "testdata/buildcontexts/main",
+				AdditionalBuildContexts: map[string]string{
+					"extra": "testdata/buildcontexts/extra",
+				},
+			},
+			// }
+			WaitingFor: wait.ForExit(),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, c.Terminate(ctx))
+	})
+
+	r, err := c.Logs(ctx)
+	require.NoError(t, err)
+
+	logs, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello from the additional build context\n\n", string(logs))
+}
+
+func TestBuildImageFromDockerfile_AdditionalBuildContextsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     ContainerRequest
+		wantErr bool
+	}{
+		{
+			name: "local-directory",
+			req: ContainerRequest{
+				FromDockerfile: FromDockerfile{
+					Context:                 "testdata/buildcontexts/main",
+					AdditionalBuildContexts: map[string]string{"extra": "testdata/buildcontexts/extra"},
+				},
+			},
+		},
+		{
+			name: "image-reference",
+			req: ContainerRequest{
+				FromDockerfile: FromDockerfile{
+					Context:                 "testdata/buildcontexts/main",
+					AdditionalBuildContexts: map[string]string{"extra": "docker-image://docker.io/alpine"},
+				},
+			},
+		},
+		{
+			name: "path-does-not-exist",
+			req: ContainerRequest{
+				FromDockerfile: FromDockerfile{
+					Context:                 "testdata/buildcontexts/main",
+					AdditionalBuildContexts: map[string]string{"extra": "testdata/buildcontexts/does-not-exist"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "path-is-a-file",
+			req: ContainerRequest{
+				FromDockerfile: FromDockerfile{
+					Context:                 "testdata/buildcontexts/main",
+					AdditionalBuildContexts: map[string]string{"extra": "testdata/buildcontexts/extra/message.txt"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "context-archive",
+			req: ContainerRequest{
+				FromDockerfile: FromDockerfile{
+					ContextArchive:          strings.NewReader(""),
+					AdditionalBuildContexts: map[string]string{"extra": "testdata/buildcontexts/extra"},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildImageFromDockerfile_ExtraHosts(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			// fromDockerfileWithExtraHosts {
+			FromDockerfile: FromDockerfile{
+				Context:    "testdata/buildhosts",
+				ExtraHosts: []string{"mirror.testcontainers.internal:10.10.10.10"},
+			},
+			// }
+			WaitingFor: wait.ForExit(),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, c.Terminate(ctx))
+	})
+
+	r, err := c.Logs(ctx)
+	require.NoError(t, err)
+
+	logs, err := io.ReadAll(r)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(logs), "10.10.10.10")
+	assert.Contains(t, string(logs), "mirror.testcontainers.internal")
+}
+
+func TestBuildImageFromDockerfile_ExtraHostsValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		extraHosts []string
+		wantErr    bool
+	}{
+		{name: "ipv4", extraHosts: []string{"mirror.internal:10.10.10.10"}},
+		{name: "ipv6", extraHosts: []string{"mirror.internal:::1"}},
+		{name: "ipv6-brackets", extraHosts: []string{"mirror.internal:[::1]"}},
+		{name: "host-gateway", extraHosts: []string{"host.docker.internal:host-gateway"}},
+		{name: "missing-ip", extraHosts: []string{"mirror.internal"}, wantErr: true},
+		{name: "missing-host", extraHosts: []string{":10.10.10.10"}, wantErr: true},
+		{name: "invalid-ip", extraHosts: []string{"mirror.internal:10.10.10"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ContainerRequest{
+				FromDockerfile: FromDockerfile{
+					Context:    "testdata/buildhosts",
+					ExtraHosts: tt.extraHosts,
+				},
+			}
+
+			err := req.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildImageFromDockerfile_BuildSecretsValidation(t *testing.T) {
+	secretPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(secretPath, []byte("s3cr3t"), 0o600))
+
+	starter := func(context.Context, map[string]string, BuildSessionDialer) (BuildSession, error) {
+		return nil, errors.New("not started")
+	}
+
+	tests := []struct {
+		name    string
+		secrets map[string]string
+		starter BuildSessionStarter
+		wantErr string
+	}{
+		{name: "file", secrets: map[string]string{"token": secretPath}, starter: starter},
+		{name: "empty-id", secrets: map[string]string{"": secretPath}, starter: starter, wantErr: "build secret id must not be empty"},
+		{name: "missing-file", secrets: map[string]string{"token": secretPath + ".missing"}, starter: starter, wantErr: "build secret token:"},
+		{name: "directory", secrets: map[string]string{"token": t.TempDir()}, starter: starter, wantErr: "is a directory"},
+		{name: "no-starter", secrets: map[string]string{"token": secretPath}, wantErr: "build secrets require a build session starter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := ContainerRequest{
+				FromDockerfile: FromDockerfile{
+					Context:             "testdata",
+					BuildSecrets:        tt.secrets,
+					BuildSessionStarter: tt.starter,
+				},
+			}
+
+			err := req.Validate()
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildOptions_BuildSecrets(t *testing.T) {
+	newRequest := func(modifier func(*types.ImageBuildOptions)) *ContainerRequest {
+		return &ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				Context:              "testdata",
+				BuildSecrets:         map[string]string{"token": "token"},
+				BuildOptionsModifier: modifier,
+			},
+		}
+	}
+
+	t.Run("buildkit-enabled", func(t *testing.T) {
+		opts, err := newRequest(nil).BuildOptions()
+		require.NoError(t, err)
+		require.Equal(t, types.BuilderBuildKit, opts.Version)
+	})
+
+	t.Run("classic-builder", func(t *testing.T) {
+		_, err := newRequest(func(opts *types.ImageBuildOptions) {
+			opts.Version = types.BuilderV1
+		}).BuildOptions()
+		require.EqualError(t, err, `build secrets require BuildKit, but the build options use the builder version "1"`)
+	})
+
+	t.Run("no-secrets", func(t *testing.T) {
+		req := newRequest(nil)
+		req.FromDockerfile.BuildSecrets = nil
+
+		opts, err := req.BuildOptions()
+		require.NoError(t, err)
+		require.Empty(t, opts.Version)
+	})
+
+	t.Run("progress-consumer", func(t *testing.T) {
+		req := newRequest(nil)
+		req.FromDockerfile.BuildProgressConsumer = func(BuildEvent) {}
+
+		_, err := req.BuildOptions()
+		require.EqualError(t, err, "the build progress consumer is only supported by the classic builder, but the build uses BuildKit")
+	})
+
+	t.Run("progress-consumer-buildkit-modifier", func(t *testing.T) {
+		req := newRequest(func(opts *types.ImageBuildOptions) {
+			opts.Version = types.BuilderBuildKit
+		})
+		req.FromDockerfile.BuildSecrets = nil
+		req.FromDockerfile.BuildProgressConsumer = func(BuildEvent) {}
+
+		_, err := req.BuildOptions()
+		require.ErrorContains(t, err, "the build uses BuildKit")
+	})
+}
+
+func TestBuildImageFromDockerfile_BuildProgressConsumer(t *testing.T) {
+	provider, err := NewDockerProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	ctx := context.Background()
+
+	var events []BuildEvent
+	tag, err := provider.BuildImage(ctx, &ContainerRequest{
+		// fromDockerfileWithBuildProgressConsumer {
+		FromDockerfile: FromDockerfile{
+			Context:    "testdata",
+			Dockerfile: "buildprogress.Dockerfile",
+			BuildProgressConsumer: func(event BuildEvent) {
+				events = append(events, event)
+			},
+		},
+		// }
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := provider.Client().ImageRemove(ctx, tag, image.RemoveOptions{
+			Force:         true,
+			PruneChildren: true,
+		})
+		require.NoError(t, err)
+	})
+
+	require.Len(t, events, 3)
+	for i, event := range events {
+		assert.Equal(t, i+1, event.Step)
+		assert.Equal(t, 3, event.TotalSteps)
+	}
+	assert.Equal(t, "FROM docker.io/alpine", events[0].Command)
+	assert.Equal(t, `RUN echo "building the second step"`, events[1].Command)
+	assert.Equal(t, `CMD ["echo", "done"]`, events[2].Command)
+	if !events[1].Cached {
+		assert.Contains(t, events[1].Lines, "building the second step")
+	}
+}
+
+func TestDecodeBuildProgress(t *testing.T) {
+	t.Run("steps", func(t *testing.T) {
+		output := `{"stream":"Step 1/3 : FROM docker.io/alpine"}
+{"stream":"\n"}
+{"stream":" ---\u003e 1d34ffeaf190\n"}
+{"stream":"Step 2/3 : RUN echo hello\n"}
+{"stream":" ---\u003e Using cache\n"}
+{"stream":" ---\u003e 5b2f4bd1a3e6\n"}
+{"stream":"Step 3/3 : RUN echo world\n"}
+{"stream":" ---\u003e Running in 0e5dd51c1bd3\n"}
+{"stream":"world\n"}
+{"stream":" ---\u003e 8c1b9ab1c7a2\n"}
+{"aux":{"ID":"sha256:8c1b9ab1c7a2"}}
+{"stream":"Successfully built 8c1b9ab1c7a2\n"}
+{"stream":"Successfully tagged test:latest\n"}
+`
+
+		var events []BuildEvent
+		err := decodeBuildProgress(strings.NewReader(output), func(event BuildEvent) {
+			events = append(events, event)
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []BuildEvent{
+			{Step: 1, TotalSteps: 3, Command: "FROM docker.io/alpine", Lines: []string{" ---> 1d34ffeaf190"}},
+			{Step: 2, TotalSteps: 3, Command: "RUN echo hello", Cached: true, Lines: []string{" ---> Using cache", " ---> 5b2f4bd1a3e6"}},
+			{Step: 3, TotalSteps: 3, Command: "RUN echo world", Lines: []string{" ---> Running in 0e5dd51c1bd3", "world", " ---> 8c1b9ab1c7a2"}},
+		}, events)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		output := `{"stream":"Step 1/2 : FROM docker.io/alpine\n"}
+{"stream":"Step 2/2 : RUN exit 1\n"}
+{"stream":" ---\u003e Running in 0e5dd51c1bd3\n"}
+{"errorDetail":{"code":1,"message":"The command '/bin/sh -c exit 1' returned a non-zero code: 1"},"error":"The command '/bin/sh -c exit 1' returned a non-zero code: 1"}
+`
+
+		var events []BuildEvent
+		err := decodeBuildProgress(strings.NewReader(output), func(event BuildEvent) {
+			events = append(events, event)
+		})
+		require.EqualError(t, err, "The command '/bin/sh -c exit 1' returned a non-zero code: 1")
+
+		require.Len(t, events, 2)
+		require.Equal(t, "RUN exit 1", events[1].Command)
+		require.Equal(t, []string{" ---> Running in 0e5dd51c1bd3"}, events[1].Lines)
+	})
+}
+
+func TestInsertBeforeFirstStage(t *testing.T) {
+	dockerfile := "# syntax=docker/dockerfile:1\nARG VERSION=3\nFROM alpine:${VERSION}\nCOPY --from=extra a /a\n"
+
+	got := insertBeforeFirstStage([]byte(dockerfile), "FROM scratch AS extra\nCOPY .testcontainers-build-contexts/extra/ /\n")
+
+	expected := "# syntax=docker/dockerfile:1\nARG VERSION=3\n" +
+		"FROM scratch AS extra\nCOPY .testcontainers-build-contexts/extra/ /\n" +
+		"FROM alpine:${VERSION}\nCOPY --from=extra a /a\n"
+	assert.Equal(t, expected, string(got))
+}
+
+func TestBuildImageFromDockerfile_TagAndPush(t *testing.T) {
+	ctx := context.Background()
+
+	registryC, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			Image:        "registry:2.8.3",
+			ExposedPorts: []string{"5000/tcp"},
+			WaitingFor:   wait.ForHTTP("/v2/").WithPort("5000/tcp"),
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, registryC)
+	require.NoError(t, err)
+
+	// the Docker daemon considers the registries on localhost as insecure, so it pushes to them over HTTP
+	port, err := registryC.MappedPort(ctx, "5000/tcp")
+	require.NoError(t, err)
+	ref := fmt.Sprintf("localhost:%s/testcontainers/echo:pushed", port.Port())
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			FromDockerfile: FromDockerfile{
+				Context:    "testdata",
+				Dockerfile: "echo.Dockerfile",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	dockerC := ctr.(*DockerContainer)
+	require.NoError(t, dockerC.TagAndPush(ctx, ref, nil))
+
+	// the tag is removed from the Docker host together with the built image
+	require.NoError(t, ctr.Terminate(ctx))
+
+	provider, err := NewDockerProvider()
+	require.NoError(t, err)
+	defer provider.Close()
+
+	cli := provider.Client()
+	_, _, err = cli.ImageInspectWithRaw(ctx, ref)
+	require.True(t, errdefs.IsNotFound(err), "expected the tag to be removed, got %v", err)
+
+	// the image is pulled back from the registry
+	require.NoError(t, provider.PullImage(ctx, ref))
+	t.Cleanup(func() {
+		_, err := cli.ImageRemove(ctx, ref, image.RemoveOptions{Force: true, PruneChildren: true})
+		require.NoError(t, err)
+	})
+
+	_, _, err = cli.ImageInspectWithRaw(ctx, ref)
+	require.NoError(t, err)
+}
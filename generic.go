@@ -21,6 +21,10 @@ type GenericContainerRequest struct {
 	ProviderType     ProviderType // which provider to use, Docker if empty
 	Logger           Logging      // provide a container specific Logging - use default global logger if empty
 	Reuse            bool         // reuse an existing container if it exists or create a new one. a container name mustn't be empty
+
+	// appliedOptions holds the names of the exclusive options applied to the request,
+	// with the names of the options each of them conflicts with.
+	appliedOptions map[string][]string
 }
 
 // Deprecated: will be removed in the future.
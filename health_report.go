@@ -0,0 +1,79 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+)
+
+// HealthReport is a summary of the state of a container, to debug a test failure with a single call.
+type HealthReport struct {
+	State        *types.ContainerState      // the state of the container, e.g. "running" or "exited", with its exit code
+	RestartCount int                        // the number of times the container has been restarted by the Docker engine
+	HealthStatus string                     // the healthcheck status: "starting", "healthy" or "unhealthy", or "none" without healthcheck
+	HealthLogs   []*types.HealthcheckResult // the last results of the healthcheck, oldest first, as kept by the Docker engine
+	Logs         []LogLine                  // the last lines of the logs of the container, in the order they were written
+}
+
+// String returns the report as text, e.g. to print it when a test fails.
+func (r HealthReport) String() string {
+	var sb strings.Builder
+
+	if r.State != nil {
+		fmt.Fprintf(&sb, "state: %s (exit code: %d, OOM killed: %t, restarts: %d)", r.State.Status, r.State.ExitCode, r.State.OOMKilled, r.RestartCount)
+		if r.State.Error != "" {
+			fmt.Fprintf(&sb, ", error: %s", r.State.Error)
+		}
+		sb.WriteString("\n")
+	}
+
+	fmt.Fprintf(&sb, "health: %s\n", r.HealthStatus)
+	for _, result := range r.HealthLogs {
+		fmt.Fprintf(&sb, "  %s exit code %d: %s\n", result.Start.Format("15:04:05.000"), result.ExitCode, strings.TrimSpace(result.Output))
+	}
+
+	sb.WriteString("logs:\n")
+	for _, line := range r.Logs {
+		fmt.Fprintf(&sb, "  %s\n", line)
+	}
+
+	return sb.String()
+}
+
+// HealthReport returns the state of the container, its healthcheck status with the last results
+// of the healthcheck, and the last logLines lines of its logs, e.g. to report them in a test failure
+// handler. The logs are not read if logLines is zero or negative. Only the requested lines are read
+// from the Docker engine, so they are numbered from the first of them, and they are kept up to the
+// same maximum size as the logs of a container failing to start.
+func (c *DockerContainer) HealthReport(ctx context.Context, logLines int) (HealthReport, error) {
+	inspect, err := c.inspectRawContainer(ctx)
+	if err != nil {
+		return HealthReport{}, fmt.Errorf("inspect container: %w", err)
+	}
+
+	report := HealthReport{
+		State:        inspect.State,
+		RestartCount: inspect.RestartCount,
+		HealthStatus: types.NoHealthcheck,
+	}
+
+	if inspect.State != nil && inspect.State.Health != nil {
+		report.HealthStatus = inspect.State.Health.Status
+		report.HealthLogs = inspect.State.Health.Log
+	}
+
+	if logLines <= 0 {
+		return report, nil
+	}
+
+	lines, _, err := c.logLines(ctx, strconv.Itoa(logLines), c.provider.maxErrorLogBytes())
+	if err != nil {
+		return HealthReport{}, fmt.Errorf("read logs: %w", err)
+	}
+	report.Logs = lines
+
+	return report, nil
+}
@@ -0,0 +1,128 @@
+package testcontainers
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestHealthReport_String(t *testing.T) {
+	report := HealthReport{
+		State:        &types.ContainerState{Status: "running"},
+		RestartCount: 1,
+		HealthStatus: types.Unhealthy,
+		HealthLogs: []*types.HealthcheckResult{
+			{Start: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), ExitCode: 1, Output: "connection refused\n"},
+		},
+		Logs: []LogLine{{Stream: StderrLog, Number: 3, Text: "listening"}},
+	}
+
+	require.Equal(t, `state: running (exit code: 0, OOM killed: false, restarts: 1)
+health: unhealthy
+  10:00:00.000 exit code 1: connection refused
+logs:
+  STDERR 3: listening
+`, report.String())
+}
+
+// healthReportMockCli is a client returning the details of a running container with a TTY,
+// and its logs, recording the options of the logs requests.
+type healthReportMockCli struct {
+	client.APIClient
+
+	logs        string
+	logsOptions []container.LogsOptions
+}
+
+func (m *healthReportMockCli) ContainerInspect(_ context.Context, _ string) (types.ContainerJSON, error) {
+	return types.ContainerJSON{
+		ContainerJSONBase: &types.ContainerJSONBase{
+			State:      &types.ContainerState{Status: "running", Running: true},
+			HostConfig: &container.HostConfig{},
+		},
+		Config: &container.Config{Tty: true},
+	}, nil
+}
+
+func (m *healthReportMockCli) ContainerLogs(_ context.Context, _ string, options container.LogsOptions) (io.ReadCloser, error) {
+	m.logsOptions = append(m.logsOptions, options)
+	return io.NopCloser(strings.NewReader(m.logs)), nil
+}
+
+func (m *healthReportMockCli) Close() error {
+	return nil
+}
+
+func TestDockerContainer_HealthReport_Tail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("tail", func(t *testing.T) {
+		m := &healthReportMockCli{logs: "line 2\nline 3\n"}
+		c := &DockerContainer{ID: "0123456789abcdef", provider: &DockerProvider{client: m}, logger: Logger}
+
+		report, err := c.HealthReport(ctx, 2)
+		require.NoError(t, err)
+		require.Equal(t, types.NoHealthcheck, report.HealthStatus)
+		require.Len(t, m.logsOptions, 1)
+		require.Equal(t, "2", m.logsOptions[0].Tail)
+		require.Equal(t, []LogLine{
+			{Stream: StdoutLog, Number: 1, Text: "line 2"},
+			{Stream: StdoutLog, Number: 2, Text: "line 3"},
+		}, report.Logs)
+	})
+
+	t.Run("no-logs", func(t *testing.T) {
+		m := &healthReportMockCli{logs: "line 1\n"}
+		c := &DockerContainer{ID: "0123456789abcdef", provider: &DockerProvider{client: m}, logger: Logger}
+
+		report, err := c.HealthReport(ctx, 0)
+		require.NoError(t, err)
+		require.True(t, report.State.Running)
+		require.Empty(t, m.logsOptions)
+		require.Empty(t, report.Logs)
+	})
+}
+
+func TestDockerContainer_HealthReport(t *testing.T) {
+	ctx := context.Background()
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			Image: "docker.io/alpine",
+			Cmd:   []string{"sh", "-c", "for i in 1 2 3; do echo line $i; done; touch /tmp/ready; sleep 60"},
+			ConfigModifier: func(c *container.Config) {
+				c.Healthcheck = &container.HealthConfig{
+					Test:     []string{"CMD-SHELL", "test -f /tmp/ready && echo ok"},
+					Interval: 100 * time.Millisecond,
+				}
+			},
+			WaitingFor: wait.ForHealthCheck().WithStartupTimeout(30 * time.Second),
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.NoError(t, err)
+
+	report, err := ctr.(*DockerContainer).HealthReport(ctx, 20)
+	require.NoError(t, err)
+
+	require.True(t, report.State.Running)
+	require.Equal(t, types.Healthy, report.HealthStatus)
+	require.NotEmpty(t, report.HealthLogs)
+	require.Equal(t, "ok\n", report.HealthLogs[len(report.HealthLogs)-1].Output)
+	require.Equal(t, []LogLine{
+		{Stream: StdoutLog, Number: 1, Text: "line 1"},
+		{Stream: StdoutLog, Number: 2, Text: "line 2"},
+		{Stream: StdoutLog, Number: 3, Text: "line 3"},
+	}, report.Logs)
+	require.Contains(t, report.String(), "health: healthy")
+}
@@ -2,6 +2,7 @@ package testcontainers
 
 import (
 	"context"
+	"sync"
 )
 
 // ImageInfo represents a summary information of an image
@@ -16,3 +17,126 @@ type ImageProvider interface {
 	SaveImages(context.Context, string, ...string) error
 	PullImage(context.Context, string) error
 }
+
+// imagePuller coalesces and limits the image pulls of all the Docker providers in the process,
+// as each call to GenericContainer creates its own provider.
+type imagePuller struct {
+	mu       sync.Mutex
+	inflight map[string]*imagePull
+	// sem limits the number of concurrent pulls, nil meaning no limit
+	sem   chan struct{}
+	limit int
+}
+
+// imagePull represents a pull in progress, which other pulls of the same image wait for
+type imagePull struct {
+	done chan struct{}
+	err  error
+	// waiters is the number of callers waiting for the pull, which is cancelled once all of them gave up
+	waiters int
+	cancel  context.CancelFunc
+}
+
+var defaultImagePuller = newImagePuller()
+
+func newImagePuller() *imagePuller {
+	return &imagePuller{
+		inflight: map[string]*imagePull{},
+	}
+}
+
+// setMaxConcurrentPulls sets the maximum number of concurrent pulls, where zero or a negative
+// number removes the limit. Pulls already in progress are not affected, and setting the same
+// limit again keeps the slots taken by them.
+func (ip *imagePuller) setMaxConcurrentPulls(n int) {
+	ip.mu.Lock()
+	defer ip.mu.Unlock()
+
+	if n <= 0 {
+		n = 0
+	}
+	if n == ip.limit {
+		return
+	}
+
+	ip.limit = n
+	if n == 0 {
+		ip.sem = nil
+		return
+	}
+
+	ip.sem = make(chan struct{}, n)
+}
+
+// pull runs the pull function, waiting for a free slot if the number of concurrent pulls
+// is limited. If a pull with the same key is already in progress, it waits for it and
+// returns its result instead. The pull is shared by all its callers, so it does not run
+// with the context of any of them: it's only cancelled once all of them stopped waiting.
+func (ip *imagePuller) pull(ctx context.Context, key string, pullFn func(context.Context) error) error {
+	ip.mu.Lock()
+	p, ok := ip.inflight[key]
+	if !ok {
+		pullCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		p = &imagePull{done: make(chan struct{}), cancel: cancel}
+		ip.inflight[key] = p
+
+		go ip.run(pullCtx, key, p, pullFn)
+	}
+	p.waiters++
+	ip.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return p.err
+	case <-ctx.Done():
+		ip.mu.Lock()
+		p.waiters--
+		last := p.waiters == 0
+		if last {
+			// nobody waits for the pull anymore, so the next caller starts a new one
+			p.cancel()
+			if ip.inflight[key] == p {
+				delete(ip.inflight, key)
+			}
+		}
+		ip.mu.Unlock()
+
+		if last {
+			// do not leave the cancelled pull running in the background
+			<-p.done
+		}
+
+		return ctx.Err()
+	}
+}
+
+// run runs the shared pull, once there is a free slot, and publishes its result to the waiters.
+func (ip *imagePuller) run(ctx context.Context, key string, p *imagePull, pullFn func(context.Context) error) {
+	defer func() {
+		p.cancel()
+
+		ip.mu.Lock()
+		if ip.inflight[key] == p {
+			delete(ip.inflight, key)
+		}
+		ip.mu.Unlock()
+
+		close(p.done)
+	}()
+
+	ip.mu.Lock()
+	sem := ip.sem
+	ip.mu.Unlock()
+
+	if sem != nil {
+		select {
+		case sem <- struct{}{}:
+			defer func() { <-sem }()
+		case <-ctx.Done():
+			p.err = ctx.Err()
+			return
+		}
+	}
+
+	p.err = pullFn(ctx)
+}
@@ -85,6 +85,20 @@ func TestPrependHubRegistrySubstitutor(t *testing.T) {
 				t.Errorf("expected registry.hub.docker.com/foo:latest, got %s", img)
 			}
 		})
+
+		t.Run("image ID", func(t *testing.T) {
+			s := newPrependHubRegistry("my-registry")
+
+			id := "sha256:4bcff63911fcb4448bd4fdacec207030997caf25e9bea4045fa6c8c44de311d1"
+			img, err := s.Substitute(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if img != id {
+				t.Errorf("expected %s, got %s", id, img)
+			}
+		})
 	})
 }
 
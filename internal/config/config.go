@@ -28,10 +28,13 @@ type Config struct {
 	HubImageNamePrefix      string        `properties:"hub.image.name.prefix,default="`
 	RyukDisabled            bool          `properties:"ryuk.disabled,default=false"`
 	RyukPrivileged          bool          `properties:"ryuk.container.privileged,default=false"`
+	RyukImage               string        `properties:"ryuk.container.image,default="`
+	RyukLabels              string        `properties:"ryuk.container.labels,default="`
 	RyukReconnectionTimeout time.Duration `properties:"ryuk.reconnection.timeout,default=10s"`
 	RyukConnectionTimeout   time.Duration `properties:"ryuk.connection.timeout,default=1m"`
 	RyukVerbose             bool          `properties:"ryuk.verbose,default=false"`
 	TestcontainersHost      string        `properties:"tc.host,default="`
+	MaxErrorLogBytes        int           `properties:"error.logs.max.bytes,default=0"`
 }
 
 // }
@@ -82,6 +85,16 @@ func read() Config {
 			config.RyukPrivileged = ryukPrivilegedEnv == "true"
 		}
 
+		ryukImageEnv := os.Getenv("TESTCONTAINERS_RYUK_CONTAINER_IMAGE")
+		if ryukImageEnv != "" {
+			config.RyukImage = ryukImageEnv
+		}
+
+		ryukLabelsEnv := os.Getenv("TESTCONTAINERS_RYUK_CONTAINER_LABELS")
+		if ryukLabelsEnv != "" {
+			config.RyukLabels = ryukLabelsEnv
+		}
+
 		ryukVerboseEnv := os.Getenv("TESTCONTAINERS_RYUK_VERBOSE")
 		if parseBool(ryukVerboseEnv) {
 			config.RyukVerbose = ryukVerboseEnv == "true"
@@ -97,6 +110,11 @@ func read() Config {
 			config.RyukConnectionTimeout = timeout
 		}
 
+		maxErrorLogBytesEnv := os.Getenv("TESTCONTAINERS_ERROR_LOGS_MAX_BYTES")
+		if n, err := strconv.Atoi(maxErrorLogBytesEnv); err == nil {
+			config.MaxErrorLogBytes = n
+		}
+
 		return config
 	}
 
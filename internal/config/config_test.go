@@ -22,9 +22,12 @@ func resetTestEnv(t *testing.T) {
 	t.Setenv("TESTCONTAINERS_HUB_IMAGE_NAME_PREFIX", "")
 	t.Setenv("TESTCONTAINERS_RYUK_DISABLED", "")
 	t.Setenv("TESTCONTAINERS_RYUK_CONTAINER_PRIVILEGED", "")
+	t.Setenv("TESTCONTAINERS_RYUK_CONTAINER_IMAGE", "")
+	t.Setenv("TESTCONTAINERS_RYUK_CONTAINER_LABELS", "")
 	t.Setenv("TESTCONTAINERS_RYUK_VERBOSE", "")
 	t.Setenv("TESTCONTAINERS_RYUK_RECONNECTION_TIMEOUT", "")
 	t.Setenv("TESTCONTAINERS_RYUK_CONNECTION_TIMEOUT", "")
+	t.Setenv("TESTCONTAINERS_ERROR_LOGS_MAX_BYTES", "")
 }
 
 func TestReadConfig(t *testing.T) {
@@ -308,6 +311,33 @@ func TestReadTCConfig(t *testing.T) {
 					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
 				},
 			},
+			{
+				"With Ryuk container image and labels configured using properties",
+				`ryuk.container.image=registry.mycompany.com/ryuk:0.7.0
+	ryuk.container.labels=team=qa,cost-center=42`,
+				map[string]string{},
+				Config{
+					RyukImage:               "registry.mycompany.com/ryuk:0.7.0",
+					RyukLabels:              "team=qa,cost-center=42",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With Ryuk container image and labels configured using env vars and properties. Env var wins",
+				`ryuk.container.image=registry.mycompany.com/ryuk:0.7.0
+	ryuk.container.labels=team=qa`,
+				map[string]string{
+					"TESTCONTAINERS_RYUK_CONTAINER_IMAGE":  "mirror.mycompany.com/ryuk:0.7.0",
+					"TESTCONTAINERS_RYUK_CONTAINER_LABELS": "team=dev",
+				},
+				Config{
+					RyukImage:               "mirror.mycompany.com/ryuk:0.7.0",
+					RyukLabels:              "team=dev",
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
 			{
 				"With Ryuk disabled using an env var",
 				``,
@@ -515,6 +545,28 @@ func TestReadTCConfig(t *testing.T) {
 					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
 				},
 			},
+			{
+				"With max error log bytes set as properties",
+				`error.logs.max.bytes=4096`,
+				map[string]string{},
+				Config{
+					MaxErrorLogBytes:        4096,
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
+			{
+				"With max error log bytes set as env var and properties: Env var wins",
+				`error.logs.max.bytes=4096`,
+				map[string]string{
+					"TESTCONTAINERS_ERROR_LOGS_MAX_BYTES": "-1",
+				},
+				Config{
+					MaxErrorLogBytes:        -1,
+					RyukConnectionTimeout:   defaultRyukConnectionTimeout,
+					RyukReconnectionTimeout: defaultRyukReconnectionTimeout,
+				},
+			},
 		}
 		for _, tt := range tests {
 			t.Run(fmt.Sprintf(tt.name), func(t *testing.T) {
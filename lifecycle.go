@@ -5,10 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -48,6 +53,31 @@ type ContainerLifecycleHooks struct {
 	PostTerminates []ContainerHook
 }
 
+// LifecycleState represents the state of a container in the Testcontainers lifecycle,
+// which is updated by the lifecycle machinery as the container moves through it.
+type LifecycleState struct {
+	Created bool // the container has been created
+	Started bool // the container has been started, although it could not be ready yet
+	Ready   bool // the container has been started and its wait strategy, if any, is satisfied
+}
+
+// LifecycleState returns the state of the container in the Testcontainers lifecycle.
+// It's safe to call it while the container is waiting to be ready, e.g. from a different goroutine.
+func (c *DockerContainer) LifecycleState() LifecycleState {
+	c.lifecycleStateMu.RLock()
+	defer c.lifecycleStateMu.RUnlock()
+
+	return c.lifecycleState
+}
+
+// setLifecycleState updates the state of the container in the Testcontainers lifecycle.
+func (c *DockerContainer) setLifecycleState(update func(state *LifecycleState)) {
+	c.lifecycleStateMu.Lock()
+	defer c.lifecycleStateMu.Unlock()
+
+	update(&c.lifecycleState)
+}
+
 // DefaultLoggingHook is a hook that will log the container lifecycle events
 var DefaultLoggingHook = func(logger Logging) ContainerLifecycleHooks {
 	shortContainerID := func(c Container) string {
@@ -159,26 +189,91 @@ var defaultCopyFileToContainerHook = func(files []ContainerFile) ContainerLifecy
 	}
 }
 
+// defaultExtractFilesHook is a hook that will copy files from the container to the host
+// once the container is ready, returning the errors of all the files that could not be copied
+var defaultExtractFilesHook = func(files []ExtractFile) ContainerLifecycleHooks {
+	if len(files) == 0 {
+		return ContainerLifecycleHooks{}
+	}
+
+	return ContainerLifecycleHooks{
+		PostReadies: []ContainerHook{
+			func(ctx context.Context, c Container) error {
+				dockerContainer := c.(*DockerContainer)
+
+				var errs []error
+				for _, f := range files {
+					if err := f.validate(); err != nil {
+						errs = append(errs, fmt.Errorf("invalid extract file: %w", err))
+						continue
+					}
+
+					mode := os.FileMode(0o644)
+					if f.FileMode != 0 {
+						mode = os.FileMode(f.FileMode)
+					}
+
+					if err := dockerContainer.CopyFileFromContainerToHost(ctx, f.ContainerFilePath, f.HostFilePath, mode); err != nil {
+						errs = append(errs, fmt.Errorf("can't copy %s from container: %w", f.ContainerFilePath, err))
+					}
+				}
+
+				return errors.Join(errs...)
+			},
+		},
+	}
+}
+
 // defaultLogConsumersHook is a hook that will start log consumers after the container is started
 var defaultLogConsumersHook = func(cfg *LogConsumerConfig) ContainerLifecycleHooks {
+	// started is true once the log production has been started, so a restart of the
+	// container does not register the consumers twice
+	var started bool
+
 	return ContainerLifecycleHooks{
 		PostStarts: []ContainerHook{
 			// first post-start hook is to produce logs and start log consumers
 			func(ctx context.Context, c Container) error {
 				dockerContainer := c.(*DockerContainer)
 
-				if cfg == nil {
+				if cfg == nil || len(cfg.Consumers) == 0 {
 					return nil
 				}
 
-				for _, consumer := range cfg.Consumers {
-					dockerContainer.followOutput(consumer)
-				}
+				if started {
+					// the production of the previous run completes once the container stops,
+					// so the logs are produced again from the start of the current run
+					if err := dockerContainer.stopLogProduction(); err != nil {
+						return fmt.Errorf("stop log production: %w", err)
+					}
+
+					inspect, err := dockerContainer.inspectRawContainer(ctx)
+					if err != nil {
+						return err
+					}
 
-				if len(cfg.Consumers) > 0 {
-					return dockerContainer.startLogProduction(ctx, cfg.Opts...)
+					startedAt, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt)
+					if err != nil {
+						return fmt.Errorf("parse start time: %w", err)
+					}
+					dockerContainer.logProductionSince = fmt.Sprintf("%d.%09d", startedAt.Unix(), startedAt.Nanosecond())
+				} else {
+					for _, consumer := range cfg.Consumers {
+						if named, ok := consumer.(containerNamedLogConsumer); ok {
+							name, err := dockerContainer.Name(ctx)
+							if err != nil {
+								return fmt.Errorf("get container name: %w", err)
+							}
+							named.setContainerName(name)
+						}
+
+						dockerContainer.followOutput(consumer)
+					}
 				}
-				return nil
+
+				started = true
+
+				return dockerContainer.startLogProduction(ctx, cfg.Opts...)
 			},
 		},
 		PreTerminates: []ContainerHook{
@@ -216,6 +311,51 @@ var defaultReadinessHook = func() ContainerLifecycleHooks {
 				}
 
 				dockerContainer.isRunning = true
+				dockerContainer.setLifecycleState(func(state *LifecycleState) {
+					state.Ready = true
+				})
+
+				return nil
+			},
+		},
+	}
+}
+
+// defaultStatsCaptureHook is a hook that will capture the resource usage of the container
+// before it's terminated, if enabled. Failing to capture it does not prevent the termination.
+var defaultStatsCaptureHook = func(enabled bool) ContainerLifecycleHooks {
+	if !enabled {
+		return ContainerLifecycleHooks{}
+	}
+
+	return ContainerLifecycleHooks{
+		PreTerminates: []ContainerHook{
+			func(ctx context.Context, c Container) error {
+				dockerContainer := c.(*DockerContainer)
+
+				if err := dockerContainer.captureStats(ctx); err != nil {
+					dockerContainer.logger.Printf("⚠️ Failed to capture the stats of container %s: %v", dockerContainer.ID[:12], err)
+				}
+
+				return nil
+			},
+		},
+	}
+}
+
+// defaultStopStrategyHook is a hook that will run the stop strategy of the container
+// right before it's stopped, falling back to the no-op strategy.
+var defaultStopStrategyHook = func(strategy StopStrategy) ContainerLifecycleHooks {
+	if strategy == nil {
+		strategy = NopStopStrategy{}
+	}
+
+	return ContainerLifecycleHooks{
+		PreStops: []ContainerHook{
+			func(ctx context.Context, c Container) error {
+				if err := strategy.BeforeStop(ctx, c); err != nil {
+					return fmt.Errorf("stop strategy: %w", err)
+				}
 
 				return nil
 			},
@@ -261,24 +401,6 @@ func (c *DockerContainer) readiedHook(ctx context.Context) error {
 	})
 }
 
-// printLogs is a helper function that will print the logs of a Docker container
-// We are going to use this helper function to inform the user of the logs when an error occurs
-func (c *DockerContainer) printLogs(ctx context.Context, cause error) {
-	reader, err := c.Logs(ctx)
-	if err != nil {
-		c.logger.Printf("failed accessing container logs: %v\n", err)
-		return
-	}
-
-	b, err := io.ReadAll(reader)
-	if err != nil {
-		c.logger.Printf("failed reading container logs: %v\n", err)
-		return
-	}
-
-	c.logger.Printf("container logs (%s):\n%s", cause, b)
-}
-
 // stoppingHook is a hook that will be called before a container is stopped.
 func (c *DockerContainer) stoppingHook(ctx context.Context) error {
 	return c.applyLifecycleHooks(ctx, false, func(lifecycleHooks ContainerLifecycleHooks) []ContainerHook {
@@ -316,7 +438,7 @@ func (c *DockerContainer) applyLifecycleHooks(ctx context.Context, logError bool
 
 	if err := errors.Join(errs...); err != nil {
 		if logError {
-			c.printLogs(ctx, err)
+			return c.logsError(ctx, err)
 		}
 
 		return err
@@ -395,8 +517,33 @@ func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req Contain
 	// prepare mounts
 	hostConfig.Mounts = mapToDockerMounts(req.Mounts)
 
+	// set the cpusets before the modifiers are applied, so they can still be overridden
+	hostConfig.CpusetCpus = req.CpusetCpus
+	hostConfig.CpusetMems = req.CpusetMems
+
+	// same for the namespace modes
+	hostConfig.PidMode = container.PidMode(req.PidMode)
+	hostConfig.CgroupnsMode = container.CgroupnsMode(req.CgroupnsMode)
+	hostConfig.UsernsMode = container.UsernsMode(req.UsernsMode)
+
+	// same for the security options and the read-only root filesystem
+	hostConfig.SecurityOpt = req.SecurityOpts
+	hostConfig.ReadonlyRootfs = req.ReadOnlyRootfs
+
+	// same for the restart policy
+	hostConfig.RestartPolicy = req.RestartPolicy
+
+	// same for the tmpfs mounts, copied so the modifiers do not change the request
+	hostConfig.Tmpfs = maps.Clone(req.Tmpfs)
+
 	endpointSettings := map[string]*network.EndpointSettings{}
 
+	for networkName := range req.NetworkStaticIPs {
+		if !slices.Contains(req.Networks, networkName) {
+			return fmt.Errorf("static IP requested for network %s, which the container is not attached to", networkName)
+		}
+	}
+
 	// #248: Docker allows only one network to be specified during container creation
 	// If there is more than one network specified in the request container should be attached to them
 	// once it is created. We will take a first network if any specified in the request and use it to create container
@@ -415,18 +562,37 @@ func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req Contain
 				Aliases:   aliases,
 				NetworkID: nw.ID,
 			}
+			if ip, ok := req.NetworkStaticIPs[attachContainerTo]; ok {
+				endpointSetting.IPAMConfig, err = staticIPAMConfig(nw, ip)
+				if err != nil {
+					return err
+				}
+			}
 			endpointSettings[attachContainerTo] = &endpointSetting
+		} else if _, ok := req.NetworkStaticIPs[attachContainerTo]; ok {
+			return fmt.Errorf("get network %s for the static IP: %w", attachContainerTo, err)
 		}
 	}
 
-	if req.ConfigModifier != nil {
-		req.ConfigModifier(dockerInput)
+	req.applyConfigModifiers(dockerInput)
+	req.applyHostConfigModifiers(hostConfig)
+
+	// the tmpfs mounts of the request are kept if a modifier replaced them,
+	// while the ones set by the modifiers take precedence for the same path
+	for path, opts := range req.Tmpfs {
+		if _, ok := hostConfig.Tmpfs[path]; ok {
+			continue
+		}
+		if hostConfig.Tmpfs == nil {
+			hostConfig.Tmpfs = map[string]string{}
+		}
+		hostConfig.Tmpfs[path] = opts
 	}
 
-	if req.HostConfigModifier == nil {
-		req.HostConfigModifier = defaultHostConfigModifier(req)
+	// this must be done after the host config modifier is called, so the network mode is already set
+	if req.MacAddress != "" {
+		p.setMacAddress(req, dockerInput, hostConfig, endpointSettings)
 	}
-	req.HostConfigModifier(hostConfig)
 
 	if req.EnpointSettingsModifier != nil {
 		req.EnpointSettingsModifier(endpointSettings)
@@ -436,12 +602,24 @@ func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req Contain
 
 	exposedPorts := req.ExposedPorts
 	// this check must be done after the pre-creation Modifiers are called, so the network mode is already set
-	if len(exposedPorts) == 0 && !hostConfig.NetworkMode.IsContainer() {
+	if (len(exposedPorts) == 0 || req.AutoExposeImagePorts) && !hostConfig.NetworkMode.IsContainer() {
 		image, _, err := p.client.ImageInspectWithRaw(ctx, dockerInput.Image)
 		if err != nil {
 			return err
 		}
+
+		requestedPorts, _, err := nat.ParsePortSpecs(exposedPorts)
+		if err != nil {
+			return err
+		}
+
+		// copy the exposed ports, so the ones in the request are not modified
+		exposedPorts = append([]string{}, exposedPorts...)
 		for p := range image.Config.ExposedPorts {
+			// skip the ports already exposed by the request, to not bind them twice
+			if _, ok := requestedPorts[p]; ok {
+				continue
+			}
 			exposedPorts = append(exposedPorts, string(p))
 		}
 	}
@@ -453,6 +631,11 @@ func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req Contain
 
 	dockerInput.ExposedPorts = exposedPortSet
 
+	// the port bindings are entirely defined by the host config modifier
+	if req.SkipPortMerge {
+		return nil
+	}
+
 	// only exposing those ports automatically if the container request exposes zero ports and the container does not run in a container network
 	if len(exposedPorts) == 0 && !hostConfig.NetworkMode.IsContainer() {
 		hostConfig.PortBindings = exposedPortMap
@@ -463,6 +646,29 @@ func (p *DockerProvider) preCreateContainerHook(ctx context.Context, req Contain
 	return nil
 }
 
+// setMacAddress sets the MAC address of the request in the endpoint settings of the first network
+// of the container. Before API v1.44, the MAC address is only supported in the container config.
+func (p *DockerProvider) setMacAddress(req ContainerRequest, dockerInput *container.Config, hostConfig *container.HostConfig, endpointSettings map[string]*network.EndpointSettings) {
+	if versions.LessThan(p.client.ClientVersion(), "1.44") {
+		dockerInput.MacAddress = req.MacAddress //nolint:staticcheck // the field is only deprecated for API v1.44 and later
+		return
+	}
+
+	networkName := network.NetworkBridge
+	if len(req.Networks) > 0 {
+		networkName = req.Networks[0]
+	} else if !hostConfig.NetworkMode.IsDefault() {
+		networkName = hostConfig.NetworkMode.NetworkName()
+	}
+
+	endpointSetting, ok := endpointSettings[networkName]
+	if !ok {
+		endpointSetting = &network.EndpointSettings{}
+		endpointSettings[networkName] = endpointSetting
+	}
+	endpointSetting.MacAddress = req.MacAddress
+}
+
 // combineContainerHooks it returns just one ContainerLifecycle hook, as the result of combining
 // the default hooks with the user-defined hooks. The function will loop over all the default hooks,
 // storing each of the hooks in a slice, and then it will loop over all the user-defined hooks,
@@ -553,5 +759,12 @@ func defaultHostConfigModifier(req ContainerRequest) func(hostConfig *container.
 		hostConfig.ExtraHosts = req.ExtraHosts
 		hostConfig.NetworkMode = req.NetworkMode
 		hostConfig.Resources = req.Resources
+		// the cpusets are defined with their own fields, which take precedence over the deprecated Resources
+		if req.CpusetCpus != "" {
+			hostConfig.CpusetCpus = req.CpusetCpus
+		}
+		if req.CpusetMems != "" {
+			hostConfig.CpusetMems = req.CpusetMems
+		}
 	}
 }
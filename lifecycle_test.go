@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/docker/api/types/versions"
 	"github.com/docker/go-connections/nat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -168,6 +169,56 @@ func TestPreCreateModifierHook(t *testing.T) {
 		)
 	})
 
+	t.Run("Skip port merge keeps the port bindings of the modifier", func(t *testing.T) {
+		portBindings := nat.PortMap{
+			"80/tcp": []nat.PortBinding{
+				{
+					HostIP:   "127.0.0.1",
+					HostPort: "8080",
+				},
+			},
+			// not exposed by the request
+			"443/tcp": []nat.PortBinding{
+				{
+					HostIP:   "127.0.0.1",
+					HostPort: "8443",
+				},
+			},
+		}
+
+		req := ContainerRequest{
+			Image:         nginxAlpineImage,
+			ExposedPorts:  []string{"80/tcp", "9000/tcp"},
+			SkipPortMerge: true,
+			HostConfigModifier: func(hostConfig *container.HostConfig) {
+				hostConfig.PortBindings = portBindings
+			},
+		}
+
+		// define empty inputs to be overwritten by the pre create hook
+		inputConfig := &container.Config{
+			Image: req.Image,
+		}
+		inputHostConfig := &container.HostConfig{}
+		inputNetworkingConfig := &network.NetworkingConfig{}
+
+		err = provider.preCreateContainerHook(ctx, req, inputConfig, inputHostConfig, inputNetworkingConfig)
+		require.NoError(t, err)
+
+		assert.Equal(
+			t,
+			nat.PortSet{"80/tcp": struct{}{}, "9000/tcp": struct{}{}},
+			inputConfig.ExposedPorts,
+			"Docker config's exposed ports should be the ones of the request",
+		)
+		assert.Equal(
+			t,
+			portBindings,
+			inputHostConfig.PortBindings,
+			"Host config's port bindings should be the ones of the modifier",
+		)
+	})
+
 	t.Run("Nil hostConfigModifier should apply default host config modifier", func(t *testing.T) {
 		req := ContainerRequest{
 			Image:       nginxAlpineImage, // alpine image does expose port 80
@@ -204,6 +255,179 @@ func TestPreCreateModifierHook(t *testing.T) {
 		assert.Equal(t, req.Resources, inputHostConfig.Resources, "Deprecated Resources should come from the container request")
 	})
 
+	t.Run("Request contains cpusets", func(t *testing.T) {
+		req := ContainerRequest{
+			Image:      nginxAlpineImage, // alpine image does expose port 80
+			CpusetCpus: "0-1,3",
+			CpusetMems: "0",
+			Resources: container.Resources{
+				Memory: 2048,
+			},
+		}
+
+		// define empty inputs to be overwritten by the pre create hook
+		inputConfig := &container.Config{
+			Image: req.Image,
+		}
+		inputHostConfig := &container.HostConfig{}
+		inputNetworkingConfig := &network.NetworkingConfig{}
+
+		err = provider.preCreateContainerHook(ctx, req, inputConfig, inputHostConfig, inputNetworkingConfig)
+		require.NoError(t, err)
+
+		// assertions
+
+		assert.Equal(t, "0-1,3", inputHostConfig.CpusetCpus)
+		assert.Equal(t, "0", inputHostConfig.CpusetMems)
+		assert.Equal(t, int64(2048), inputHostConfig.Memory, "Deprecated Resources should come from the container request")
+	})
+
+	t.Run("Request contains pid, cgroupns and userns modes", func(t *testing.T) {
+		req := ContainerRequest{
+			Image:        nginxAlpineImage, // alpine image does expose port 80
+			PidMode:      "host",
+			CgroupnsMode: "private",
+			UsernsMode:   "host",
+		}
+
+		// define empty inputs to be overwritten by the pre create hook
+		inputConfig := &container.Config{
+			Image: req.Image,
+		}
+		inputHostConfig := &container.HostConfig{}
+		inputNetworkingConfig := &network.NetworkingConfig{}
+
+		err = provider.preCreateContainerHook(ctx, req, inputConfig, inputHostConfig, inputNetworkingConfig)
+		require.NoError(t, err)
+
+		// assertions
+
+		assert.Equal(t, container.PidMode("host"), inputHostConfig.PidMode)
+		assert.Equal(t, container.CgroupnsMode("private"), inputHostConfig.CgroupnsMode)
+		assert.Equal(t, container.UsernsMode("host"), inputHostConfig.UsernsMode)
+	})
+
+	t.Run("Request contains tmpfs mounts, merged with the ones of the modifier", func(t *testing.T) {
+		req := ContainerRequest{
+			Image: nginxAlpineImage, // alpine image does expose port 80
+			Tmpfs: map[string]string{
+				"/run": "rw,size=64m",
+				"/tmp": "rw",
+			},
+			HostConfigModifier: func(hc *container.HostConfig) {
+				hc.Tmpfs = map[string]string{
+					"/tmp":   "rw,size=16m",
+					"/cache": "rw,noexec",
+				}
+			},
+		}
+
+		// define empty inputs to be overwritten by the pre create hook
+		inputConfig := &container.Config{
+			Image: req.Image,
+		}
+		inputHostConfig := &container.HostConfig{}
+		inputNetworkingConfig := &network.NetworkingConfig{}
+
+		err = provider.preCreateContainerHook(ctx, req, inputConfig, inputHostConfig, inputNetworkingConfig)
+		require.NoError(t, err)
+
+		// assertions
+
+		assert.Equal(t, map[string]string{
+			"/run":   "rw,size=64m",
+			"/tmp":   "rw,size=16m",
+			"/cache": "rw,noexec",
+		}, inputHostConfig.Tmpfs)
+
+		// the request is not modified
+		assert.Equal(t, map[string]string{"/run": "rw,size=64m", "/tmp": "rw"}, req.Tmpfs)
+	})
+
+	t.Run("Request contains security options", func(t *testing.T) {
+		req := ContainerRequest{
+			Image:        nginxAlpineImage, // alpine image does expose port 80
+			SecurityOpts: []string{"no-new-privileges", "apparmor=unconfined"},
+		}
+
+		// define empty inputs to be overwritten by the pre create hook
+		inputConfig := &container.Config{
+			Image: req.Image,
+		}
+		inputHostConfig := &container.HostConfig{}
+		inputNetworkingConfig := &network.NetworkingConfig{}
+
+		err = provider.preCreateContainerHook(ctx, req, inputConfig, inputHostConfig, inputNetworkingConfig)
+		require.NoError(t, err)
+
+		// assertions
+
+		assert.Equal(t, []string{"no-new-privileges", "apparmor=unconfined"}, inputHostConfig.SecurityOpt)
+	})
+
+	t.Run("Request contains a read-only root filesystem", func(t *testing.T) {
+		req := ContainerRequest{
+			Image:          nginxAlpineImage, // alpine image does expose port 80
+			ReadOnlyRootfs: true,
+		}
+
+		// define empty inputs to be overwritten by the pre create hook
+		inputConfig := &container.Config{
+			Image: req.Image,
+		}
+		inputHostConfig := &container.HostConfig{}
+		inputNetworkingConfig := &network.NetworkingConfig{}
+
+		err = provider.preCreateContainerHook(ctx, req, inputConfig, inputHostConfig, inputNetworkingConfig)
+		require.NoError(t, err)
+
+		// assertions
+
+		assert.True(t, inputHostConfig.ReadonlyRootfs)
+	})
+
+	t.Run("Request contains a MAC address", func(t *testing.T) {
+		// the client version is negotiated with the daemon on the first request
+		_, err := provider.client.Ping(ctx)
+		require.NoError(t, err)
+		if versions.LessThan(provider.client.ClientVersion(), "1.44") {
+			t.Skip("the MAC address is set in the endpoint settings since API v1.44")
+		}
+
+		networkName := "foo"
+		net, err := provider.CreateNetwork(ctx, NetworkRequest{
+			Name: networkName,
+		})
+		require.NoError(t, err)
+		defer func() {
+			err := net.Remove(ctx)
+			if err != nil {
+				t.Logf("failed to remove network %s: %s\n", networkName, err)
+			}
+		}()
+
+		req := ContainerRequest{
+			Image:      nginxAlpineImage, // alpine image does expose port 80
+			Networks:   []string{networkName},
+			MacAddress: "02:42:ac:11:00:02",
+		}
+
+		// define empty inputs to be overwritten by the pre create hook
+		inputConfig := &container.Config{
+			Image: req.Image,
+		}
+		inputHostConfig := &container.HostConfig{}
+		inputNetworkingConfig := &network.NetworkingConfig{}
+
+		err = provider.preCreateContainerHook(ctx, req, inputConfig, inputHostConfig, inputNetworkingConfig)
+		require.NoError(t, err)
+
+		// assertions
+
+		assert.Equal(t, req.MacAddress, inputNetworkingConfig.EndpointsConfig[networkName].MacAddress)
+		assert.Empty(t, inputConfig.MacAddress) //nolint:staticcheck // the deprecated field must not be used
+	})
+
 	t.Run("Request contains more than one network including aliases", func(t *testing.T) {
 		networkName := "foo"
 		net, err := provider.CreateNetwork(ctx, NetworkRequest{
@@ -846,6 +1070,11 @@ func TestPrintContainerLogsOnError(t *testing.T) {
 	}
 	terminateContainerOnEnd(t, ctx, container)
 
+	// the logs at the failure point are available in the error, numbered per stream
+	var logsErr *LogsError
+	require.ErrorAs(t, err, &logsErr)
+	require.Equal(t, []LogLine{{Stream: StdoutLog, Number: 1, Text: "I am expecting this"}}, logsErr.Lines)
+
 	containerLogs, err := container.Logs(ctx)
 	if err != nil {
 		t.Fatal(err)
@@ -916,3 +1145,51 @@ func lifecycleHooksIsHonouredFn(t *testing.T, ctx context.Context, prints []stri
 	assert.True(t, strings.HasPrefix(prints[22], "post-terminate hook 1: "))
 	assert.True(t, strings.HasPrefix(prints[23], "post-terminate hook 2: "))
 }
+
+func TestLifecycleState(t *testing.T) {
+	ctx := context.Background()
+
+	var states []LifecycleState
+	recordState := func(ctx context.Context, c Container) error {
+		states = append(states, c.(*DockerContainer).LifecycleState())
+		return nil
+	}
+
+	c, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			Image:        nginxAlpineImage,
+			ExposedPorts: []string{"80/tcp"},
+			WaitingFor:   wait.ForListeningPort("80/tcp"),
+			LifecycleHooks: []ContainerLifecycleHooks{
+				{
+					PostCreates: []ContainerHook{recordState},
+					PreStarts:   []ContainerHook{recordState},
+					PostStarts:  []ContainerHook{recordState},
+					PostReadies: []ContainerHook{recordState},
+					PostStops:   []ContainerHook{recordState},
+				},
+			},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+
+	dockerContainer := c.(*DockerContainer)
+	require.Equal(t, LifecycleState{Created: true, Started: true, Ready: true}, dockerContainer.LifecycleState())
+
+	err = c.Stop(ctx, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, []LifecycleState{
+		{Created: true},                             // post-create
+		{Created: true},                             // pre-start
+		{Created: true, Started: true},              // post-start, before the wait strategy is satisfied
+		{Created: true, Started: true, Ready: true}, // post-ready
+		{Created: true},                             // post-stop
+	}, states)
+
+	err = c.Terminate(ctx)
+	require.NoError(t, err)
+
+	require.Equal(t, LifecycleState{}, dockerContainer.LifecycleState())
+}
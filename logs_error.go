@@ -0,0 +1,203 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// LogLine is a line of the logs of a container.
+type LogLine struct {
+	Stream string // the stream of the line: StdoutLog or StderrLog
+	Number int    // the line number in its stream, starting at 1
+	Text   string // the content of the line, without the line break
+}
+
+// String returns the line referencing its stream and line number, e.g. "STDERR 42: message".
+func (l LogLine) String() string {
+	return fmt.Sprintf("%s %d: %s", l.Stream, l.Number, l.Text)
+}
+
+// defaultMaxErrorLogBytes is the default maximum size of the logs captured when a container fails.
+const defaultMaxErrorLogBytes = 1 << 20
+
+// LogsError is returned when a container fails to start or to be ready, e.g. when
+// its wait strategy fails, wrapping the cause and the logs of the container at that
+// point, so they can be reported in a structured way. It can be retrieved from the
+// error returned by the container using errors.As.
+type LogsError struct {
+	Err       error     // the cause of the failure
+	Lines     []LogLine // the logs of the container when it failed, in the order they were written
+	Truncated bool      // true if the oldest logs were dropped to keep them under the maximum size
+}
+
+// Error implements the error interface, returning the message of the cause.
+func (e *LogsError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the cause of the failure.
+func (e *LogsError) Unwrap() error {
+	return e.Err
+}
+
+// logsError prints the logs of the container, to inform the user when an error occurs,
+// and returns the error wrapped into a LogsError with them.
+func (c *DockerContainer) logsError(ctx context.Context, cause error) error {
+	maxBytes := c.provider.maxErrorLogBytes()
+	lines, truncated, err := c.logLines(ctx, "all", maxBytes)
+	if err != nil {
+		c.logger.Printf("failed reading container logs: %v\n", err)
+		return cause
+	}
+
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(line.Text)
+		sb.WriteString("\n")
+	}
+
+	if truncated {
+		c.logger.Printf("container logs, truncated to the last %d bytes (%s):\n%s", maxBytes, cause, sb.String())
+	} else {
+		c.logger.Printf("container logs (%s):\n%s", cause, sb.String())
+	}
+
+	return &LogsError{Err: cause, Lines: lines, Truncated: truncated}
+}
+
+// maxErrorLogBytes returns the maximum size of the logs captured when a container fails,
+// set by WithMaxErrorLogBytes, or else by the configuration, or else the default. Zero means
+// unset in both of them, falling back to the next one, and a negative number means no limit.
+func (p *DockerProvider) maxErrorLogBytes() int {
+	switch {
+	case p.DockerProviderOptions != nil && p.DockerProviderOptions.maxErrorLogBytes != 0:
+		return p.DockerProviderOptions.maxErrorLogBytes
+	case p.config.Config.MaxErrorLogBytes != 0:
+		return p.config.Config.MaxErrorLogBytes
+	default:
+		return defaultMaxErrorLogBytes
+	}
+}
+
+// logLines returns the lines of both the stdout and stderr logs of the container,
+// limited to the last tail lines per stream by the Docker engine, or "all" of them,
+// keeping only the last maxBytes of them, unless maxBytes is negative. The logs within
+// the tail are still transferred from the Docker engine, as it cannot limit them by size.
+func (c *DockerContainer) logLines(ctx context.Context, tail string, maxBytes int) ([]LogLine, bool, error) {
+	inspect, err := c.Inspect(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rc, err := c.provider.client.ContainerLogs(ctx, c.ID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       tail,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	defer rc.Close()
+
+	return readLogLines(rc, inspect.Config != nil && inspect.Config.Tty, maxBytes)
+}
+
+// readLogLines splits the logs of a container into lines, numbering them per stream.
+// Without a TTY, the logs are multiplexed, so each line is tagged with its stream.
+// With a TTY, all the lines belong to stdout. The logs are read to the end, so the lines
+// keep their numbers, but only the last maxBytes of them are kept, unless maxBytes is
+// negative, and it returns true if older lines were dropped.
+func readLogLines(r io.Reader, tty bool, maxBytes int) ([]LogLine, bool, error) {
+	tail := &logTail{maxBytes: maxBytes}
+	stdout := &logLineWriter{stream: StdoutLog, tail: tail}
+	stderr := &logLineWriter{stream: StderrLog, tail: tail}
+
+	var err error
+	if tty {
+		_, err = io.Copy(stdout, r)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, r)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("read logs: %w", err)
+	}
+
+	stdout.flush()
+	stderr.flush()
+
+	return tail.lines, tail.truncated, nil
+}
+
+// logTail is the list of lines shared by the streams, so the order in which the lines
+// were written is kept, dropping the oldest ones when they exceed the maximum size.
+type logTail struct {
+	lines     []LogLine
+	size      int
+	maxBytes  int
+	truncated bool
+}
+
+func (t *logTail) add(line LogLine) {
+	t.lines = append(t.lines, line)
+	t.size += len(line.Text) + 1
+
+	if t.maxBytes < 0 {
+		return
+	}
+
+	// the last line is always kept, as its length is already limited by the writer
+	for t.size > t.maxBytes && len(t.lines) > 1 {
+		t.size -= len(t.lines[0].Text) + 1
+		t.lines = t.lines[1:]
+		t.truncated = true
+	}
+}
+
+// logLineWriter splits the logs written to it into lines of a stream.
+type logLineWriter struct {
+	stream  string
+	tail    *logTail
+	number  int
+	partial []byte
+}
+
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.add(string(w.partial[:i]))
+		w.partial = w.partial[i+1:]
+	}
+
+	// a line without line breaks cannot grow beyond the maximum size, keeping its end
+	if maxBytes := w.tail.maxBytes; maxBytes >= 0 && len(w.partial) > maxBytes {
+		w.partial = append([]byte(nil), w.partial[len(w.partial)-maxBytes:]...)
+		w.tail.truncated = true
+	}
+
+	return len(p), nil
+}
+
+// flush adds the last line, if it does not end with a line break.
+func (w *logLineWriter) flush() {
+	if len(w.partial) > 0 {
+		w.add(string(w.partial))
+		w.partial = nil
+	}
+}
+
+func (w *logLineWriter) add(text string) {
+	w.number++
+	w.tail.add(LogLine{Stream: w.stream, Number: w.number, Text: text})
+}
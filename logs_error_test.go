@@ -0,0 +1,128 @@
+package testcontainers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestReadLogLines(t *testing.T) {
+	t.Run("multiplexed", func(t *testing.T) {
+		var buf bytes.Buffer
+		stdout := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+		stderr := stdcopy.NewStdWriter(&buf, stdcopy.Stderr)
+
+		_, err := stdout.Write([]byte("starting\nlistening"))
+		require.NoError(t, err)
+		_, err = stderr.Write([]byte("warning\n"))
+		require.NoError(t, err)
+		_, err = stdout.Write([]byte(" on port 80\n"))
+		require.NoError(t, err)
+		_, err = stderr.Write([]byte("error\nfatal"))
+		require.NoError(t, err)
+
+		lines, truncated, err := readLogLines(&buf, false, -1)
+		require.NoError(t, err)
+		require.False(t, truncated)
+		require.Equal(t, []LogLine{
+			{Stream: StdoutLog, Number: 1, Text: "starting"},
+			{Stream: StderrLog, Number: 1, Text: "warning"},
+			{Stream: StdoutLog, Number: 2, Text: "listening on port 80"},
+			{Stream: StderrLog, Number: 2, Text: "error"},
+			{Stream: StderrLog, Number: 3, Text: "fatal"},
+		}, lines)
+		require.Equal(t, "STDERR 3: fatal", lines[4].String())
+	})
+
+	t.Run("tty", func(t *testing.T) {
+		lines, truncated, err := readLogLines(bytes.NewReader([]byte("first\nsecond\n")), true, -1)
+		require.NoError(t, err)
+		require.False(t, truncated)
+		require.Equal(t, []LogLine{
+			{Stream: StdoutLog, Number: 1, Text: "first"},
+			{Stream: StdoutLog, Number: 2, Text: "second"},
+		}, lines)
+	})
+
+	t.Run("max-bytes", func(t *testing.T) {
+		var buf bytes.Buffer
+		stdout := stdcopy.NewStdWriter(&buf, stdcopy.Stdout)
+		stderr := stdcopy.NewStdWriter(&buf, stdcopy.Stderr)
+
+		_, err := stdout.Write([]byte("first\nsecond\n"))
+		require.NoError(t, err)
+		_, err = stderr.Write([]byte("third\n"))
+		require.NoError(t, err)
+
+		// only the last lines fitting in 13 bytes, line breaks included, are kept
+		lines, truncated, err := readLogLines(&buf, false, 13)
+		require.NoError(t, err)
+		require.True(t, truncated)
+		require.Equal(t, []LogLine{
+			{Stream: StdoutLog, Number: 2, Text: "second"},
+			{Stream: StderrLog, Number: 1, Text: "third"},
+		}, lines)
+	})
+
+	t.Run("max-bytes/long-line", func(t *testing.T) {
+		// the end of a line longer than the maximum size is kept
+		lines, truncated, err := readLogLines(bytes.NewReader([]byte("first\n"+strings.Repeat("a", 100)+"end")), true, 10)
+		require.NoError(t, err)
+		require.True(t, truncated)
+		require.Equal(t, []LogLine{{Stream: StdoutLog, Number: 2, Text: "aaaaaaaend"}}, lines)
+	})
+}
+
+func TestLogsError(t *testing.T) {
+	cause := errors.New("wait until ready: context deadline exceeded")
+
+	err := fmt.Errorf("start container: %w", &LogsError{
+		Err:   cause,
+		Lines: []LogLine{{Stream: StderrLog, Number: 1, Text: "boom"}},
+	})
+	require.EqualError(t, err, "start container: wait until ready: context deadline exceeded")
+	require.ErrorIs(t, err, cause)
+
+	var logsErr *LogsError
+	require.ErrorAs(t, err, &logsErr)
+	require.Equal(t, "STDERR 1: boom", logsErr.Lines[0].String())
+}
+
+func TestLogsErrorMaxBytes(t *testing.T) {
+	ctx := context.Background()
+
+	provider, err := NewDockerProvider(WithMaxErrorLogBytes(1024))
+	require.NoError(t, err)
+	defer provider.Close()
+
+	// around 600KB of logs are written before the wait strategy fails
+	ctr, err := provider.RunContainer(ctx, ContainerRequest{
+		Image:      "docker.io/alpine",
+		Cmd:        []string{"seq", "1", "100000"},
+		WaitingFor: wait.ForLog("never written").WithStartupTimeout(5 * time.Second),
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.Error(t, err)
+
+	var logsErr *LogsError
+	require.ErrorAs(t, err, &logsErr)
+	require.True(t, logsErr.Truncated)
+
+	size := 0
+	for _, line := range logsErr.Lines {
+		size += len(line.Text) + 1
+	}
+	require.LessOrEqual(t, size, 1024)
+
+	// the tail of the logs is kept, with the numbers of the lines
+	require.Equal(t, LogLine{Stream: StdoutLog, Number: 100000, Text: "100000"}, logsErr.Lines[len(logsErr.Lines)-1])
+}
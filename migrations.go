@@ -0,0 +1,48 @@
+package testcontainers
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// MigrationRunner applies the content of a migration file, e.g. a SQL script, to the container.
+// The name is the path of the file in the file system of the migrations.
+type MigrationRunner func(ctx context.Context, ctr Container, name string, content []byte) error
+
+// ApplyMigrations applies the migration files of the file system matching the pattern, using the runner.
+// The files are applied in lexical order of their paths, so they can be prefixed with their version,
+// e.g. "migrations/001_create_users.sql", and the first error stops applying them.
+// The pattern follows the syntax of fs.Glob, and the directories matching it are skipped.
+// The file system is usually embedded in the tests with a go:embed directive.
+func ApplyMigrations(ctx context.Context, ctr Container, fsys fs.FS, pattern string, runner MigrationRunner) error {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return fmt.Errorf("glob migrations: %w", err)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		info, err := fs.Stat(fsys, name)
+		if err != nil {
+			return fmt.Errorf("stat migration %s: %w", name, err)
+		}
+
+		if info.IsDir() {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("read migration %s: %w", name, err)
+		}
+
+		if err := runner(ctx, ctr, name, content); err != nil {
+			return fmt.Errorf("apply migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
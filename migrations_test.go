@@ -0,0 +1,68 @@
+package testcontainers
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/migrations
+var testMigrations embed.FS
+
+func TestApplyMigrations(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sorted", func(t *testing.T) {
+		var applied []string
+		err := ApplyMigrations(ctx, nil, testMigrations, "testdata/migrations/*.sql", func(_ context.Context, _ Container, name string, content []byte) error {
+			applied = append(applied, name+": "+string(content))
+			return nil
+		})
+		require.NoError(t, err)
+
+		// the directory matching the pattern and the files not matching it are skipped
+		require.Equal(t, []string{
+			"testdata/migrations/001_create_schema.sql: CREATE SCHEMA app;\n",
+			"testdata/migrations/002_create_users.sql: CREATE TABLE users (id INT);\n",
+			"testdata/migrations/010_add_user_name.sql: ALTER TABLE users ADD COLUMN name TEXT;\n",
+		}, applied)
+	})
+
+	t.Run("runner-error", func(t *testing.T) {
+		errRunner := errors.New("syntax error")
+
+		var applied []string
+		err := ApplyMigrations(ctx, nil, testMigrations, "testdata/migrations/*.sql", func(_ context.Context, _ Container, name string, _ []byte) error {
+			applied = append(applied, name)
+			if name == "testdata/migrations/002_create_users.sql" {
+				return errRunner
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errRunner)
+		require.ErrorContains(t, err, "apply migration testdata/migrations/002_create_users.sql")
+
+		// the migrations after the failing one are not applied
+		require.Equal(t, []string{
+			"testdata/migrations/001_create_schema.sql",
+			"testdata/migrations/002_create_users.sql",
+		}, applied)
+	})
+
+	t.Run("no-match", func(t *testing.T) {
+		err := ApplyMigrations(ctx, nil, testMigrations, "testdata/migrations/*.cql", func(_ context.Context, _ Container, _ string, _ []byte) error {
+			return errors.New("unexpected migration")
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("bad-pattern", func(t *testing.T) {
+		err := ApplyMigrations(ctx, nil, testMigrations, "testdata/migrations/[", func(_ context.Context, _ Container, _ string, _ []byte) error {
+			return nil
+		})
+		require.ErrorContains(t, err, "glob migrations")
+	})
+}
@@ -0,0 +1,95 @@
+package testcontainers
+
+import (
+	"sort"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ModifierPriority is the priority of a config or host config modifier registered in a container request.
+// Before creating the container, the modifiers are applied in ascending priority, and in the order they were
+// registered when they have the same priority, so a modifier can override the changes of the modifiers
+// with a lower priority. Priorities between the predefined ones can be used to run in between them.
+type ModifierPriority int
+
+const (
+	// ModifierPriorityDefault is the priority of the modifiers providing the defaults of the container
+	ModifierPriorityDefault ModifierPriority = 0
+	// ModifierPriorityModule is the priority of the modifiers registered by the modules. The ConfigModifier
+	// and HostConfigModifier fields of the request are applied at this priority, before the registered ones.
+	ModifierPriorityModule ModifierPriority = 100
+	// ModifierPriorityUser is the priority of the modifiers registered by the users of the modules,
+	// so they run after the ones of the modules and can override them
+	ModifierPriorityUser ModifierPriority = 200
+)
+
+// configModifier is a config modifier registered at a priority
+type configModifier struct {
+	priority ModifierPriority
+	modify   func(*container.Config)
+}
+
+// hostConfigModifier is a host config modifier registered at a priority
+type hostConfigModifier struct {
+	priority ModifierPriority
+	modify   func(*container.HostConfig)
+}
+
+// WithConfigModifierAt registers a modifier for the config of the container at the given priority,
+// in addition to the ConfigModifier field and to the other registered modifiers.
+func WithConfigModifierAt(priority ModifierPriority, modifier func(config *container.Config)) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.configModifiers = append(req.configModifiers, configModifier{priority: priority, modify: modifier})
+
+		return nil
+	}
+}
+
+// WithHostConfigModifierAt registers a modifier for the host config of the container at the given priority,
+// in addition to the HostConfigModifier field and to the other registered modifiers.
+func WithHostConfigModifierAt(priority ModifierPriority, modifier func(hostConfig *container.HostConfig)) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.hostConfigModifiers = append(req.hostConfigModifiers, hostConfigModifier{priority: priority, modify: modifier})
+
+		return nil
+	}
+}
+
+// applyConfigModifiers applies the ConfigModifier field and the registered config modifiers, in order.
+func (c *ContainerRequest) applyConfigModifiers(config *container.Config) {
+	modifiers := make([]configModifier, 0, len(c.configModifiers)+1)
+	if c.ConfigModifier != nil {
+		modifiers = append(modifiers, configModifier{priority: ModifierPriorityModule, modify: c.ConfigModifier})
+	}
+	modifiers = append(modifiers, c.configModifiers...)
+
+	// the stable sort keeps the registration order for the same priority
+	sort.SliceStable(modifiers, func(i, j int) bool {
+		return modifiers[i].priority < modifiers[j].priority
+	})
+
+	for _, m := range modifiers {
+		m.modify(config)
+	}
+}
+
+// applyHostConfigModifiers applies the HostConfigModifier field and the registered host config modifiers, in order.
+// Without the HostConfigModifier field, the deprecated host config fields of the request are set at the default priority.
+func (c *ContainerRequest) applyHostConfigModifiers(hostConfig *container.HostConfig) {
+	modifiers := make([]hostConfigModifier, 0, len(c.hostConfigModifiers)+1)
+	if c.HostConfigModifier != nil {
+		modifiers = append(modifiers, hostConfigModifier{priority: ModifierPriorityModule, modify: c.HostConfigModifier})
+	} else {
+		modifiers = append(modifiers, hostConfigModifier{priority: ModifierPriorityDefault, modify: defaultHostConfigModifier(*c)})
+	}
+	modifiers = append(modifiers, c.hostConfigModifiers...)
+
+	// the stable sort keeps the registration order for the same priority
+	sort.SliceStable(modifiers, func(i, j int) bool {
+		return modifiers[i].priority < modifiers[j].priority
+	})
+
+	for _, m := range modifiers {
+		m.modify(hostConfig)
+	}
+}
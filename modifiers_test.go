@@ -0,0 +1,147 @@
+package testcontainers
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModifierPriority(t *testing.T) {
+	var calls []string
+
+	req := GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			ConfigModifier: func(config *container.Config) {
+				calls = append(calls, "module-field")
+				config.User = "module"
+			},
+			HostConfigModifier: func(hostConfig *container.HostConfig) {
+				calls = append(calls, "module-field")
+				hostConfig.Privileged = true
+				hostConfig.ShmSize = 1024
+			},
+		},
+	}
+
+	// the user modifiers are registered first, but they run after the module ones
+	opts := []CustomizeRequestOption{
+		WithConfigModifierAt(ModifierPriorityUser, func(config *container.Config) {
+			calls = append(calls, "user")
+			config.User = "user"
+		}),
+		WithHostConfigModifierAt(ModifierPriorityUser, func(hostConfig *container.HostConfig) {
+			calls = append(calls, "user")
+			hostConfig.Privileged = false
+		}),
+		WithConfigModifierAt(ModifierPriorityModule, func(config *container.Config) {
+			calls = append(calls, "module")
+			config.WorkingDir = "/module"
+		}),
+		WithHostConfigModifierAt(ModifierPriorityModule, func(hostConfig *container.HostConfig) {
+			calls = append(calls, "module")
+			hostConfig.ShmSize = 2048
+		}),
+		WithConfigModifierAt(ModifierPriorityDefault, func(config *container.Config) {
+			calls = append(calls, "default")
+			config.User = "default"
+			config.WorkingDir = "/default"
+		}),
+		WithHostConfigModifierAt(ModifierPriorityDefault, func(hostConfig *container.HostConfig) {
+			calls = append(calls, "default")
+			hostConfig.ShmSize = 512
+		}),
+	}
+	for _, opt := range opts {
+		require.NoError(t, opt(&req))
+	}
+
+	t.Run("config", func(t *testing.T) {
+		calls = nil
+
+		config := &container.Config{}
+		req.applyConfigModifiers(config)
+
+		require.Equal(t, []string{"default", "module-field", "module", "user"}, calls)
+		require.Equal(t, "user", config.User)
+		require.Equal(t, "/module", config.WorkingDir)
+	})
+
+	t.Run("host-config", func(t *testing.T) {
+		calls = nil
+
+		hostConfig := &container.HostConfig{}
+		req.applyHostConfigModifiers(hostConfig)
+
+		require.Equal(t, []string{"default", "module-field", "module", "user"}, calls)
+		require.False(t, hostConfig.Privileged)
+		require.Equal(t, int64(2048), hostConfig.ShmSize)
+	})
+
+	t.Run("default-host-config-modifier", func(t *testing.T) {
+		req := GenericContainerRequest{
+			ContainerRequest: ContainerRequest{
+				CapAdd: []string{"NET_ADMIN"},
+			},
+		}
+		require.NoError(t, WithHostConfigModifierAt(ModifierPriorityUser, func(hostConfig *container.HostConfig) {
+			hostConfig.CapAdd = append(hostConfig.CapAdd, "SYS_TIME")
+		})(&req))
+
+		hostConfig := &container.HostConfig{}
+		req.applyHostConfigModifiers(hostConfig)
+
+		require.Equal(t, []string{"NET_ADMIN", "SYS_TIME"}, []string(hostConfig.CapAdd))
+	})
+
+	t.Run("default-host-config-modifier/cpusets", func(t *testing.T) {
+		// the cpusets fields take precedence over the ones of the deprecated Resources
+		req := ContainerRequest{
+			CpusetCpus: "0-1,3",
+			Resources: container.Resources{
+				CpusetCpus: "2",
+				CpusetMems: "1",
+				Memory:     2048,
+			},
+		}
+
+		hostConfig := &container.HostConfig{}
+		req.applyHostConfigModifiers(hostConfig)
+
+		require.Equal(t, "0-1,3", hostConfig.CpusetCpus)
+		require.Equal(t, "1", hostConfig.CpusetMems)
+		require.Equal(t, int64(2048), hostConfig.Memory)
+	})
+
+	t.Run("with-modifier-options", func(t *testing.T) {
+		// the options don't replace the modifier fields set by the module
+		req := GenericContainerRequest{
+			ContainerRequest: ContainerRequest{
+				ConfigModifier: func(config *container.Config) {
+					config.User = "module"
+					config.WorkingDir = "/module"
+				},
+				HostConfigModifier: func(hostConfig *container.HostConfig) {
+					hostConfig.Privileged = true
+					hostConfig.ShmSize = 1024
+				},
+			},
+		}
+		require.NoError(t, WithConfigModifier(func(config *container.Config) {
+			config.User = "user"
+		})(&req))
+		require.NoError(t, WithHostConfigModifier(func(hostConfig *container.HostConfig) {
+			hostConfig.ShmSize = 2048
+		})(&req))
+
+		config := &container.Config{}
+		req.applyConfigModifiers(config)
+		require.Equal(t, "user", config.User)
+		require.Equal(t, "/module", config.WorkingDir)
+
+		hostConfig := &container.HostConfig{}
+		req.applyHostConfigModifiers(hostConfig)
+		require.True(t, hostConfig.Privileged)
+		require.Equal(t, int64(2048), hostConfig.ShmSize)
+	})
+}
@@ -22,7 +22,8 @@ type CassandraContainer struct {
 }
 
 // ConnectionHost returns the host and port of the cassandra container, using the default, native 9000 port, and
-// obtaining the host and exposed port from the container
+// obtaining the host and exposed port from the container. The container details they're resolved from are cached
+// by the container, until it's stopped, started or restarted.
 func (c *CassandraContainer) ConnectionHost(ctx context.Context) (string, error) {
 	host, err := c.Host(ctx)
 	if err != nil {
@@ -37,6 +38,17 @@ func (c *CassandraContainer) ConnectionHost(ctx context.Context) (string, error)
 	return host + ":" + port.Port(), nil
 }
 
+// MustConnectionHost panics if the address cannot be determined.
+// It takes a context, like the MySQL module's MustConnectionString, so that
+// resolving the address honours the caller's deadline.
+func (c *CassandraContainer) MustConnectionHost(ctx context.Context) string {
+	host, err := c.ConnectionHost(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return host
+}
+
 // WithConfigFile sets the YAML config file to be used for the cassandra container
 // It will also set the "configFile" parameter to the path of the config file
 // as a command line argument to the container.
@@ -106,6 +106,7 @@ func TestCassandraWithInitScripts(t *testing.T) {
 		connectionHost, err := container.ConnectionHost(ctx)
 		// }
 		require.NoError(t, err)
+		require.Equal(t, connectionHost, container.MustConnectionHost(ctx))
 
 		cluster := gocql.NewCluster(connectionHost)
 		session, err := cluster.CreateSession()
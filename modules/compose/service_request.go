@@ -0,0 +1,192 @@
+package compose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// serviceRequestProject is the name of the project the compose file is loaded into,
+// which is required by the loader, even though the request is not part of any project.
+const serviceRequestProject = "testcontainers"
+
+// supportedServiceKeys are the keys of a service converted into a ContainerRequest.
+var supportedServiceKeys = map[string]bool{
+	"image":       true,
+	"ports":       true,
+	"environment": true,
+	"env_file":    true,
+	"volumes":     true,
+	"command":     true,
+}
+
+type serviceRequestOptions struct {
+	Logger testcontainers.Logging
+}
+
+// ServiceRequestOption defines a common interface to customize the conversion
+// of a compose service into a ContainerRequest.
+type ServiceRequestOption interface {
+	applyToServiceRequest(o *serviceRequestOptions)
+}
+
+func (o ComposeLoggerOption) applyToServiceRequest(opts *serviceRequestOptions) {
+	opts.Logger = o.logger
+}
+
+// ContainerRequestFromService builds a ContainerRequest out of a single service of a compose file,
+// so its definition can be reused without running the whole compose stack.
+// The compose file is loaded with the compose-go loader, so variables are interpolated from the
+// current environment, and relative host paths are resolved against the current working directory.
+// Only the image, ports, environment, env_file, volumes and command keys are supported: any other
+// key of the service is ignored, logging a warning. Because the returned request is not part of
+// a compose project, the published ports are replaced by random host ports.
+func ContainerRequestFromService(composeYAML []byte, serviceName string, opts ...ServiceRequestOption) (testcontainers.ContainerRequest, error) {
+	o := serviceRequestOptions{
+		Logger: testcontainers.Logger,
+	}
+	for _, opt := range opts {
+		opt.applyToServiceRequest(&o)
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return testcontainers.ContainerRequest{}, err
+	}
+
+	project, err := loader.LoadWithContext(context.Background(), types.ConfigDetails{
+		WorkingDir:  workingDir,
+		ConfigFiles: []types.ConfigFile{{Filename: filepath.Join(workingDir, "compose.yaml"), Content: composeYAML}},
+		Environment: types.NewMapping(os.Environ()),
+	}, func(opts *loader.Options) {
+		opts.SetProjectName(serviceRequestProject, true)
+		// the service is converted alone, so the volumes and networks it refers to are not required to be declared
+		opts.SkipConsistencyCheck = true
+	})
+	if err != nil {
+		return testcontainers.ContainerRequest{}, fmt.Errorf("parse compose file: %w", err)
+	}
+
+	service, ok := project.Services[serviceName]
+	if !ok {
+		return testcontainers.ContainerRequest{}, fmt.Errorf("service %q not found", serviceName)
+	}
+
+	if err := warnUnsupportedServiceKeys(composeYAML, serviceName, o.Logger); err != nil {
+		return testcontainers.ContainerRequest{}, err
+	}
+
+	if service.Image == "" {
+		return testcontainers.ContainerRequest{}, fmt.Errorf("service %q has no image", serviceName)
+	}
+
+	req := testcontainers.ContainerRequest{
+		Image:        service.Image,
+		ExposedPorts: servicePorts(service, o.Logger),
+		Env:          serviceEnvironment(service),
+		Cmd:          service.Command,
+	}
+
+	serviceVolumes(service, &req, o.Logger)
+
+	return req, nil
+}
+
+// warnUnsupportedServiceKeys logs a warning for each key of the service which is not converted,
+// in the order they are sorted, as the loaded project does not tell which keys were set.
+func warnUnsupportedServiceKeys(composeYAML []byte, serviceName string, logger testcontainers.Logging) error {
+	dict, err := loader.ParseYAML(composeYAML)
+	if err != nil {
+		return fmt.Errorf("parse compose file: %w", err)
+	}
+
+	services, _ := dict["services"].(map[string]interface{})
+	service, _ := services[serviceName].(map[string]interface{})
+
+	keys := make([]string, 0, len(service))
+	for key := range service {
+		if !supportedServiceKeys[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		logger.Printf("⚠️ Unsupported key %q in service %q, ignoring it", key, serviceName)
+	}
+
+	return nil
+}
+
+// servicePorts converts the ports of a service into exposed ports.
+// Published ports are dropped, so random host ports are used instead.
+func servicePorts(service types.ServiceConfig, logger testcontainers.Logging) []string {
+	ports := make([]string, 0, len(service.Ports))
+	for _, port := range service.Ports {
+		if port.Published != "" {
+			logger.Printf("⚠️ Published port %q of service %q is ignored, a random host port is used instead", port.Published, service.Name)
+		}
+
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+
+		ports = append(ports, strconv.FormatUint(uint64(port.Target), 10)+"/"+protocol)
+	}
+
+	return ports
+}
+
+// serviceEnvironment converts the environment of a service, already merged with its env files
+// by the loader. Variables without a value are taken from the current environment, as compose
+// does, and dropped if they are not set there either.
+func serviceEnvironment(service types.ServiceConfig) map[string]string {
+	env := map[string]string{}
+	for key, value := range service.Environment {
+		if value != nil {
+			env[key] = *value
+		}
+	}
+
+	return env
+}
+
+// serviceVolumes converts the volumes of a service. Named volumes are added as volume mounts,
+// while host paths, already resolved by the loader, are bound using the host config.
+func serviceVolumes(service types.ServiceConfig, req *testcontainers.ContainerRequest, logger testcontainers.Logging) {
+	var binds []string
+	for _, volume := range service.Volumes {
+		switch {
+		case volume.Type == types.VolumeTypeBind:
+			bind := volume.Source + ":" + volume.Target
+			if volume.ReadOnly {
+				bind += ":ro"
+			}
+			binds = append(binds, bind)
+		case volume.Type == types.VolumeTypeVolume && volume.Source != "":
+			m := testcontainers.VolumeMount(volume.Source, testcontainers.ContainerMountTarget(volume.Target))
+			m.ReadOnly = volume.ReadOnly
+			req.Mounts = append(req.Mounts, m)
+		case volume.Type == types.VolumeTypeVolume:
+			logger.Printf("⚠️ Anonymous volume %q of service %q is not supported, ignoring it", volume.Target, service.Name)
+		default:
+			logger.Printf("⚠️ Volume %q of type %q of service %q is not supported, ignoring it", volume.Target, volume.Type, service.Name)
+		}
+	}
+
+	if len(binds) > 0 {
+		req.HostConfigModifier = func(hc *container.HostConfig) {
+			hc.Binds = append(hc.Binds, binds...)
+		}
+	}
+}
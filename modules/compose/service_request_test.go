@@ -0,0 +1,145 @@
+package compose
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// recordingLogger keeps the logged messages, so they can be asserted.
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, v...))
+}
+
+func TestContainerRequestFromService(t *testing.T) {
+	composeYAML := []byte(`
+services:
+  api:
+    image: nginx:alpine
+    restart: always
+    ports:
+      - "8080:80"
+      - 9090/udp
+      - target: 443
+        protocol: tcp
+    environment:
+      FOO: bar
+      ANSWER: 42
+    volumes:
+      - data:/var/lib/data
+      - ./config:/etc/nginx/conf.d:ro
+      - type: bind
+        source: ./static
+        target: /usr/share/nginx/html
+      - /var/cache/nginx
+    command: nginx -g "daemon off;"
+  db:
+    image: postgres:16
+`)
+
+	t.Run("service", func(t *testing.T) {
+		logger := &recordingLogger{}
+
+		req, err := ContainerRequestFromService(composeYAML, "api", WithLogger(logger))
+		require.NoError(t, err)
+
+		assert.Equal(t, "nginx:alpine", req.Image)
+		assert.Equal(t, []string{"80/tcp", "9090/udp", "443/tcp"}, req.ExposedPorts)
+		assert.Equal(t, map[string]string{"FOO": "bar", "ANSWER": "42"}, req.Env)
+		assert.Equal(t, []string{"nginx", "-g", "daemon off;"}, req.Cmd)
+
+		require.Len(t, req.Mounts, 1)
+		assert.Equal(t, testcontainers.ContainerMountTarget("/var/lib/data"), req.Mounts[0].Target)
+		assert.Equal(t, "data", req.Mounts[0].Source.(testcontainers.GenericVolumeMountSource).Name)
+
+		require.NotNil(t, req.HostConfigModifier)
+		hostConfig := &container.HostConfig{}
+		req.HostConfigModifier(hostConfig)
+		configDir, err := filepath.Abs("config")
+		require.NoError(t, err)
+		staticDir, err := filepath.Abs("static")
+		require.NoError(t, err)
+		assert.Equal(t, []string{configDir + ":/etc/nginx/conf.d:ro", staticDir + ":/usr/share/nginx/html"}, hostConfig.Binds)
+
+		assert.Equal(t, []string{
+			`⚠️ Unsupported key "restart" in service "api", ignoring it`,
+			`⚠️ Published port "8080" of service "api" is ignored, a random host port is used instead`,
+			`⚠️ Anonymous volume "/var/cache/nginx" of service "api" is not supported, ignoring it`,
+		}, logger.messages)
+	})
+
+	t.Run("environment list", func(t *testing.T) {
+		t.Setenv("FROM_HOST", "value")
+
+		req, err := ContainerRequestFromService([]byte(`
+services:
+  app:
+    image: alpine
+    environment:
+      - FOO=bar=baz
+      - FROM_HOST
+      - MISSING_FROM_HOST
+    command: ["echo", "hello world"]
+`), "app", WithLogger(&recordingLogger{}))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"FOO": "bar=baz", "FROM_HOST": "value"}, req.Env)
+		assert.Equal(t, []string{"echo", "hello world"}, req.Cmd)
+	})
+
+	t.Run("interpolation", func(t *testing.T) {
+		t.Setenv("NGINX_TAG", "1.27-alpine")
+
+		req, err := ContainerRequestFromService([]byte(`
+services:
+  app:
+    image: nginx:${NGINX_TAG}
+    environment:
+      TAG: ${NGINX_TAG:-latest}
+`), "app", WithLogger(&recordingLogger{}))
+		require.NoError(t, err)
+
+		assert.Equal(t, "nginx:1.27-alpine", req.Image)
+		assert.Equal(t, map[string]string{"TAG": "1.27-alpine"}, req.Env)
+	})
+
+	t.Run("windows host path", func(t *testing.T) {
+		req, err := ContainerRequestFromService([]byte(`
+services:
+  app:
+    image: alpine
+    volumes:
+      - C:\data:/data
+`), "app", WithLogger(&recordingLogger{}))
+		require.NoError(t, err)
+
+		require.NotNil(t, req.HostConfigModifier)
+		hostConfig := &container.HostConfig{}
+		req.HostConfigModifier(hostConfig)
+		assert.Equal(t, []string{`C:\data:/data`}, hostConfig.Binds)
+	})
+
+	t.Run("unknown service", func(t *testing.T) {
+		_, err := ContainerRequestFromService(composeYAML, "cache")
+		require.EqualError(t, err, `service "cache" not found`)
+	})
+
+	t.Run("no image", func(t *testing.T) {
+		_, err := ContainerRequestFromService([]byte(`
+services:
+  app:
+    build: .
+`), "app", WithLogger(&recordingLogger{}))
+		require.EqualError(t, err, `service "app" has no image`)
+	})
+}
@@ -2,6 +2,7 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
@@ -10,10 +11,20 @@ import (
 	"golang.org/x/mod/semver"
 
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
 const publicPort = nat.Port("9093/tcp")
+const (
+	defaultKafkaImage = "confluentinc/confluent-local:7.5.0"
+
+	// the images of the Zookeeper mode, as the confluent-local image only supports KRaft
+	defaultZookeeperKafkaImage = "confluentinc/cp-kafka:7.5.0"
+	defaultZookeeperImage      = "confluentinc/cp-zookeeper:7.5.0"
+	zookeeperAlias             = "zookeeper"
+	zookeeperPort              = "2181"
+)
 const (
 	starterScript = "/usr/sbin/testcontainers_start.sh"
 
@@ -26,6 +37,16 @@ sed -i '/KAFKA_ZOOKEEPER_CONNECT/d' /etc/confluent/docker/configure
 echo 'kafka-storage format --ignore-formatted -t "$(kafka-storage random-uuid)" -c /etc/kafka/kafka.properties' >> /etc/confluent/docker/configure
 echo '' > /etc/confluent/docker/ensure
 /etc/confluent/docker/configure
+/etc/confluent/docker/launch`
+	// }
+
+	// zookeeperStarterScript {
+	zookeeperStarterScriptContent = `#!/bin/bash
+source /etc/confluent/docker/bash-config
+export KAFKA_ADVERTISED_LISTENERS=PLAINTEXT://%s:%d,BROKER://%s:9092
+echo Starting Kafka Zookeeper mode
+echo '' > /etc/confluent/docker/ensure
+/etc/confluent/docker/configure
 /etc/confluent/docker/launch`
 	// }
 )
@@ -34,12 +55,33 @@ echo '' > /etc/confluent/docker/ensure
 type KafkaContainer struct {
 	testcontainers.Container
 	ClusterID string
+	zookeeper testcontainers.Container      // the Zookeeper container, in Zookeeper mode
+	network   *testcontainers.DockerNetwork // the network of the Kafka and Zookeeper containers, in Zookeeper mode
+}
+
+// Terminate terminates the Kafka container and, in Zookeeper mode, the Zookeeper container and their network.
+func (kc *KafkaContainer) Terminate(ctx context.Context) error {
+	err := kc.Container.Terminate(ctx)
+
+	if kc.zookeeper != nil {
+		err = errors.Join(err, kc.zookeeper.Terminate(ctx))
+	}
+
+	if kc.network != nil {
+		err = errors.Join(err, kc.network.Remove(ctx))
+	}
+
+	return err
 }
 
 // RunContainer creates an instance of the Kafka container type
 func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (*KafkaContainer, error) {
+	// the starter script and the log of a ready broker depend on the mode
+	scriptContentTpl := starterScriptContent
+	readyLog := ".*Transitioning from RECOVERY to RUNNING.*"
+
 	req := testcontainers.ContainerRequest{
-		Image:        "confluentinc/confluent-local:7.5.0",
+		Image:        defaultKafkaImage,
 		ExposedPorts: []string{string(publicPort)},
 		Env: map[string]string{
 			// envVars {
@@ -84,13 +126,13 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 							return err
 						}
 
-						scriptContent := fmt.Sprintf(starterScriptContent, host, port.Int(), hostname)
+						scriptContent := fmt.Sprintf(scriptContentTpl, host, port.Int(), hostname)
 
 						return c.CopyToContainer(ctx, []byte(scriptContent), starterScript, 0o755)
 					},
 					// 2. wait for the Kafka server to be ready
 					func(ctx context.Context, c testcontainers.Container) error {
-						return wait.ForLog(".*Transitioning from RECOVERY to RUNNING.*").AsRegexp().WaitUntilReady(ctx, c)
+						return wait.ForLog(readyLog).AsRegexp().WaitUntilReady(ctx, c)
 					},
 				},
 			},
@@ -102,12 +144,31 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 		Started:          true,
 	}
 
+	settings := options{}
 	for _, opt := range opts {
+		if apply, ok := opt.(Option); ok {
+			apply(&settings)
+		}
 		if err := opt.Customize(&genericContainerReq); err != nil {
 			return nil, err
 		}
 	}
 
+	if settings.kraft && settings.zookeeper {
+		return nil, errors.New("WithKRaft and WithZookeeper cannot be used together")
+	}
+
+	if settings.zookeeper {
+		if err := configureZookeeperMode(&genericContainerReq); err != nil {
+			return nil, err
+		}
+
+		scriptContentTpl = zookeeperStarterScriptContent
+		readyLog = `.*\[KafkaServer id=\d+\] started.*`
+
+		return runWithZookeeper(ctx, genericContainerReq, settings.zookeeperImage)
+	}
+
 	err := validateKRaftVersion(genericContainerReq.Image)
 	if err != nil {
 		return nil, err
@@ -125,6 +186,89 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 	return &KafkaContainer{Container: container, ClusterID: clusterID}, nil
 }
 
+// runWithZookeeper starts a Zookeeper container, with the given image, and then the Kafka container,
+// both attached to a new network, which are cleaned up if the Kafka container cannot be started.
+func runWithZookeeper(ctx context.Context, req testcontainers.GenericContainerRequest, zookeeperImage string) (*KafkaContainer, error) {
+	if zookeeperImage == "" {
+		zookeeperImage = defaultZookeeperImage
+	}
+
+	nw, err := network.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("create network: %w", err)
+	}
+
+	zookeeperReq := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: zookeeperImage,
+			Env: map[string]string{
+				"ZOOKEEPER_CLIENT_PORT": zookeeperPort,
+				"ZOOKEEPER_TICK_TIME":   "2000",
+			},
+			// the port is only reachable in the network
+			WaitingFor: wait.ForListeningPort(zookeeperPort + "/tcp").WithInternalProbe(),
+		},
+		Started: true,
+	}
+
+	kc := &KafkaContainer{network: nw}
+
+	// the error is always nil, as the network already exists
+	_ = network.WithNetwork([]string{zookeeperAlias}, nw)(&zookeeperReq)
+	_ = network.WithNetwork([]string{"kafka"}, nw)(&req)
+
+	kc.zookeeper, err = testcontainers.GenericContainer(ctx, zookeeperReq)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("start zookeeper: %w", err), kc.terminateOnError(ctx))
+	}
+
+	kc.Container, err = testcontainers.GenericContainer(ctx, req)
+	if err != nil {
+		return nil, errors.Join(err, kc.terminateOnError(ctx))
+	}
+
+	return kc, nil
+}
+
+// terminateOnError terminates the containers which could be created, and removes the network.
+func (kc *KafkaContainer) terminateOnError(ctx context.Context) error {
+	var err error
+
+	for _, c := range []testcontainers.Container{kc.Container, kc.zookeeper} {
+		if c != nil {
+			err = errors.Join(err, c.Terminate(ctx))
+		}
+	}
+
+	return errors.Join(err, kc.network.Remove(ctx))
+}
+
+// configureZookeeperMode configures the broker to use the Zookeeper container, instead of acting as its
+// own KRaft controller. The default image is replaced by the cp-kafka one, as confluent-local only supports KRaft.
+func configureZookeeperMode(req *testcontainers.GenericContainerRequest) error {
+	if req.Image == defaultKafkaImage {
+		req.Image = defaultZookeeperKafkaImage
+	} else if strings.Contains(req.Image, "confluentinc/confluent-local") {
+		return fmt.Errorf("image=%s. The confluentinc/confluent-local image only supports KRaft mode, use confluentinc/cp-kafka instead", req.Image)
+	}
+
+	for _, key := range []string{
+		"KAFKA_NODE_ID",
+		"KAFKA_PROCESS_ROLES",
+		"KAFKA_CONTROLLER_LISTENER_NAMES",
+		"KAFKA_CONTROLLER_QUORUM_VOTERS",
+		"KAFKA_REST_BOOTSTRAP_SERVERS",
+	} {
+		delete(req.Env, key)
+	}
+
+	req.Env["KAFKA_LISTENERS"] = strings.Replace(req.Env["KAFKA_LISTENERS"], ",CONTROLLER://0.0.0.0:9094", "", 1)
+	req.Env["KAFKA_LISTENER_SECURITY_PROTOCOL_MAP"] = strings.Replace(req.Env["KAFKA_LISTENER_SECURITY_PROTOCOL_MAP"], ",CONTROLLER:PLAINTEXT", "", 1)
+	req.Env["KAFKA_ZOOKEEPER_CONNECT"] = zookeeperAlias + ":" + zookeeperPort
+
+	return nil
+}
+
 func WithClusterID(clusterID string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		req.Env["CLUSTER_ID"] = clusterID
@@ -97,6 +97,65 @@ func TestKafka_invalidVersion(t *testing.T) {
 	}
 }
 
+func TestKafka_zookeeper(t *testing.T) {
+	ctx := context.Background()
+
+	kafkaContainer, err := kafka.RunContainer(ctx, kafka.WithZookeeper("confluentinc/cp-zookeeper:7.5.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clean up the containers and their network after the test is complete
+	t.Cleanup(func() {
+		if err := kafkaContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	assertAdvertisedListeners(t, kafkaContainer)
+
+	brokers, err := kafkaContainer.Brokers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := sarama.NewConfig()
+	config.Producer.Return.Successes = true
+
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer producer.Close()
+
+	// the topic is created automatically by the broker
+	if _, _, err := producer.SendMessage(&sarama.ProducerMessage{
+		Topic: "zookeeper-topic",
+		Key:   sarama.StringEncoder("key"),
+		Value: sarama.StringEncoder("value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKafka_invalidMode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("kraft-and-zookeeper", func(t *testing.T) {
+		_, err := kafka.RunContainer(ctx, kafka.WithKRaft(), kafka.WithZookeeper(""))
+		if err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+			t.Fatalf("expected an error, got %v", err)
+		}
+	})
+
+	t.Run("zookeeper-with-confluent-local", func(t *testing.T) {
+		_, err := kafka.RunContainer(ctx, kafka.WithZookeeper(""), testcontainers.WithImage("confluentinc/confluent-local:7.6.0"))
+		if err == nil || !strings.Contains(err.Error(), "only supports KRaft mode") {
+			t.Fatalf("expected an error, got %v", err)
+		}
+	})
+}
+
 // assertAdvertisedListeners checks that the advertised listeners are set correctly:
 // - The BROKER:// protocol is using the hostname of the Kafka container
 func assertAdvertisedListeners(t *testing.T, container testcontainers.Container) {
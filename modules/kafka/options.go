@@ -0,0 +1,46 @@
+package kafka
+
+import (
+	"github.com/testcontainers/testcontainers-go"
+)
+
+type options struct {
+	// kraft is true if the KRaft mode was explicitly requested
+	kraft bool
+	// zookeeper is true if the Zookeeper mode was requested, with the image of the Zookeeper container
+	zookeeper      bool
+	zookeeperImage string
+}
+
+// Compiler check to ensure that Option implements the testcontainers.ContainerCustomizer interface.
+var _ testcontainers.ContainerCustomizer = (*Option)(nil)
+
+// Option is an option for the Kafka container.
+type Option func(*options)
+
+// Customize is a NOOP. It's defined to satisfy the testcontainers.ContainerCustomizer interface.
+func (o Option) Customize(*testcontainers.GenericContainerRequest) error {
+	// NOOP to satisfy interface.
+	return nil
+}
+
+// WithKRaft runs the broker in KRaft mode, acting as its own controller, which is the default.
+// It cannot be used together with WithZookeeper.
+func WithKRaft() Option {
+	return func(o *options) {
+		o.kraft = true
+	}
+}
+
+// WithZookeeper runs the broker in the classic mode, backed by a Zookeeper container started
+// with the given image, e.g. "confluentinc/cp-zookeeper:7.5.0", which is the default if empty.
+// The two containers are attached to a new network, which is removed when the Kafka container
+// is terminated, together with the Zookeeper container. As the confluentinc/confluent-local image
+// only supports KRaft, the confluentinc/cp-kafka image of the same version is used by default.
+// It cannot be used together with WithKRaft.
+func WithZookeeper(image string) Option {
+	return func(o *options) {
+		o.zookeeper = true
+		o.zookeeperImage = image
+	}
+}
@@ -3,6 +3,8 @@ package mongodb
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strings"
 
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
@@ -14,8 +16,9 @@ const defaultImage = "mongo:6"
 // MongoDBContainer represents the MongoDB container type used in the module
 type MongoDBContainer struct {
 	testcontainers.Container
-	username string
-	password string
+	username   string
+	password   string
+	replicaSet string
 }
 
 // RunContainer creates an instance of the MongoDB container type
@@ -51,10 +54,27 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 		return nil, err
 	}
 
+	c := &MongoDBContainer{Container: container, replicaSet: replicaSetName(genericContainerReq.Cmd)}
 	if username != "" && password != "" {
-		return &MongoDBContainer{Container: container, username: username, password: password}, nil
+		c.username = username
+		c.password = password
 	}
-	return &MongoDBContainer{Container: container}, nil
+
+	return c, nil
+}
+
+// replicaSetName returns the name of the replica set passed to mongod with the --replSet flag, if any.
+func replicaSetName(cmd []string) string {
+	for i, arg := range cmd {
+		if arg == "--replSet" && i+1 < len(cmd) {
+			return cmd[i+1]
+		}
+		if name, ok := strings.CutPrefix(arg, "--replSet="); ok {
+			return name
+		}
+	}
+
+	return ""
 }
 
 // WithUsername sets the initial username to be created when the container starts
@@ -79,21 +99,29 @@ func WithPassword(password string) testcontainers.CustomizeRequestOption {
 	}
 }
 
-// WithReplicaSet configures the container to run a single-node MongoDB replica set named "rs".
-// It will wait until the replica set is ready.
+// WithReplicaSet configures the container to run a single-node MongoDB replica set with the given name,
+// e.g. to test transactions, which require a replica set. Once the container is ready, the replica set
+// is initiated, and it waits until the node is elected as primary. The node advertises itself as
+// localhost:27017, as mongod must recognize itself in the replica set config, so the connection
+// string returned by ConnectionString uses a direct connection to the mapped port.
 func WithReplicaSet(replSetName string) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		req.Cmd = append(req.Cmd, "--replSet", replSetName)
 		req.LifecycleHooks = append(req.LifecycleHooks, testcontainers.ContainerLifecycleHooks{
-			PostStarts: []testcontainers.ContainerHook{
+			PostReadies: []testcontainers.ContainerHook{
 				func(ctx context.Context, c testcontainers.Container) error {
-					ip, err := c.ContainerIP(ctx)
-					if err != nil {
-						return err
+					cmd := eval("rs.initiate({ _id: '%s', members: [ { _id: 0, host: 'localhost:27017' } ] })", replSetName)
+					if err := wait.ForExec(cmd).WaitUntilReady(ctx, c); err != nil {
+						return fmt.Errorf("initiate replica set %s: %w", replSetName, err)
 					}
 
-					cmd := eval("rs.initiate({ _id: '%s', members: [ { _id: 0, host: '%s:27017' } ] })", replSetName, ip)
-					return wait.ForExec(cmd).WaitUntilReady(ctx, c)
+					// the node is elected as primary shortly after the replica set is initiated
+					cmd = eval("quit(db.runCommand({ isMaster: 1 }).ismaster ? 0 : 1)")
+					if err := wait.ForExec(cmd).WaitUntilReady(ctx, c); err != nil {
+						return fmt.Errorf("wait for the primary of replica set %s: %w", replSetName, err)
+					}
+
+					return nil
 				},
 			},
 		})
@@ -104,6 +132,8 @@ func WithReplicaSet(replSetName string) testcontainers.CustomizeRequestOption {
 
 // ConnectionString returns the connection string for the MongoDB container.
 // If you provide a username and a password, the connection string will also include them.
+// If the container runs a replica set, the connection string includes its name, and a direct
+// connection is requested, as the replica set advertises the port of the container, not the mapped one.
 func (c *MongoDBContainer) ConnectionString(ctx context.Context) (string, error) {
 	host, err := c.Host(ctx)
 	if err != nil {
@@ -113,10 +143,22 @@ func (c *MongoDBContainer) ConnectionString(ctx context.Context) (string, error)
 	if err != nil {
 		return "", err
 	}
+
+	var connStr string
 	if c.username != "" && c.password != "" {
-		return fmt.Sprintf("mongodb://%s:%s@%s:%s", c.username, c.password, host, port.Port()), nil
+		connStr = fmt.Sprintf("mongodb://%s:%s@%s:%s", c.username, c.password, host, port.Port())
+	} else {
+		connStr, err = c.Endpoint(ctx, "mongodb")
+		if err != nil {
+			return "", err
+		}
 	}
-	return c.Endpoint(ctx, "mongodb")
+
+	if c.replicaSet != "" {
+		connStr += "/?replicaSet=" + url.QueryEscape(c.replicaSet) + "&directConnection=true"
+	}
+
+	return connStr, nil
 }
 
 // eval builds an mongosh|mongo eval command.
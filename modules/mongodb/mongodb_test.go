@@ -3,8 +3,10 @@ package mongodb_test
 import (
 	"context"
 	"log"
+	"strings"
 	"testing"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
@@ -91,3 +93,53 @@ func TestMongoDB(t *testing.T) {
 		})
 	}
 }
+
+func TestMongoDB_replicaSetTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	mongodbContainer, err := mongodb.RunContainer(ctx, testcontainers.WithImage("mongo:6"), mongodb.WithReplicaSet("rs0"))
+	if err != nil {
+		t.Fatalf("failed to start container: %s", err)
+	}
+
+	defer func() {
+		if err := mongodbContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	}()
+
+	endpoint, err := mongodbContainer.ConnectionString(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection string: %s", err)
+	}
+
+	if !strings.HasSuffix(endpoint, "/?replicaSet=rs0&directConnection=true") {
+		t.Fatalf("expected the replica set in the connection string, got %s", endpoint)
+	}
+
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI(endpoint))
+	if err != nil {
+		t.Fatalf("failed to connect to MongoDB: %s", err)
+	}
+	defer mongoClient.Disconnect(ctx)
+
+	// transactions are only supported by replica sets
+	coll := mongoClient.Database("test").Collection("accounts")
+	err = mongoClient.UseSession(ctx, func(sc mongo.SessionContext) error {
+		_, err := sc.WithTransaction(sc, func(sc mongo.SessionContext) (any, error) {
+			return coll.InsertOne(sc, bson.M{"name": "alice"})
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("failed to run the transaction: %s", err)
+	}
+
+	n, err := coll.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		t.Fatalf("failed to count the documents: %s", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 document, got %d", n)
+	}
+}
@@ -25,12 +25,29 @@ const defaultImage = "mysql:8.0.36"
 // MySQLContainer represents the MySQL container type used in the module
 type MySQLContainer struct {
 	testcontainers.Container
-	username string
-	password string
-	database string
+	username     string
+	password     string
+	rootPassword string
+	database     string
 }
 
+// WithDefaultCredentials reverts the username, password and database name to the defaults of the module,
+// "test" for all of them, discarding the ones set by previous options, e.g. WithUsername.
 func WithDefaultCredentials() testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.Env["MYSQL_USER"] = defaultUser
+		req.Env["MYSQL_PASSWORD"] = defaultPassword
+		req.Env["MYSQL_DATABASE"] = defaultDatabaseName
+		delete(req.Env, "MYSQL_ROOT_PASSWORD")
+		delete(req.Env, "MYSQL_ALLOW_EMPTY_PASSWORD")
+
+		return nil
+	}
+}
+
+// withRootCredentials sets the password of the root user to the password of the user,
+// allowing an empty password if the user is root. It's applied after all the other options.
+func withRootCredentials() testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
 		username := req.Env["MYSQL_USER"]
 		password := req.Env["MYSQL_PASSWORD"]
@@ -66,7 +83,7 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 		Started:          true,
 	}
 
-	opts = append(opts, WithDefaultCredentials())
+	opts = append(opts, withRootCredentials())
 
 	for _, opt := range opts {
 		if err := opt.Customize(&genericContainerReq); err != nil {
@@ -90,8 +107,9 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 	}
 
 	database := req.Env["MYSQL_DATABASE"]
+	rootPassword := req.Env["MYSQL_ROOT_PASSWORD"]
 
-	return &MySQLContainer{container, username, password, database}, nil
+	return &MySQLContainer{container, username, password, rootPassword, database}, nil
 }
 
 // MustConnectionString panics if the address cannot be determined.
@@ -104,6 +122,17 @@ func (c *MySQLContainer) MustConnectionString(ctx context.Context, args ...strin
 }
 
 func (c *MySQLContainer) ConnectionString(ctx context.Context, args ...string) (string, error) {
+	return c.connectionString(ctx, c.username, c.password, args...)
+}
+
+// ConnectionStringRoot returns the connection string to connect to the database of the MySQL container
+// as the root user, using the root password, e.g. to create additional schemas. It's possible to pass
+// extra parameters to the connection string, e.g. "parseTime=true", in a variadic way.
+func (c *MySQLContainer) ConnectionStringRoot(ctx context.Context, args ...string) (string, error) {
+	return c.connectionString(ctx, rootUser, c.rootPassword, args...)
+}
+
+func (c *MySQLContainer) connectionString(ctx context.Context, username string, password string, args ...string) (string, error) {
 	containerPort, err := c.MappedPort(ctx, "3306/tcp")
 	if err != nil {
 		return "", err
@@ -122,7 +151,7 @@ func (c *MySQLContainer) ConnectionString(ctx context.Context, args ...string) (
 		extraArgs = "?" + extraArgs
 	}
 
-	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s%s", c.username, c.password, host, containerPort.Port(), c.database, extraArgs)
+	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s%s", username, password, host, containerPort.Port(), c.database, extraArgs)
 	return connectionString, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	// Import mysql into the scope of this package (required)
@@ -153,3 +154,85 @@ func TestMySQLWithScripts(t *testing.T) {
 		t.Fatal("The expected record was not found in the database.")
 	}
 }
+
+func TestMySQLConnectionStringRoot(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := mysql.RunContainer(ctx,
+		mysql.WithUsername("foo"),
+		mysql.WithPassword("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clean up the container after the test is complete
+	t.Cleanup(func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// perform assertions
+	// connectionStringRoot {
+	connectionString, err := container.ConnectionStringRoot(ctx, "parseTime=true")
+	// }
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(connectionString, "root:bar@tcp(") {
+		t.Errorf("expected the connection string to use the root user, got %s", connectionString)
+	}
+	if !strings.HasSuffix(connectionString, "/test?parseTime=true") {
+		t.Errorf("expected the connection string to include the parameters, got %s", connectionString)
+	}
+
+	db, err := sql.Open("mysql", connectionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// only the root user is allowed to create additional schemas
+	if _, err = db.Exec("CREATE DATABASE another_schema"); err != nil {
+		t.Errorf("error creating schema: %+v\n", err)
+	}
+}
+
+func TestMySQLWithDefaultCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := mysql.RunContainer(ctx,
+		mysql.WithDatabase("foo"),
+		mysql.WithUsername("root"),
+		mysql.WithPassword(""),
+		mysql.WithDefaultCredentials())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clean up the container after the test is complete
+	t.Cleanup(func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	// perform assertions
+	connectionString, err := container.ConnectionString(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(connectionString, "test:test@tcp(") || !strings.HasSuffix(connectionString, "/test") {
+		t.Errorf("expected the default credentials, got %s", connectionString)
+	}
+
+	db, err := sql.Open("mysql", connectionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err = db.Ping(); err != nil {
+		t.Errorf("error pinging db: %+v\n", err)
+	}
+}
@@ -28,7 +28,7 @@ func withGpu() testcontainers.CustomizeRequestOption {
 		return noopCustomizeRequestOption
 	}
 
-	return testcontainers.WithHostConfigModifier(func(hostConfig *container.HostConfig) {
+	return testcontainers.WithHostConfigModifierAt(testcontainers.ModifierPriorityModule, func(hostConfig *container.HostConfig) {
 		hostConfig.DeviceRequests = []container.DeviceRequest{
 			{
 				Count:        -1,
@@ -0,0 +1,9 @@
+#!/bin/sh
+set -e
+
+# The key material is copied as root, but postgres refuses to start if its private key is not owned
+# by the postgres user, or is accessible by others, so it's handed over before running the server.
+chown postgres:postgres /etc/postgresql/ssl/ca.crt /etc/postgresql/ssl/server.crt /etc/postgresql/ssl/server.key
+chmod 600 /etc/postgresql/ssl/server.key
+
+exec docker-entrypoint.sh "$@"
@@ -1,7 +1,9 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
+	_ "embed"
 	"fmt"
 	"io"
 	"net"
@@ -11,11 +13,19 @@ import (
 	"github.com/testcontainers/testcontainers-go"
 )
 
+//go:embed mounts/entrypoint-ssl.sh
+var sslEntrypoint []byte
+
 const (
 	defaultUser          = "postgres"
 	defaultPassword      = "postgres"
 	defaultPostgresImage = "docker.io/postgres:16-alpine"
 	defaultSnapshotName  = "migrated_template"
+
+	sslEntrypointFile = "/usr/local/bin/docker-entrypoint-ssl.sh"
+	sslCAFile         = "/etc/postgresql/ssl/ca.crt"
+	sslCertFile       = "/etc/postgresql/ssl/server.crt"
+	sslKeyFile        = "/etc/postgresql/ssl/server.key"
 )
 
 // PostgresContainer represents the postgres container type used in the module
@@ -56,6 +66,20 @@ func (c *PostgresContainer) ConnectionString(ctx context.Context, args ...string
 	return connStr, nil
 }
 
+// ConnectionStringWithSSL returns the connection string for the postgres container, like ConnectionString,
+// requiring TLS with "sslmode=require", unless another sslmode is passed in the extra arguments,
+// e.g. "sslmode=verify-full" together with "sslrootcert=<path to the CA certificate>".
+// The container must be started with WithSSL.
+func (c *PostgresContainer) ConnectionStringWithSSL(ctx context.Context, args ...string) (string, error) {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "sslmode=") {
+			return c.ConnectionString(ctx, args...)
+		}
+	}
+
+	return c.ConnectionString(ctx, append(args, "sslmode=require")...)
+}
+
 // WithConfigFile sets the config file to be used for the postgres container
 // It will also set the "config_file" parameter to the path of the config file
 // as a command line argument to the container
@@ -74,6 +98,50 @@ func WithConfigFile(cfg string) testcontainers.CustomizeRequestOption {
 	}
 }
 
+// WithSSL enables TLS in the postgres container, copying the certificate and the private key of the server,
+// and the certificate of the CA verifying the certificates of the clients, into the container,
+// and setting "ssl=on" and the paths of the files as command line arguments to the container,
+// which take precedence over the config file. The private key is owned by the postgres user,
+// with 0600 permissions, as postgres refuses to start otherwise, so the container must run as root.
+// Clients can then connect with ConnectionStringWithSSL.
+func WithSSL(certFile, keyFile, caFile string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		req.Files = append(req.Files,
+			testcontainers.ContainerFile{
+				HostFilePath:      caFile,
+				ContainerFilePath: sslCAFile,
+				FileMode:          0o600,
+			},
+			testcontainers.ContainerFile{
+				HostFilePath:      certFile,
+				ContainerFilePath: sslCertFile,
+				FileMode:          0o600,
+			},
+			testcontainers.ContainerFile{
+				HostFilePath:      keyFile,
+				ContainerFilePath: sslKeyFile,
+				FileMode:          0o600,
+			},
+			testcontainers.ContainerFile{
+				Reader:            bytes.NewReader(sslEntrypoint),
+				ContainerFilePath: sslEntrypointFile,
+				FileMode:          0o755,
+			},
+		)
+
+		// the entrypoint hands the key material over to the postgres user, then runs the one of the image
+		req.Entrypoint = []string{sslEntrypointFile}
+		req.Cmd = append(req.Cmd,
+			"-c", "ssl=on",
+			"-c", "ssl_ca_file="+sslCAFile,
+			"-c", "ssl_cert_file="+sslCertFile,
+			"-c", "ssl_key_file="+sslKeyFile,
+		)
+
+		return nil
+	}
+}
+
 // WithDatabase sets the initial database to be created when the container starts
 // It can be used to define a different name for the default database that is created when the image is first started.
 // If it is not specified, then the value of WithUser will be used.
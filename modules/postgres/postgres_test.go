@@ -2,10 +2,20 @@ package postgres_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"database/sql"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
+	"net"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -400,3 +410,103 @@ func TestSnapshotWithOverrides(t *testing.T) {
 		}
 	})
 }
+
+func TestWithSSL(t *testing.T) {
+	ctx := context.Background()
+
+	caFile, certFile, keyFile := createSSLCerts(t)
+
+	container, err := postgres.RunContainer(ctx,
+		postgres.WithSSL(certFile, keyFile, caFile),
+		postgres.WithDatabase(dbname),
+		postgres.WithUsername(user),
+		postgres.WithPassword(password),
+		testcontainers.WithWaitStrategy(wait.ForLog("database system is ready to accept connections").WithOccurrence(2).WithStartupTimeout(15*time.Second)),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	t.Run("require", func(t *testing.T) {
+		connStr, err := container.ConnectionStringWithSSL(ctx)
+		require.NoError(t, err)
+		require.Contains(t, connStr, "sslmode=require")
+
+		db, err := sql.Open("postgres", connStr)
+		require.NoError(t, err)
+		defer db.Close()
+
+		var ssl bool
+		require.NoError(t, db.QueryRow("SELECT ssl FROM pg_stat_ssl WHERE pid = pg_backend_pid()").Scan(&ssl))
+		require.True(t, ssl)
+	})
+
+	t.Run("verify-full", func(t *testing.T) {
+		// the certificate of the server is issued for localhost
+		connStr, err := container.ConnectionStringWithSSL(ctx, "sslmode=verify-full", "sslrootcert="+caFile)
+		require.NoError(t, err)
+		require.NotContains(t, connStr, "sslmode=require")
+
+		conn, err := pgx.Connect(ctx, strings.Replace(connStr, "@127.0.0.1:", "@localhost:", 1))
+		require.NoError(t, err)
+		defer conn.Close(ctx)
+
+		require.NoError(t, conn.Ping(ctx))
+	})
+}
+
+// createSSLCerts creates a CA, and a certificate and private key for the server issued by the CA,
+// in a temporary directory, returning the paths of the PEM files.
+func createSSLCerts(t *testing.T) (caFile, certFile, keyFile string) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "testcontainers-go CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	serverKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serverTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caTemplate, &serverKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	serverKeyDER, err := x509.MarshalPKCS8PrivateKey(serverKey)
+	require.NoError(t, err)
+
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+		return path
+	}
+
+	return writePEM("ca.crt", "CERTIFICATE", caDER),
+		writePEM("server.crt", "CERTIFICATE", serverDER),
+		writePEM("server.key", "PRIVATE KEY", serverKeyDER)
+}
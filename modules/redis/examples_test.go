@@ -16,7 +16,6 @@ func ExampleRunContainer() {
 
 	redisContainer, err := redis.RunContainer(ctx,
 		testcontainers.WithImage("docker.io/redis:7"),
-		redis.WithSnapshotting(10, 1),
 		redis.WithLogLevel(redis.LogLevelVerbose),
 		redis.WithConfigFile(filepath.Join("testdata", "redis7.conf")),
 	)
@@ -42,3 +41,35 @@ func ExampleRunContainer() {
 	// Output:
 	// true
 }
+
+func ExampleRunContainer_withSnapshotting() {
+	// redisContainerWithSnapshotting {
+	ctx := context.Background()
+
+	redisContainer, err := redis.RunContainer(ctx,
+		testcontainers.WithImage("docker.io/redis:7"),
+		redis.WithSnapshotting(10, 1),
+		redis.WithLogLevel(redis.LogLevelVerbose),
+	)
+	if err != nil {
+		log.Fatalf("failed to start container: %s", err)
+	}
+
+	// Clean up the container
+	defer func() {
+		if err := redisContainer.Terminate(ctx); err != nil {
+			log.Fatalf("failed to terminate container: %s", err)
+		}
+	}()
+	// }
+
+	state, err := redisContainer.State(ctx)
+	if err != nil {
+		log.Fatalf("failed to get container state: %s", err) // nolint:gocritic
+	}
+
+	fmt.Println(state.Running)
+
+	// Output:
+	// true
+}
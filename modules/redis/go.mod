@@ -7,7 +7,6 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/stretchr/testify v1.9.0
 	github.com/testcontainers/testcontainers-go v0.31.0
-
 )
 
 replace github.com/testcontainers/testcontainers-go => ../..
@@ -1,6 +1,7 @@
 package redis
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -139,3 +140,115 @@ func TestWithSnapshotting(t *testing.T) {
 		})
 	}
 }
+
+func TestWithAOF(t *testing.T) {
+	tests := []struct {
+		name         string
+		cmds         []string
+		appendfsync  string
+		expectedCmds []string
+		wantErr      bool
+	}{
+		{
+			name:         "no existing command",
+			cmds:         []string{},
+			appendfsync:  "everysec",
+			expectedCmds: []string{redisServerProcess, "--appendonly", "yes", "--appendfsync", "everysec"},
+		},
+		{
+			name:         "existing redis-server command as first argument",
+			cmds:         []string{redisServerProcess, "a", "b", "c"},
+			appendfsync:  "always",
+			expectedCmds: []string{redisServerProcess, "a", "b", "c", "--appendonly", "yes", "--appendfsync", "always"},
+		},
+		{
+			name:         "non existing redis-server command",
+			cmds:         []string{"a", "b", "c"},
+			appendfsync:  "no",
+			expectedCmds: []string{redisServerProcess, "a", "b", "c", "--appendonly", "yes", "--appendfsync", "no"},
+		},
+		{
+			name:         "invalid appendfsync",
+			cmds:         []string{},
+			appendfsync:  "sometimes",
+			expectedCmds: []string{},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &testcontainers.GenericContainerRequest{
+				ContainerRequest: testcontainers.ContainerRequest{
+					Cmd: tt.cmds,
+				},
+			}
+
+			err := WithAOF(tt.appendfsync)(req)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, tt.expectedCmds, req.Cmd)
+		})
+	}
+}
+
+func TestConflictingOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []testcontainers.ContainerCustomizer
+	}{
+		{
+			name: "config file and snapshotting",
+			opts: []testcontainers.ContainerCustomizer{WithConfigFile("redis6.conf"), WithLogLevel(LogLevelDebug), WithSnapshotting(10, 1)},
+		},
+		{
+			name: "snapshotting and config file",
+			opts: []testcontainers.ContainerCustomizer{WithSnapshotting(10, 1), WithConfigFile("redis6.conf")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the error is returned before creating the container
+			_, err := RunContainer(context.Background(), tt.opts...)
+			require.ErrorIs(t, err, testcontainers.ErrConflictingOptions)
+		})
+	}
+
+	t.Run("error message", func(t *testing.T) {
+		_, err := RunContainer(context.Background(), WithConfigFile("redis6.conf"), WithSnapshotting(10, 1))
+		require.EqualError(t, err, "conflicting options: redis.WithSnapshotting cannot be combined with redis.WithConfigFile")
+	})
+}
+
+func TestRepeatedOptions(t *testing.T) {
+	// the options can be repeated, the last one winning, as the last flag of the command line wins
+	req := &testcontainers.GenericContainerRequest{}
+
+	opts := []testcontainers.CustomizeRequestOption{
+		WithConfigFile("redis6.conf"),
+		WithLogLevel(LogLevelDebug),
+		WithAOF("always"),
+		WithConfigFile("redis7.conf"),
+		WithLogLevel(LogLevelNotice),
+		WithAOF("everysec"),
+	}
+	for _, opt := range opts {
+		require.NoError(t, opt(req))
+	}
+
+	require.Equal(t, []string{
+		redisServerProcess, "/usr/local/redis.conf",
+		"--loglevel", "debug",
+		"--appendonly", "yes", "--appendfsync", "always",
+		"--loglevel", "notice",
+		"--appendonly", "yes", "--appendfsync", "everysec",
+	}, req.Cmd)
+
+	require.Len(t, req.Files, 1)
+	require.Equal(t, "redis7.conf", req.Files[0].HostFilePath)
+}
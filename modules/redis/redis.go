@@ -31,6 +31,9 @@ type RedisContainer struct {
 	testcontainers.Container
 }
 
+// ConnectionString returns the connection string of the Redis container. The container details it's
+// resolved from are cached by the container, until it's stopped, started or restarted, so calling it
+// repeatedly does not inspect the container each time.
 func (c *RedisContainer) ConnectionString(ctx context.Context) (string, error) {
 	mappedPort, err := c.MappedPort(ctx, "6379/tcp")
 	if err != nil {
@@ -46,6 +49,17 @@ func (c *RedisContainer) ConnectionString(ctx context.Context) (string, error) {
 	return uri, nil
 }
 
+// MustConnectionString panics if the address cannot be determined.
+// It takes a context, like the MySQL module's MustConnectionString, so that
+// resolving the address honours the caller's deadline.
+func (c *RedisContainer) MustConnectionString(ctx context.Context) string {
+	uri, err := c.ConnectionString(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return uri
+}
+
 // RunContainer creates an instance of the Redis container type
 func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (*RedisContainer, error) {
 	req := testcontainers.ContainerRequest{
@@ -73,18 +87,39 @@ func RunContainer(ctx context.Context, opts ...testcontainers.ContainerCustomize
 	return &RedisContainer{Container: container}, nil
 }
 
+// names of the options that cannot be combined: the save points passed on the command line by WithSnapshotting
+// would silently override the ones of the config file.
+const (
+	withConfigFileOption   = "redis.WithConfigFile"
+	withSnapshottingOption = "redis.WithSnapshotting"
+)
+
 // WithConfigFile sets the config file to be used for the redis container, and sets the command to run the redis server
-// using the passed config file
+// using the passed config file. If it's passed more than once, the last config file wins. It cannot be combined with
+// WithSnapshotting, as the save points of the command line would override the ones of the config file.
 func WithConfigFile(configFile string) testcontainers.CustomizeRequestOption {
 	const defaultConfigFile = "/usr/local/redis.conf"
 
-	return func(req *testcontainers.GenericContainerRequest) error {
+	return testcontainers.ExclusiveOption(withConfigFileOption, []string{withSnapshottingOption}, func(req *testcontainers.GenericContainerRequest) error {
 		cf := testcontainers.ContainerFile{
 			HostFilePath:      configFile,
 			ContainerFilePath: defaultConfigFile,
 			FileMode:          0o755,
 		}
-		req.Files = append(req.Files, cf)
+
+		// the config file is copied always to the same path, so it replaces the one of a previous call
+		files := make([]testcontainers.ContainerFile, 0, len(req.Files)+1)
+		for _, f := range req.Files {
+			if f.ContainerFilePath != defaultConfigFile {
+				files = append(files, f)
+			}
+		}
+		req.Files = append(files, cf)
+
+		if len(req.Cmd) > 1 && req.Cmd[0] == redisServerProcess && req.Cmd[1] == defaultConfigFile {
+			// the command already runs the redis server with the config file
+			return nil
+		}
 
 		if len(req.Cmd) == 0 {
 			req.Cmd = []string{redisServerProcess, defaultConfigFile}
@@ -101,10 +136,10 @@ func WithConfigFile(configFile string) testcontainers.CustomizeRequestOption {
 		}
 
 		return nil
-	}
+	})
 }
 
-// WithLogLevel sets the log level for the redis server process
+// WithLogLevel sets the log level for the redis server process. If it's passed more than once, the last log level wins.
 // See https://redis.io/docs/reference/modules/modules-api-ref/#redismodule_log for more information.
 func WithLogLevel(level LogLevel) testcontainers.CustomizeRequestOption {
 	return func(req *testcontainers.GenericContainerRequest) error {
@@ -116,7 +151,7 @@ func WithLogLevel(level LogLevel) testcontainers.CustomizeRequestOption {
 
 // WithSnapshotting sets the snapshotting configuration for the redis server process. You can configure Redis to have it
 // save the dataset every N seconds if there are at least M changes in the dataset.
-// This method allows Redis to benefit from copy-on-write semantics.
+// This method allows Redis to benefit from copy-on-write semantics. It cannot be combined with WithConfigFile.
 // See https://redis.io/docs/management/persistence/#snapshotting for more information.
 func WithSnapshotting(seconds int, changedKeys int) testcontainers.CustomizeRequestOption {
 	if changedKeys < 1 {
@@ -126,8 +161,28 @@ func WithSnapshotting(seconds int, changedKeys int) testcontainers.CustomizeRequ
 		seconds = 1
 	}
 
-	return func(req *testcontainers.GenericContainerRequest) error {
+	return testcontainers.ExclusiveOption(withSnapshottingOption, []string{withConfigFileOption}, func(req *testcontainers.GenericContainerRequest) error {
 		processRedisServerArgs(req, []string{"--save", fmt.Sprintf("%d", seconds), fmt.Sprintf("%d", changedKeys)})
+		return nil
+	})
+}
+
+// WithAOF enables the append only file (AOF) persistence for the redis server process, logging every write
+// operation received by the server, and sets how often the data is written to disk with the appendfsync
+// setting: "always", "everysec" or "no". If it's passed more than once, the last setting wins.
+// The data is stored in the /data directory, which is a volume of the image, so it's kept when the container
+// is stopped and started again, but not in a new container, unless a volume is mounted at /data.
+// See https://redis.io/docs/management/persistence/#append-only-file for more information.
+func WithAOF(appendfsync string) testcontainers.CustomizeRequestOption {
+	return func(req *testcontainers.GenericContainerRequest) error {
+		switch appendfsync {
+		case "always", "everysec", "no":
+		default:
+			return fmt.Errorf("invalid appendfsync %q: must be always, everysec or no", appendfsync)
+		}
+
+		processRedisServerArgs(req, []string{"--appendonly", "yes", "--appendfsync", appendfsync})
+
 		return nil
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
@@ -115,11 +116,79 @@ func TestRedisWithSnapshotting(t *testing.T) {
 	assertSetsGets(t, ctx, redisContainer, 10)
 }
 
+func TestRedisWithAOF(t *testing.T) {
+	ctx := context.Background()
+
+	redisContainer, err := tcredis.RunContainer(ctx, tcredis.WithAOF("always"))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		if err := redisContainer.Terminate(ctx); err != nil {
+			t.Fatalf("failed to terminate container: %s", err)
+		}
+	})
+
+	newClient := func() *redis.Client {
+		uri, err := redisContainer.ConnectionString(ctx)
+		require.NoError(t, err)
+
+		options, err := redis.ParseURL(uri)
+		require.NoError(t, err)
+
+		return redis.NewClient(options)
+	}
+
+	client := newClient()
+
+	appendonly, err := client.ConfigGet(ctx, "appendonly").Result()
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"appendonly", "yes"}, appendonly)
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, client.Set(ctx, fmt.Sprintf("aof.%d", i), i, 0).Err())
+	}
+	require.NoError(t, client.Close())
+
+	// the data is kept in the /data volume of the container, so it survives the restart
+	require.NoError(t, redisContainer.Stop(ctx, nil))
+	require.NoError(t, redisContainer.Start(ctx))
+
+	client = newClient()
+	defer client.Close()
+
+	for i := 0; i < 10; i++ {
+		value, err := client.Get(ctx, fmt.Sprintf("aof.%d", i)).Result()
+		require.NoError(t, err)
+		require.Equal(t, strconv.Itoa(i), value)
+	}
+}
+
+func TestRedisWithStartupTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("too-short", func(t *testing.T) {
+		_, err := tcredis.RunContainer(ctx, testcontainers.WithStartupTimeout(time.Millisecond))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("long-enough", func(t *testing.T) {
+		redisContainer, err := tcredis.RunContainer(ctx, testcontainers.WithStartupTimeout(2*time.Minute))
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			if err := redisContainer.Terminate(ctx); err != nil {
+				t.Fatalf("failed to terminate container: %s", err)
+			}
+		})
+
+		assertSetsGets(t, ctx, redisContainer, 1)
+	})
+}
+
 func assertSetsGets(t *testing.T, ctx context.Context, redisContainer *tcredis.RedisContainer, keyCount int) {
 	// connectionString {
 	uri, err := redisContainer.ConnectionString(ctx)
 	// }
 	require.NoError(t, err)
+	require.Equal(t, uri, redisContainer.MustConnectionString(ctx))
 
 	// You will likely want to wrap your Redis package of choice in an
 	// interface to aid in unit testing and limit lock-in throughout your
@@ -2,6 +2,8 @@ package testcontainers
 
 import (
 	"context"
+	"fmt"
+	"net"
 
 	"github.com/docker/docker/api/types/network"
 )
@@ -47,3 +49,36 @@ type NetworkRequest struct {
 	ReaperImage   string            // Deprecated: use WithImageName ContainerOption instead. Alternative reaper registry
 	ReaperOptions []ContainerOption // Deprecated: the reaper is configured at the properties level, for an entire test session
 }
+
+// staticIPAMConfig returns the IPAM config of the endpoint of a container requesting a static IP in the network.
+// The IP must be within one of the subnets of the network of the same family, if the network defines any.
+func staticIPAMConfig(nw network.Inspect, ip string) (*network.EndpointIPAMConfig, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil, fmt.Errorf("invalid static IP %q for network %s", ip, nw.Name)
+	}
+	isIPv4 := addr.To4() != nil
+
+	var subnets []string
+	within := false
+	for _, cfg := range nw.IPAM.Config {
+		_, subnet, err := net.ParseCIDR(cfg.Subnet)
+		if err != nil || (subnet.IP.To4() != nil) != isIPv4 {
+			continue
+		}
+
+		subnets = append(subnets, cfg.Subnet)
+		if subnet.Contains(addr) {
+			within = true
+		}
+	}
+	if len(subnets) > 0 && !within {
+		return nil, fmt.Errorf("static IP %s is not within the subnets %v of network %s", ip, subnets, nw.Name)
+	}
+
+	if isIPv4 {
+		return &network.EndpointIPAMConfig{IPv4Address: ip}, nil
+	}
+
+	return &network.EndpointIPAMConfig{IPv6Address: ip}, nil
+}
@@ -395,6 +395,125 @@ func TestNew_withOptions(t *testing.T) {
 	assert.Equal(t, ipamConfig, foundNetwork.IPAM)
 }
 
+func TestWithStaticIP(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx,
+		network.WithIPAM(&dockernetwork.IPAM{
+			Driver: "default",
+			Config: []dockernetwork.IPAMConfig{
+				{
+					Subnet:  "10.2.1.0/24",
+					Gateway: "10.2.1.254",
+				},
+			},
+		}),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, nw.Remove(ctx))
+	})
+
+	newRequest := func(t *testing.T, ip string) testcontainers.GenericContainerRequest {
+		t.Helper()
+
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image: nginxAlpineImage,
+			},
+		}
+		require.NoError(t, network.WithNetwork([]string{"nginx"}, nw)(&req))
+		require.NoError(t, testcontainers.WithStaticIP(nw.Name, ip)(&req))
+
+		return req
+	}
+
+	t.Run("within-subnet", func(t *testing.T) {
+		req := newRequest(t, "10.2.1.10")
+		req.Started = true
+
+		nginx, err := testcontainers.GenericContainer(ctx, req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, nginx.Terminate(ctx))
+		})
+
+		inspect, err := nginx.Inspect(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "10.2.1.10", inspect.NetworkSettings.Networks[nw.Name].IPAddress)
+	})
+
+	t.Run("outside-subnet", func(t *testing.T) {
+		_, err := testcontainers.GenericContainer(ctx, newRequest(t, "10.2.2.10"))
+		require.ErrorContains(t, err, "is not within the subnets [10.2.1.0/24]")
+	})
+}
+
+func TestConnectToNetwork(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, nw.Remove(ctx))
+	})
+
+	newContainer := func(t *testing.T, opts ...testcontainers.CustomizeRequestOption) *testcontainers.DockerContainer {
+		t.Helper()
+
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image: "docker.io/alpine:latest",
+				Cmd:   []string{"sleep", "300"},
+			},
+			Started: true,
+		}
+		for _, opt := range opts {
+			require.NoError(t, opt(&req))
+		}
+
+		ctr, err := testcontainers.GenericContainer(ctx, req)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, ctr.Terminate(ctx))
+		})
+
+		return ctr.(*testcontainers.DockerContainer)
+	}
+
+	client := newContainer(t, network.WithNetwork([]string{"client"}, nw))
+	node := newContainer(t)
+
+	ping := func(t *testing.T) int {
+		t.Helper()
+
+		code, _, err := client.Exec(ctx, []string{"ping", "-c", "1", "-W", "1", "node"})
+		require.NoError(t, err)
+		return code
+	}
+
+	// the node is not reachable until it's attached to the network
+	require.NotZero(t, ping(t))
+
+	require.NoError(t, node.ConnectToNetwork(ctx, nw.ID, "node"))
+
+	aliases, err := node.NetworkAliases(ctx)
+	require.NoError(t, err)
+	require.Contains(t, aliases[nw.Name], "node")
+	require.Zero(t, ping(t))
+
+	require.NoError(t, node.DisconnectFromNetwork(ctx, nw.ID))
+
+	networks, err := node.Networks(ctx)
+	require.NoError(t, err)
+	require.NotContains(t, networks, nw.Name)
+	require.NotZero(t, ping(t))
+
+	// the node can be reattached, e.g. after a partition test
+	require.NoError(t, node.ConnectToNetwork(ctx, nw.Name, "node"))
+	require.Zero(t, ping(t))
+}
+
 func TestWithNetwork(t *testing.T) {
 	// first create the network to be reused
 	nw, err := network.New(context.Background(), network.WithLabels(map[string]string{"network-type": "unique"}))
@@ -543,3 +662,89 @@ func TestWithNewNetworkContextTimeout(t *testing.T) {
 	assert.Empty(t, req.Networks)
 	assert.Empty(t, req.NetworkAliases)
 }
+
+func TestInternalEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, nw.Remove(ctx))
+	})
+
+	server, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:          nginxAlpineImage,
+			ExposedPorts:   []string{nginxDefaultPort},
+			Networks:       []string{nw.Name},
+			NetworkAliases: map[string][]string{nw.Name: {"server"}},
+			WaitingFor:     wait.ForListeningPort(nginxDefaultPort),
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, server.Terminate(ctx))
+	})
+
+	client, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:    nginxAlpineImage,
+			Networks: []string{nw.Name},
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Terminate(ctx))
+	})
+
+	endpoint, err := server.(*testcontainers.DockerContainer).InternalEndpoint(ctx, nginxDefaultPort, "http")
+	require.NoError(t, err)
+	require.Equal(t, "http://server:80", endpoint)
+
+	// the endpoint is reachable from the other container in the network
+	code, _, err := client.Exec(ctx, []string{"wget", "-q", "-O", "/dev/null", endpoint})
+	require.NoError(t, err)
+	require.Zero(t, code)
+}
+
+func TestWaitForNetwork(t *testing.T) {
+	ctx := context.Background()
+
+	nw, err := network.New(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, nw.Remove(ctx))
+	})
+
+	ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: nginxAlpineImage,
+		},
+		Started: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, ctr.Terminate(ctx))
+	})
+
+	cli, err := testcontainers.NewDockerClientWithOpts(ctx)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	// connect the running container to the network asynchronously
+	connected := make(chan error, 1)
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		connected <- cli.NetworkConnect(ctx, nw.ID, ctr.GetContainerID(), nil)
+	}()
+
+	err = wait.ForNetwork(nw.Name).WithStartupTimeout(10*time.Second).WaitUntilReady(ctx, ctr)
+	require.NoError(t, err)
+	require.NoError(t, <-connected)
+
+	networks, err := ctr.Networks(ctx)
+	require.NoError(t, err)
+	require.Contains(t, networks, nw.Name)
+}
@@ -0,0 +1,53 @@
+package testcontainers
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticIPAMConfig(t *testing.T) {
+	nw := network.Inspect{
+		Name: "my-network",
+		IPAM: network.IPAM{
+			Config: []network.IPAMConfig{
+				{Subnet: "10.1.1.0/24"},
+				{Subnet: "10.1.2.0/24"},
+				{Subnet: "fd00:1::/64"},
+			},
+		},
+	}
+
+	t.Run("ipv4", func(t *testing.T) {
+		cfg, err := staticIPAMConfig(nw, "10.1.2.10")
+		require.NoError(t, err)
+		require.Equal(t, &network.EndpointIPAMConfig{IPv4Address: "10.1.2.10"}, cfg)
+	})
+
+	t.Run("ipv6", func(t *testing.T) {
+		cfg, err := staticIPAMConfig(nw, "fd00:1::10")
+		require.NoError(t, err)
+		require.Equal(t, &network.EndpointIPAMConfig{IPv6Address: "fd00:1::10"}, cfg)
+	})
+
+	t.Run("outside-subnets", func(t *testing.T) {
+		_, err := staticIPAMConfig(nw, "10.1.3.10")
+		require.EqualError(t, err, "static IP 10.1.3.10 is not within the subnets [10.1.1.0/24 10.1.2.0/24] of network my-network")
+
+		_, err = staticIPAMConfig(nw, "fd00:2::10")
+		require.ErrorContains(t, err, "is not within the subnets [fd00:1::/64]")
+	})
+
+	t.Run("unknown-subnets", func(t *testing.T) {
+		// without known subnets, the address is not checked
+		cfg, err := staticIPAMConfig(network.Inspect{Name: "my-network"}, "172.30.0.10")
+		require.NoError(t, err)
+		require.Equal(t, &network.EndpointIPAMConfig{IPv4Address: "172.30.0.10"}, cfg)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := staticIPAMConfig(nw, "10.1.1")
+		require.EqualError(t, err, `invalid static IP "10.1.1" for network my-network`)
+	})
+}
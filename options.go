@@ -1,13 +1,26 @@
 package testcontainers
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
 	"time"
 
 	"dario.cat/mergo"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
 
 	tcexec "github.com/testcontainers/testcontainers-go/exec"
 	"github.com/testcontainers/testcontainers-go/internal/core"
@@ -40,15 +53,47 @@ func CustomizeRequest(src GenericContainerRequest) CustomizeRequestOption {
 	}
 }
 
-// WithConfigModifier allows to override the default container config
-func WithConfigModifier(modifier func(config *container.Config)) CustomizeRequestOption {
+// ErrConflictingOptions is returned when options that cannot be combined are applied to the same request
+var ErrConflictingOptions = errors.New("conflicting options")
+
+// ExclusiveOption wraps the given option, tagging it with a name, so that applying it returns an
+// ErrConflictingOptions error if any of the options it conflicts with, identified by their names,
+// has already been applied to the request. Conflicts are symmetric, so they are detected regardless
+// of the order of the options. An option can be applied more than once, usually with the last one winning,
+// unless the name of the option itself is passed as a conflict, e.g. because its calls cannot be merged.
+// It's meant to be used by modules, e.g. ExclusiveOption("redis.WithConfigFile", []string{"redis.WithSnapshotting"}, opt).
+func ExclusiveOption(name string, conflictsWith []string, opt CustomizeRequestOption) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
-		req.ConfigModifier = modifier
+		for applied, appliedConflicts := range req.appliedOptions {
+			if !slices.Contains(conflictsWith, applied) && !slices.Contains(appliedConflicts, name) {
+				continue
+			}
+
+			if applied == name {
+				return fmt.Errorf("%w: %s cannot be applied more than once", ErrConflictingOptions, name)
+			}
+			return fmt.Errorf("%w: %s cannot be combined with %s", ErrConflictingOptions, name, applied)
+		}
+
+		if err := opt(req); err != nil {
+			return err
+		}
+
+		if req.appliedOptions == nil {
+			req.appliedOptions = map[string][]string{}
+		}
+		req.appliedOptions[name] = conflictsWith
 
 		return nil
 	}
 }
 
+// WithConfigModifier allows to override the default container config. The modifier is registered at
+// the ModifierPriorityUser priority, so it runs after the ones of the modules instead of replacing them.
+func WithConfigModifier(modifier func(config *container.Config)) CustomizeRequestOption {
+	return WithConfigModifierAt(ModifierPriorityUser, modifier)
+}
+
 // WithEndpointSettingsModifier allows to override the default endpoint settings
 func WithEndpointSettingsModifier(modifier func(settings map[string]*network.EndpointSettings)) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
@@ -58,6 +103,24 @@ func WithEndpointSettingsModifier(modifier func(settings map[string]*network.End
 	}
 }
 
+// WithStaticIP requests a static IPv4 or IPv6 address for the container in the given user-defined network,
+// to which the container must be attached, e.g. using the network.WithNetwork option. The address must be
+// within a subnet of the network, which is checked when the container is created if the network defines it.
+func WithStaticIP(networkName string, ip string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("invalid static IP %q for network %s", ip, networkName)
+		}
+
+		if req.NetworkStaticIPs == nil {
+			req.NetworkStaticIPs = make(map[string]string)
+		}
+		req.NetworkStaticIPs[networkName] = ip
+
+		return nil
+	}
+}
+
 // WithEnv sets the environment variables for a container.
 // If the environment variable already exists, it will be overridden.
 func WithEnv(envs map[string]string) CustomizeRequestOption {
@@ -74,15 +137,86 @@ func WithEnv(envs map[string]string) CustomizeRequestOption {
 	}
 }
 
-// WithHostConfigModifier allows to override the default host config
-func WithHostConfigModifier(modifier func(hostConfig *container.HostConfig)) CustomizeRequestOption {
+// WithEnvFile sets the environment variables for a container from a dotenv file, with a KEY=VALUE
+// entry per line. Quoted values, "export " prefixes and "#" comments are supported.
+// The environment variables already set in the request take precedence over the ones in the file.
+func WithEnvFile(path string) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
-		req.HostConfigModifier = modifier
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open env file: %w", err)
+		}
+		defer f.Close()
+
+		env, err := parseEnvFile(f)
+		if err != nil {
+			return fmt.Errorf("parse env file %s: %w", path, err)
+		}
+
+		if req.Env == nil {
+			req.Env = map[string]string{}
+		}
+
+		for key, val := range env {
+			if _, ok := req.Env[key]; !ok {
+				req.Env[key] = val
+			}
+		}
+
+		return nil
+	}
+}
+
+// zoneinfoDirs are the directories of the host where the tzdata files are looked up, the same as the time package
+var zoneinfoDirs = []string{
+	"/usr/share/zoneinfo/",
+	"/usr/share/lib/zoneinfo/",
+	"/usr/lib/locale/TZ/",
+}
+
+// WithTimezone sets the timezone of the container, as an IANA tzdata name, e.g. "Europe/Madrid",
+// which is validated using the tzdata of the host. It sets the TZ environment variable, and copies
+// the tzdata file of the host into the container, both as the zoneinfo file of the timezone and as
+// /etc/localtime, so the timezone also applies in images without tzdata, e.g. Alpine.
+// The file is not copied if the host has no tzdata files, e.g. on Windows.
+func WithTimezone(tz string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if tz == "" || tz == "Local" {
+			return fmt.Errorf("invalid timezone %q", tz)
+		}
+
+		if _, err := time.LoadLocation(tz); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+
+		if req.Env == nil {
+			req.Env = map[string]string{}
+		}
+		req.Env["TZ"] = tz
+
+		for _, dir := range zoneinfoDirs {
+			hostPath := filepath.Join(dir, tz)
+			if info, err := os.Stat(hostPath); err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+
+			req.Files = append(req.Files,
+				ContainerFile{HostFilePath: hostPath, ContainerFilePath: "/usr/share/zoneinfo/" + tz, FileMode: 0o644},
+				ContainerFile{HostFilePath: hostPath, ContainerFilePath: "/etc/localtime", FileMode: 0o644},
+			)
+			break
+		}
 
 		return nil
 	}
 }
 
+// WithHostConfigModifier allows to override the default host config. The modifier is registered at
+// the ModifierPriorityUser priority, so it runs after the ones of the modules instead of replacing them.
+func WithHostConfigModifier(modifier func(hostConfig *container.HostConfig)) CustomizeRequestOption {
+	return WithHostConfigModifierAt(ModifierPriorityUser, modifier)
+}
+
 // WithHostPortAccess allows to expose the host ports to the container
 func WithHostPortAccess(ports ...int) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
@@ -95,6 +229,120 @@ func WithHostPortAccess(ports ...int) CustomizeRequestOption {
 	}
 }
 
+// WithAutoExposeImagePorts exposes all the ports declared by the image on random host ports,
+// in addition to the ports defined in the ExposedPorts of the request.
+// It's a no-op if the image does not declare any port.
+func WithAutoExposeImagePorts() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.AutoExposeImagePorts = true
+
+		return nil
+	}
+}
+
+// WithTmpfsMount adds a tmpfs mount at the given target of the container,
+// using the typed options to set its size and mode.
+// The target must be an absolute path, the size must not be negative and
+// the mode must be a valid octal permission, e.g. 0o1777.
+func WithTmpfsMount(target string, opts TmpfsOptions) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if err := opts.validate(target); err != nil {
+			return err
+		}
+
+		req.Mounts = append(req.Mounts, ContainerMount{
+			Source: DockerTmpfsMountSource{
+				TmpfsOptions: &mount.TmpfsOptions{
+					SizeBytes: opts.SizeBytes,
+					Mode:      opts.Mode,
+				},
+			},
+			Target: ContainerMountTarget(target),
+		})
+
+		return nil
+	}
+}
+
+// WithUnixSocketDir bind-mounts the given host directory at the given directory of the container,
+// so the Unix sockets created by the container in it can be dialed from the host, using the host
+// path returned by DockerContainer.UnixSocketPath. Both paths must be absolute, and the host directory
+// must exist. As a bind mount, it only works when the Docker daemon runs on the same host as the tests.
+func WithUnixSocketDir(hostDir string, containerDir string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if !filepath.IsAbs(hostDir) {
+			return fmt.Errorf("host directory %q for the Unix sockets must be an absolute path", hostDir)
+		}
+		if !strings.HasPrefix(containerDir, "/") {
+			return fmt.Errorf("container directory %q for the Unix sockets must be an absolute path", containerDir)
+		}
+
+		return WithHostConfigModifierAt(ModifierPriorityUser, func(hc *container.HostConfig) {
+			hc.Mounts = append(hc.Mounts, mount.Mount{
+				Type:   mount.TypeBind,
+				Source: hostDir,
+				Target: containerDir,
+			})
+		})(req)
+	}
+}
+
+// WithSeccompProfile applies the seccomp profile of the given JSON file to the container.
+// The profile is read and embedded into the security options when the option is applied,
+// as the file may not be accessible by the Docker daemon, e.g. when it runs on a remote host.
+func WithSeccompProfile(profilePath string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		profile, err := os.ReadFile(profilePath)
+		if err != nil {
+			return fmt.Errorf("read seccomp profile: %w", err)
+		}
+
+		var compacted bytes.Buffer
+		if err := json.Compact(&compacted, profile); err != nil {
+			return fmt.Errorf("invalid seccomp profile %s: %w", profilePath, err)
+		}
+
+		req.SecurityOpts = append(req.SecurityOpts, "seccomp="+compacted.String())
+
+		return nil
+	}
+}
+
+// WithApparmorProfile applies the AppArmor profile with the given name to the container.
+// The profile must be loaded in the host running the Docker daemon.
+func WithApparmorProfile(profileName string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.SecurityOpts = append(req.SecurityOpts, "apparmor="+profileName)
+
+		return nil
+	}
+}
+
+// WithNoNewPrivileges prevents the processes of the container from gaining new privileges,
+// e.g. running setuid or setgid binaries.
+func WithNoNewPrivileges() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.SecurityOpts = append(req.SecurityOpts, "no-new-privileges")
+
+		return nil
+	}
+}
+
+// WithRestartPolicy sets the restart policy of the container, so the Docker engine restarts it automatically
+// when it exits, e.g. to test how a cluster recovers from a node being OOM-killed. The maximum retry count
+// can only be set for the "on-failure" policy, and the policy cannot be used together with AutoRemove.
+// Use DockerContainer.RestartCount to observe the restarts.
+func WithRestartPolicy(name container.RestartPolicyMode, maxRetries int) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.RestartPolicy = container.RestartPolicy{
+			Name:              name,
+			MaximumRetryCount: maxRetries,
+		}
+
+		return nil
+	}
+}
+
 // WithImage sets the image for a container
 func WithImage(image string) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
@@ -104,6 +352,198 @@ func WithImage(image string) CustomizeRequestOption {
 	}
 }
 
+// WithCmdString sets the command of the container, splitting the string into arguments the way a POSIX
+// shell does, without expanding variables or globs, e.g. `redis-server --save "" --loglevel 'verbose'`
+// becomes ["redis-server", "--save", "", "--loglevel", "verbose"]. Single quotes keep their content as is,
+// double quotes only allow escaping ", \, $ and ` with a backslash, and a backslash outside quotes
+// escapes the next character. An unbalanced quote or a trailing backslash returns an error.
+func WithCmdString(cmd string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		args, err := splitCmdString(cmd)
+		if err != nil {
+			return fmt.Errorf("split command %q: %w", cmd, err)
+		}
+
+		req.Cmd = args
+
+		return nil
+	}
+}
+
+// splitCmdString splits a command into arguments following the quoting rules of a POSIX shell.
+func splitCmdString(cmd string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool // true if an argument was started, even if it's empty, e.g. ""
+		quote   rune // the opening quote, or zero outside quotes
+		escaped bool
+	)
+
+	for _, r := range cmd {
+		switch {
+		case escaped:
+			escaped = false
+			// inside double quotes, the backslash only escapes some characters
+			if quote == '"' && !strings.ContainsRune("\"\\$`\n", r) {
+				current.WriteRune('\\')
+			}
+			// an escaped line break is a line continuation, so it's removed
+			if r != '\n' {
+				current.WriteRune(r)
+				inArg = true
+			}
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	switch {
+	case escaped:
+		return nil, errors.New("trailing backslash")
+	case quote != 0:
+		return nil, fmt.Errorf("unbalanced %c quote", quote)
+	}
+
+	if inArg {
+		args = append(args, current.String())
+	}
+
+	return args, nil
+}
+
+// WithImagePlatformAndLocalOnly makes an image referenced by digest, i.e. an image ID such as "sha256:<hex>"
+// or a name with a digest such as "redis@sha256:<hex>", be used only if it's present locally, e.g. because
+// it was pre-loaded with "docker load", never pulling it, so no request is sent to the registry.
+// If the request sets an image platform, the platform of the local image must match it instead of
+// pulling the image for that platform. The images referenced by tag are pulled as usual.
+func WithImagePlatformAndLocalOnly() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.ImageLocalOnly = true
+
+		return nil
+	}
+}
+
+// WithDebugEntrypoint keeps the container alive, replacing its entrypoint and command with
+// "tail -f /dev/null" and disabling its wait strategy, so it's possible to exec into it to debug
+// a process that crashes right after starting, running the real command manually.
+// A warning is logged, as the container does not run its normal process. It should be passed
+// after any other option modifying the entrypoint, the command or the wait strategy.
+func WithDebugEntrypoint() CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		logger := req.Logger
+		if logger == nil {
+			logger = Logger
+		}
+
+		logger.Printf("⚠️ Debug entrypoint enabled for image %s: the container does not run its normal process and it's not waited for. Requested entrypoint: %q, command: %q",
+			req.Image, req.Entrypoint, req.Cmd)
+
+		req.Entrypoint = []string{"tail", "-f", "/dev/null"}
+		req.Cmd = nil
+		req.WaitingFor = nil
+
+		return nil
+	}
+}
+
+// WithTemplatedFile renders the given text/template with the data, and copies the result
+// into the container at the given path, right after the container is created.
+// Errors parsing or executing the template are returned when the option is applied.
+func WithTemplatedFile(tmpl string, data any, containerPath string, mode int64) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		t, err := template.New(containerPath).Parse(tmpl)
+		if err != nil {
+			return fmt.Errorf("parse template for %s: %w", containerPath, err)
+		}
+
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, data); err != nil {
+			return fmt.Errorf("execute template for %s: %w", containerPath, err)
+		}
+
+		req.Files = append(req.Files, ContainerFile{
+			Reader:            &buf,
+			ContainerFilePath: containerPath,
+			FileMode:          mode,
+		})
+
+		return nil
+	}
+}
+
+// WithSecretFile copies the secret content into the container at the given path, right after
+// the container is created, with mode 0400. The file is owned by the user of the container
+// if it's numeric, e.g. "1000:1000", so it can be read by a non-root process, or by root otherwise.
+// The content is never logged, and it's scrubbed from the error messages.
+func WithSecretFile(content []byte, containerPath string) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.LifecycleHooks = append(req.LifecycleHooks, ContainerLifecycleHooks{
+			PostCreates: []ContainerHook{
+				func(ctx context.Context, c Container) error {
+					dockerContainer, ok := c.(*DockerContainer)
+					if !ok {
+						return fmt.Errorf("secret file %s: unsupported container type %T", containerPath, c)
+					}
+
+					return dockerContainer.copySecretToContainer(ctx, content, containerPath)
+				},
+			},
+		})
+
+		return nil
+	}
+}
+
+// WithFilesFromDir copies the contents of the host directory into the given directory of the
+// container, right after the container is created, creating it if needed. Files and directories
+// are copied with the given mode. Unlike the directories in the ContainerRequest.Files field,
+// which are copied into their container path, only their contents are copied, so it can be
+// used with a temporary directory created with TempDir.
+func WithFilesFromDir(hostDir string, containerDir string, fileMode int64) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		req.LifecycleHooks = append(req.LifecycleHooks, ContainerLifecycleHooks{
+			PostCreates: []ContainerHook{
+				func(ctx context.Context, c Container) error {
+					dockerContainer, ok := c.(*DockerContainer)
+					if !ok {
+						return fmt.Errorf("files from dir %s: unsupported container type %T", hostDir, c)
+					}
+
+					return dockerContainer.copyDirContentsToContainer(ctx, hostDir, containerDir, fileMode)
+				},
+			},
+		})
+
+		return nil
+	}
+}
+
 // imageSubstitutor {
 
 // ImageSubstitutor represents a way to substitute container image names
@@ -148,6 +588,7 @@ func (p prependHubRegistry) Substitute(image string) (string, error) {
 		func() bool { return registry != "" },                        // non-hub image
 		func() bool { return registry == "docker.io" },               // explicitly including docker.io
 		func() bool { return registry == "registry.hub.docker.com" }, // explicitly including registry.hub.docker.com
+		func() bool { return strings.HasPrefix(image, "sha256:") },   // image ID, not a name in a registry
 	}
 
 	for _, exclusion := range exclusions {
@@ -268,11 +709,127 @@ func WithAfterReadyCommand(execs ...Executable) CustomizeRequestOption {
 	}
 }
 
+// WithRunOnce will execute the command representation of each Executable into the container once
+// it's ready, like WithAfterReadyCommand, but only once per container, so the commands are skipped
+// when attaching to a reused container that already ran them, e.g. to seed a database. A marker file,
+// written into the root directory of the container once a command exits with code 0, records that it ran,
+// so the root filesystem must be writable: combining it with ReadOnlyRootfs returns an error, as does
+// failing to write the marker, instead of running the command again on the next attach. A command
+// exiting with a different code returns an error, and it's run again on the next attach.
+func WithRunOnce(execs ...Executable) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if req.ReadOnlyRootfs {
+			return errRunOnceReadOnlyRootfs
+		}
+		// the root filesystem could still be made read-only by a later option, so it's also validated
+		req.runOnce = true
+
+		postReadiesHook := []ContainerHook{}
+
+		for _, exec := range execs {
+			exec := exec
+			execFn := func(ctx context.Context, c Container) error {
+				cmd := exec.AsCommand()
+				marker := runOnceMarkerPath(cmd)
+
+				r, err := c.CopyFileFromContainer(ctx, marker)
+				if err == nil {
+					// the command already ran in the container
+					return r.Close()
+				}
+				if !errdefs.IsNotFound(err) {
+					return fmt.Errorf("check run-once marker: %w", err)
+				}
+
+				// a host config modifier could have made the root filesystem read-only
+				inspect, err := c.Inspect(ctx)
+				if err != nil {
+					return fmt.Errorf("inspect container: %w", err)
+				}
+				if inspect.HostConfig != nil && inspect.HostConfig.ReadonlyRootfs {
+					return errRunOnceReadOnlyRootfs
+				}
+
+				code, _, err := c.Exec(ctx, cmd, exec.Options()...)
+				if err != nil {
+					return err
+				}
+				if code != 0 {
+					return fmt.Errorf("run-once command %q exited with code %d", cmd, code)
+				}
+
+				// without the marker the command would run again when the container is reused
+				if err := c.CopyToContainer(ctx, []byte(strings.Join(cmd, " ")), marker, 0o644); err != nil {
+					return fmt.Errorf("write run-once marker of command %q: %w", cmd, err)
+				}
+
+				return nil
+			}
+
+			postReadiesHook = append(postReadiesHook, execFn)
+		}
+
+		req.LifecycleHooks = append(req.LifecycleHooks, ContainerLifecycleHooks{
+			PostReadies: postReadiesHook,
+		})
+
+		return nil
+	}
+}
+
+// errRunOnceReadOnlyRootfs is returned when WithRunOnce is combined with a read-only root filesystem,
+// in which the markers recording that the commands ran cannot be written.
+var errRunOnceReadOnlyRootfs = errors.New("WithRunOnce requires a writable root filesystem to record that the commands ran, but it's read-only")
+
+// runOnceMarkerPath returns the path of the file marking that the command ran in the container,
+// derived from the command so that each command of WithRunOnce is tracked on its own.
+func runOnceMarkerPath(cmd []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(cmd, "\x00")))
+	return "/.testcontainers-run-once-" + hex.EncodeToString(sum[:8])
+}
+
 // WithWaitStrategy sets the wait strategy for a container, using 60 seconds as deadline
 func WithWaitStrategy(strategies ...wait.Strategy) CustomizeRequestOption {
 	return WithWaitStrategyAndDeadline(60*time.Second, strategies...)
 }
 
+// WithReadinessExec makes the container be ready once the command, run inside it, exits with code 0,
+// running it repeatedly until then, using a wait.ForExec strategy, which can be configured with
+// the given functions, e.g. to change its poll interval or its expected exit code. If the request
+// already has a wait strategy, both must succeed, combining them with wait.ForAll.
+func WithReadinessExec(cmd []string, opts ...func(*wait.ExecStrategy)) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		strategy := wait.ForExec(cmd)
+		for _, opt := range opts {
+			opt(strategy)
+		}
+
+		if req.WaitingFor == nil {
+			req.WaitingFor = strategy
+		} else {
+			req.WaitingFor = wait.ForAll(req.WaitingFor, strategy)
+		}
+
+		return nil
+	}
+}
+
+// WithStartupTimeout overrides the startup timeout of the wait strategy of the container, including
+// the ones defined by the modules, so callers don't need to redefine a module's wait strategy just to
+// give it more or less time. It's applied to the wait strategy defined at the moment the option is
+// applied, so it should be passed after any other option modifying the wait strategy.
+func WithStartupTimeout(timeout time.Duration) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		if req.WaitingFor == nil {
+			return nil
+		}
+
+		wait.SetStartupTimeout(req.WaitingFor, timeout)
+
+		return nil
+	}
+}
+
 // WithWaitStrategyAndDeadline sets the wait strategy for a container, including deadline
 func WithWaitStrategyAndDeadline(deadline time.Duration, strategies ...wait.Strategy) CustomizeRequestOption {
 	return func(req *GenericContainerRequest) error {
@@ -2,9 +2,16 @@ package testcontainers_test
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -94,7 +101,7 @@ func TestWithLogConsumers(t *testing.T) {
 	c, err := testcontainers.GenericContainer(context.Background(), req)
 	// we expect an error because the MySQL environment variables are not set
 	// but this is expected because we just want to test the log consumer
-	require.EqualError(t, err, "failed to start container: container exited with code 1")
+	require.ErrorContains(t, err, "failed to start container: container exited with code 1")
 	// c might be not nil even on error
 	if c != nil {
 		defer func() {
@@ -170,6 +177,110 @@ func TestWithAfterReadyCommand(t *testing.T) {
 	assert.Equal(t, "/tmp/.testcontainers\n", string(content))
 }
 
+func TestWithRunOnce(t *testing.T) {
+	ctx := context.Background()
+
+	newRequest := func() testcontainers.GenericContainerRequest {
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "alpine",
+				Name:       "test-with-run-once",
+				Entrypoint: []string{"tail", "-f", "/dev/null"},
+			},
+			Started: true,
+			Reuse:   true,
+		}
+
+		testExec := testcontainers.NewRawCommand([]string{"sh", "-c", "echo ran >> /tmp/run-once.log"})
+
+		err := testcontainers.WithRunOnce(testExec)(&req)
+		require.NoError(t, err)
+
+		return req
+	}
+
+	c1, err := testcontainers.GenericContainer(ctx, newRequest())
+	require.NoError(t, err)
+	terminateContainerOnEnd(t, ctx, c1)
+
+	// the container is reused, so the command must not run again
+	c2, err := testcontainers.GenericContainer(ctx, newRequest())
+	require.NoError(t, err)
+	require.Equal(t, c1.GetContainerID(), c2.GetContainerID())
+
+	_, reader, err := c2.Exec(ctx, []string{"cat", "/tmp/run-once.log"}, exec.Multiplexed())
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Equal(t, "ran\n", string(content))
+
+	t.Run("failed", func(t *testing.T) {
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "alpine",
+				Entrypoint: []string{"tail", "-f", "/dev/null"},
+			},
+			Started: true,
+		}
+
+		err := testcontainers.WithRunOnce(testcontainers.NewRawCommand([]string{"sh", "-c", "exit 3"}))(&req)
+		require.NoError(t, err)
+
+		c, err := testcontainers.GenericContainer(ctx, req)
+		terminateContainerOnEnd(t, ctx, c)
+		require.ErrorContains(t, err, "exited with code 3")
+	})
+
+	t.Run("read-only-rootfs", func(t *testing.T) {
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "alpine",
+				Entrypoint: []string{"tail", "-f", "/dev/null"},
+			},
+			Started: true,
+		}
+
+		opts := []testcontainers.CustomizeRequestOption{
+			testcontainers.WithHostConfigModifier(func(hostConfig *container.HostConfig) {
+				hostConfig.ReadonlyRootfs = true
+			}),
+			// the marker cannot be written, so the command must not run
+			testcontainers.WithRunOnce(testcontainers.NewRawCommand([]string{"true"})),
+		}
+		for _, opt := range opts {
+			require.NoError(t, opt(&req))
+		}
+
+		c, err := testcontainers.GenericContainer(ctx, req)
+		terminateContainerOnEnd(t, ctx, c)
+		require.ErrorContains(t, err, "WithRunOnce requires a writable root filesystem")
+	})
+}
+
+func TestWithRunOnce_ReadOnlyRootfs(t *testing.T) {
+	runOnce := testcontainers.WithRunOnce(testcontainers.NewRawCommand([]string{"true"}))
+
+	t.Run("read-only-before", func(t *testing.T) {
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{ReadOnlyRootfs: true},
+		}
+
+		require.ErrorContains(t, runOnce(&req), "WithRunOnce requires a writable root filesystem")
+	})
+
+	t.Run("read-only-after", func(t *testing.T) {
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{Image: "alpine"},
+		}
+		require.NoError(t, runOnce(&req))
+		require.NoError(t, req.Validate())
+
+		req.ReadOnlyRootfs = true
+		require.ErrorContains(t, req.Validate(), "WithRunOnce requires a writable root filesystem")
+	})
+}
+
 func TestWithEnv(t *testing.T) {
 	tests := map[string]struct {
 		req    *testcontainers.GenericContainerRequest
@@ -218,6 +329,111 @@ func TestWithEnv(t *testing.T) {
 	}
 }
 
+func TestWithEnvFile(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(envFile, []byte("# database settings\nexport DB_HOST=localhost\nDB_USER=\"tc user\" # quoted\nDB_PORT=5432\n"), 0o600))
+
+	t.Run("merge", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Env: map[string]string{"DB_PORT": "5433", "KEY": "VAL"},
+			},
+		}
+
+		require.NoError(t, testcontainers.WithEnvFile(envFile).Customize(req))
+
+		// the variables of the request take precedence
+		require.Equal(t, map[string]string{
+			"DB_HOST": "localhost",
+			"DB_USER": "tc user",
+			"DB_PORT": "5433",
+			"KEY":     "VAL",
+		}, req.Env)
+	})
+
+	t.Run("nil-env", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+
+		require.NoError(t, testcontainers.WithEnvFile(envFile).Customize(req))
+		require.Len(t, req.Env, 3)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		malformed := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(malformed, []byte("DB_HOST=localhost\nDB_USER\n"), 0o600))
+
+		req := &testcontainers.GenericContainerRequest{}
+		err := testcontainers.WithEnvFile(malformed).Customize(req)
+		require.ErrorContains(t, err, "line 2: missing '='")
+		require.Empty(t, req.Env)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := testcontainers.WithEnvFile(filepath.Join(t.TempDir(), "missing.env")).Customize(&testcontainers.GenericContainerRequest{})
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestWithTimezone(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Env: map[string]string{"KEY": "VAL"},
+			},
+		}
+
+		require.NoError(t, testcontainers.WithTimezone("Asia/Tokyo").Customize(req))
+		require.Equal(t, map[string]string{"KEY": "VAL", "TZ": "Asia/Tokyo"}, req.Env)
+
+		if _, err := os.Stat("/usr/share/zoneinfo/Asia/Tokyo"); err != nil {
+			t.Skip("the host has no tzdata files")
+		}
+
+		require.Len(t, req.Files, 2)
+		require.Equal(t, "/usr/share/zoneinfo/Asia/Tokyo", req.Files[0].ContainerFilePath)
+		require.Equal(t, "/etc/localtime", req.Files[1].ContainerFilePath)
+	})
+
+	for _, tz := range []string{"", "Local", "Mars/Olympus_Mons", "../../etc/passwd"} {
+		t.Run("invalid/"+tz, func(t *testing.T) {
+			req := &testcontainers.GenericContainerRequest{}
+
+			err := testcontainers.WithTimezone(tz).Customize(req)
+			require.ErrorContains(t, err, "invalid timezone")
+			require.Empty(t, req.Env)
+			require.Empty(t, req.Files)
+		})
+	}
+}
+
+func TestWithTimezone_Container(t *testing.T) {
+	ctx := context.Background()
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:      "docker.io/alpine:latest",
+			Cmd:        []string{"date", "+%Z"},
+			WaitingFor: wait.ForExit(),
+		},
+		Started: true,
+	}
+	require.NoError(t, testcontainers.WithTimezone("Asia/Tokyo").Customize(&req))
+
+	ctr, err := testcontainers.GenericContainer(ctx, req)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, ctr.Terminate(ctx))
+	})
+
+	r, err := ctr.Logs(ctx)
+	require.NoError(t, err)
+	defer r.Close()
+
+	logs, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Contains(t, string(logs), "JST")
+}
+
 func TestWithHostPortAccess(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -250,3 +466,460 @@ func TestWithHostPortAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestWithTemplatedFile(t *testing.T) {
+	t.Run("invalid-template", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+
+		opt := testcontainers.WithTemplatedFile("port={{ .Port", nil, "/tmp/app.conf", 0o644)
+		require.Error(t, opt.Customize(req))
+		require.Empty(t, req.Files)
+	})
+
+	t.Run("missing-key", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+
+		opt := testcontainers.WithTemplatedFile("port={{ .Port.Number }}", struct{ Port int }{Port: 1}, "/tmp/app.conf", 0o644)
+		require.Error(t, opt.Customize(req))
+		require.Empty(t, req.Files)
+	})
+
+	t.Run("rendered-in-container", func(t *testing.T) {
+		ctx := context.Background()
+
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "alpine",
+				Entrypoint: []string{"tail", "-f", "/dev/null"},
+			},
+			Started: true,
+		}
+
+		data := map[string]any{"Port": 8080, "Name": "testcontainers"}
+		opt := testcontainers.WithTemplatedFile("name={{ .Name }}\nport={{ .Port }}\n", data, "/tmp/app.conf", 0o644)
+		require.NoError(t, opt.Customize(&req))
+		require.Len(t, req.Files, 1)
+
+		c, err := testcontainers.GenericContainer(ctx, req)
+		require.NoError(t, err)
+		defer func() {
+			err = c.Terminate(ctx)
+			require.NoError(t, err)
+		}()
+
+		_, reader, err := c.Exec(ctx, []string{"cat", "/tmp/app.conf"}, exec.Multiplexed())
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "name=testcontainers\nport=8080\n", string(content))
+	})
+}
+
+func TestWithSecretFile(t *testing.T) {
+	ctx := context.Background()
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:      "alpine",
+			Entrypoint: []string{"tail", "-f", "/dev/null"},
+			User:       "1000:1000",
+		},
+		Started: true,
+	}
+
+	opt := testcontainers.WithSecretFile([]byte("s3cr3t-t0k3n"), "/run/secrets/token")
+	require.NoError(t, opt.Customize(&req))
+	require.Empty(t, req.Files, "the secret must not be added to the request files")
+
+	c, err := testcontainers.GenericContainer(ctx, req)
+	terminateContainerOnEnd(t, ctx, c)
+	require.NoError(t, err)
+
+	_, reader, err := c.Exec(ctx, []string{"stat", "-c", "%a %u:%g", "/run/secrets/token"}, exec.Multiplexed())
+	require.NoError(t, err)
+
+	stat, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "400 1000:1000\n", string(stat))
+
+	// the process of the container can read the secret, as it's running as the owner of the file
+	_, reader, err = c.Exec(ctx, []string{"cat", "/run/secrets/token"}, exec.Multiplexed())
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t-t0k3n", string(content))
+}
+
+func TestWithStartupTimeout(t *testing.T) {
+	t.Run("no-wait-strategy", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+
+		opt := testcontainers.WithStartupTimeout(time.Minute)
+		require.NoError(t, opt.Customize(req))
+		require.Nil(t, req.WaitingFor)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		strategy := wait.ForLog("ready").WithStartupTimeout(time.Second)
+		req := &testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				WaitingFor: strategy,
+			},
+		}
+
+		opt := testcontainers.WithStartupTimeout(time.Minute)
+		require.NoError(t, opt.Customize(req))
+		require.Equal(t, time.Minute, *strategy.Timeout())
+	})
+}
+
+// messagesLogger is a logger that keeps the messages it logs.
+type messagesLogger struct {
+	messages []string
+}
+
+func (l *messagesLogger) Printf(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func TestWithDebugEntrypoint(t *testing.T) {
+	logger := &messagesLogger{}
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image: "alpine",
+			// the process crashes right after starting
+			Cmd:        []string{"sh", "-c", "exit 1"},
+			WaitingFor: wait.ForLog("never logged"),
+		},
+		Logger:  logger,
+		Started: true,
+	}
+
+	err := testcontainers.WithDebugEntrypoint()(&req)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"tail", "-f", "/dev/null"}, req.Entrypoint)
+	require.Empty(t, req.Cmd)
+	require.Nil(t, req.WaitingFor)
+	require.Len(t, logger.messages, 1)
+	require.Contains(t, logger.messages[0], "Debug entrypoint enabled for image alpine")
+	require.Contains(t, logger.messages[0], `["sh" "-c" "exit 1"]`)
+
+	c, err := testcontainers.GenericContainer(context.Background(), req)
+	require.NoError(t, err)
+	defer func() {
+		err = c.Terminate(context.Background())
+		require.NoError(t, err)
+	}()
+
+	state, err := c.State(context.Background())
+	require.NoError(t, err)
+	require.True(t, state.Running)
+
+	code, reader, err := c.Exec(context.Background(), []string{"echo", "debugging"}, exec.Multiplexed())
+	require.NoError(t, err)
+	require.Zero(t, code)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "debugging\n", string(content))
+}
+
+func TestWithCmdString(t *testing.T) {
+	tests := []struct {
+		name   string
+		cmd    string
+		expect []string
+		errMsg string
+	}{
+		{
+			name:   "plain",
+			cmd:    "  echo  hello\tworld\n",
+			expect: []string{"echo", "hello", "world"},
+		},
+		{
+			name:   "quotes",
+			cmd:    `arg1 'arg with space' "double quoted" mixed'single'"double" ''`,
+			expect: []string{"arg1", "arg with space", "double quoted", "mixedsingledouble", ""},
+		},
+		{
+			name:   "escapes",
+			cmd:    `escaped\ space \'not-quoted\' "a \"b\" \$c \d" 'no \escape' line\` + "\n" + `continued`,
+			expect: []string{"escaped space", "'not-quoted'", `a "b" $c \d`, `no \escape`, "linecontinued"},
+		},
+		{
+			name:   "empty",
+			cmd:    "",
+			expect: nil,
+		},
+		{
+			name:   "unbalanced-single-quote",
+			cmd:    `echo 'hello`,
+			errMsg: `split command "echo 'hello": unbalanced ' quote`,
+		},
+		{
+			name:   "unbalanced-double-quote",
+			cmd:    `echo "hello 'world'`,
+			errMsg: `split command "echo \"hello 'world'": unbalanced " quote`,
+		},
+		{
+			name:   "trailing-backslash",
+			cmd:    `echo \`,
+			errMsg: `split command "echo \\": trailing backslash`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := testcontainers.GenericContainerRequest{}
+
+			err := testcontainers.WithCmdString(tt.cmd)(&req)
+			if tt.errMsg != "" {
+				require.EqualError(t, err, tt.errMsg)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, req.Cmd)
+		})
+	}
+}
+
+func TestWithReadinessExec(t *testing.T) {
+	t.Run("without-wait-strategy", func(t *testing.T) {
+		req := testcontainers.GenericContainerRequest{}
+
+		err := testcontainers.WithReadinessExec([]string{"pg_isready"}, func(s *wait.ExecStrategy) {
+			s.WithPollInterval(time.Second)
+		})(&req)
+		require.NoError(t, err)
+
+		strategy, ok := req.WaitingFor.(*wait.ExecStrategy)
+		require.True(t, ok)
+		require.Equal(t, time.Second, strategy.PollInterval)
+	})
+
+	t.Run("with-wait-strategy", func(t *testing.T) {
+		logStrategy := wait.ForLog("ready")
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				WaitingFor: logStrategy,
+			},
+		}
+
+		err := testcontainers.WithReadinessExec([]string{"pg_isready"})(&req)
+		require.NoError(t, err)
+
+		strategy, ok := req.WaitingFor.(*wait.MultiStrategy)
+		require.True(t, ok)
+		require.Len(t, strategy.Strategies, 2)
+		require.Equal(t, logStrategy, strategy.Strategies[0])
+		require.IsType(t, &wait.ExecStrategy{}, strategy.Strategies[1])
+	})
+
+	t.Run("container", func(t *testing.T) {
+		ctx := context.Background()
+
+		// a module running a process which only becomes ready after a while
+		runModule := func(ctx context.Context, opts ...testcontainers.ContainerCustomizer) (testcontainers.Container, error) {
+			req := testcontainers.GenericContainerRequest{
+				ContainerRequest: testcontainers.ContainerRequest{
+					Image: "docker.io/alpine",
+					Cmd:   []string{"sh", "-c", "sleep 2; touch /tmp/ready; sleep 60"},
+				},
+				Started: true,
+			}
+
+			opts = append([]testcontainers.ContainerCustomizer{
+				testcontainers.WithReadinessExec([]string{"test", "-f", "/tmp/ready"}),
+			}, opts...)
+			for _, opt := range opts {
+				if err := opt.Customize(&req); err != nil {
+					return nil, err
+				}
+			}
+
+			return testcontainers.GenericContainer(ctx, req)
+		}
+
+		ctr, err := runModule(ctx)
+		terminateContainerOnEnd(t, ctx, ctr)
+		require.NoError(t, err)
+
+		// the command exits with code 0 once the container is ready
+		code, _, err := ctr.Exec(ctx, []string{"test", "-f", "/tmp/ready"})
+		require.NoError(t, err)
+		require.Zero(t, code)
+	})
+}
+
+func TestWithAutoExposeImagePorts(t *testing.T) {
+	ctx := context.Background()
+
+	req := testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        nginxAlpineImage, // alpine image does expose port 80
+			ExposedPorts: []string{"8080/tcp"},
+		},
+		Started: true,
+	}
+
+	require.NoError(t, testcontainers.WithAutoExposeImagePorts()(&req))
+
+	c, err := testcontainers.GenericContainer(ctx, req)
+	terminateContainerOnEnd(t, ctx, c)
+	require.NoError(t, err)
+
+	ports, err := c.Ports(ctx)
+	require.NoError(t, err)
+	require.Contains(t, ports, nat.Port("8080/tcp"))
+	require.Contains(t, ports, nat.Port(nginxDefaultPort))
+
+	port, err := c.MappedPort(ctx, nginxDefaultPort)
+	require.NoError(t, err)
+	require.NotEmpty(t, port.Port())
+}
+
+func TestWithSecurityOpts(t *testing.T) {
+	t.Run("seccomp-profile", func(t *testing.T) {
+		profilePath := filepath.Join(t.TempDir(), "seccomp.json")
+		err := os.WriteFile(profilePath, []byte(`{
+	"defaultAction": "SCMP_ACT_ALLOW",
+	"syscalls": []
+}`), 0o644)
+		require.NoError(t, err)
+
+		req := &testcontainers.GenericContainerRequest{}
+		require.NoError(t, testcontainers.WithSeccompProfile(profilePath)(req))
+		require.Equal(t, []string{`seccomp={"defaultAction":"SCMP_ACT_ALLOW","syscalls":[]}`}, req.SecurityOpts)
+	})
+
+	t.Run("invalid-seccomp-profile", func(t *testing.T) {
+		profilePath := filepath.Join(t.TempDir(), "seccomp.json")
+		require.NoError(t, os.WriteFile(profilePath, []byte(`{"defaultAction":`), 0o644))
+
+		req := &testcontainers.GenericContainerRequest{}
+		require.ErrorContains(t, testcontainers.WithSeccompProfile(profilePath)(req), "invalid seccomp profile")
+		require.ErrorContains(t, testcontainers.WithSeccompProfile(filepath.Join(t.TempDir(), "missing.json"))(req), "read seccomp profile")
+		require.Empty(t, req.SecurityOpts)
+	})
+
+	t.Run("apparmor-profile", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+		require.NoError(t, testcontainers.WithApparmorProfile("docker-default")(req))
+		require.Equal(t, []string{"apparmor=docker-default"}, req.SecurityOpts)
+	})
+
+	t.Run("no-new-privileges", func(t *testing.T) {
+		req := testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "alpine",
+				Entrypoint: []string{"tail", "-f", "/dev/null"},
+			},
+			Started: true,
+		}
+		require.NoError(t, testcontainers.WithNoNewPrivileges()(&req))
+		require.Equal(t, []string{"no-new-privileges"}, req.SecurityOpts)
+
+		c, err := testcontainers.GenericContainer(context.Background(), req)
+		require.NoError(t, err)
+		defer func() {
+			err = c.Terminate(context.Background())
+			require.NoError(t, err)
+		}()
+
+		// the kernel reports the processes which cannot gain new privileges, e.g. running setuid binaries
+		_, reader, err := c.Exec(context.Background(), []string{"grep", "NoNewPrivs", "/proc/self/status"}, exec.Multiplexed())
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "NoNewPrivs:\t1\n", string(content))
+	})
+}
+
+func TestWithTmpfsMount(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		req := &testcontainers.GenericContainerRequest{}
+
+		opts := testcontainers.TmpfsOptions{SizeBytes: 64 * 1024 * 1024, Mode: 0o1777}
+		require.NoError(t, testcontainers.WithTmpfsMount("/scratch", opts)(req))
+
+		mounts := req.Mounts.PrepareMounts()
+		require.Len(t, mounts, 1)
+		assert.Equal(t, mount.TypeTmpfs, mounts[0].Type)
+		assert.Equal(t, "/scratch", mounts[0].Target)
+		require.NotNil(t, mounts[0].TmpfsOptions)
+		assert.Equal(t, int64(64*1024*1024), mounts[0].TmpfsOptions.SizeBytes)
+		assert.Equal(t, os.FileMode(0o1777), mounts[0].TmpfsOptions.Mode)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			target string
+			opts   testcontainers.TmpfsOptions
+			errMsg string
+		}{
+			{name: "relative-target", target: "scratch", errMsg: "must be an absolute path"},
+			{name: "negative-size", target: "/scratch", opts: testcontainers.TmpfsOptions{SizeBytes: -1}, errMsg: "must not be negative"},
+			{name: "invalid-mode", target: "/scratch", opts: testcontainers.TmpfsOptions{Mode: os.ModeDir | 0o755}, errMsg: "must be at most 07777"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				req := &testcontainers.GenericContainerRequest{}
+
+				err := testcontainers.WithTmpfsMount(tt.target, tt.opts)(req)
+				require.ErrorContains(t, err, tt.errMsg)
+				require.Empty(t, req.Mounts)
+			})
+		}
+	})
+}
+
+func TestExclusiveOption(t *testing.T) {
+	noop := func(req *testcontainers.GenericContainerRequest) error {
+		return nil
+	}
+
+	optA := testcontainers.ExclusiveOption("a", []string{"b"}, noop)
+	optB := testcontainers.ExclusiveOption("b", nil, noop)
+	optC := testcontainers.ExclusiveOption("c", []string{"c"}, noop)
+
+	tests := []struct {
+		name    string
+		opts    []testcontainers.CustomizeRequestOption
+		wantErr string
+	}{
+		{name: "no conflicts", opts: []testcontainers.CustomizeRequestOption{optA, optC}},
+		{name: "declared conflict", opts: []testcontainers.CustomizeRequestOption{optB, optA}, wantErr: "conflicting options: a cannot be combined with b"},
+		{name: "reverse order", opts: []testcontainers.CustomizeRequestOption{optA, optB}, wantErr: "conflicting options: b cannot be combined with a"},
+		{name: "applied twice", opts: []testcontainers.CustomizeRequestOption{optC, optC}, wantErr: "conflicting options: c cannot be applied more than once"},
+		{name: "applied twice without conflict", opts: []testcontainers.CustomizeRequestOption{optB, optB}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &testcontainers.GenericContainerRequest{}
+
+			var err error
+			for _, opt := range tt.opts {
+				if err = opt(req); err != nil {
+					break
+				}
+			}
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, testcontainers.ErrConflictingOptions)
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
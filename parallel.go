@@ -2,6 +2,7 @@ package testcontainers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 )
@@ -119,3 +120,105 @@ func ParallelContainers(ctx context.Context, reqs ParallelContainerRequest, opt
 
 	return containers, nil
 }
+
+// StartAllOption is a functional option to configure how StartAll starts the containers.
+type StartAllOption func(*startAllOptions)
+
+// startAllOptions holds the configuration applied by StartAll.
+type startAllOptions struct {
+	parallelism int
+	failFast    bool
+}
+
+// WithParallelism sets the maximum number of containers created and started at the same time
+// by StartAll. If it's zero or negative, the default value, 8, is used.
+func WithParallelism(n int) StartAllOption {
+	return func(o *startAllOptions) {
+		o.parallelism = n
+	}
+}
+
+// WithFailFast makes StartAll stop starting containers as soon as one of them fails,
+// terminating the ones that were successfully started.
+func WithFailFast() StartAllOption {
+	return func(o *startAllOptions) {
+		o.failFast = true
+	}
+}
+
+// StartAll creates and starts the containers for the given requests, with bounded parallelism.
+// It returns the successfully started containers, in the order of the requests, and the
+// failures joined into a single error. If the WithFailFast option is set, the containers
+// pending to start are cancelled on the first failure, the successfully started ones are
+// terminated, and no containers are returned.
+// Containers that fail to start after being created are terminated, as they are not returned.
+func StartAll(ctx context.Context, reqs []GenericContainerRequest, opts ...StartAllOption) ([]Container, error) {
+	options := startAllOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.parallelism <= 0 {
+		options.parallelism = defaultWorkersCount
+	}
+
+	startCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]Container, len(reqs))
+	errs := make([]error, len(reqs))
+	sem := make(chan struct{}, options.parallelism)
+
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(i int, req GenericContainerRequest) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-startCtx.Done():
+				errs[i] = fmt.Errorf("start container %d (%s): %w", i, req.Image, startCtx.Err())
+				return
+			}
+
+			req.Started = true
+			c, err := GenericContainer(startCtx, req)
+			if err != nil {
+				errs[i] = fmt.Errorf("start container %d (%s): %w", i, req.Image, err)
+				if c != nil {
+					// the container is not returned, so terminate it right away
+					_ = c.Terminate(context.WithoutCancel(ctx))
+				}
+				if options.failFast {
+					cancel()
+				}
+				return
+			}
+
+			results[i] = c
+		}(i, req)
+	}
+	wg.Wait()
+
+	containers := make([]Container, 0, len(reqs))
+	for _, c := range results {
+		if c != nil {
+			containers = append(containers, c)
+		}
+	}
+
+	err := errors.Join(errs...)
+	if err == nil || !options.failFast {
+		return containers, err
+	}
+
+	for _, c := range containers {
+		if termErr := c.Terminate(context.WithoutCancel(ctx)); termErr != nil {
+			err = errors.Join(err, fmt.Errorf("terminate container %s: %w", c.GetContainerID(), termErr))
+		}
+	}
+
+	return nil, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -165,3 +166,79 @@ func TestParallelContainersWithReuse(t *testing.T) {
 	// Container is reused, only terminate first container
 	terminateContainerOnEnd(t, ctx, res[0])
 }
+
+func TestStartAll(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("bounded-parallelism", func(t *testing.T) {
+		var mu sync.Mutex
+		var active, maxActive int
+
+		// track the number of containers being created at the same time
+		trackingHook := ContainerLifecycleHooks{
+			PreCreates: []ContainerRequestHook{
+				func(ctx context.Context, req ContainerRequest) error {
+					mu.Lock()
+					active++
+					if active > maxActive {
+						maxActive = active
+					}
+					mu.Unlock()
+
+					time.Sleep(500 * time.Millisecond)
+
+					mu.Lock()
+					active--
+					mu.Unlock()
+					return nil
+				},
+			},
+		}
+
+		reqs := make([]GenericContainerRequest, 5)
+		for i := range reqs {
+			reqs[i] = GenericContainerRequest{
+				ContainerRequest: ContainerRequest{
+					Image:          nginxAlpineImage,
+					ExposedPorts:   []string{nginxDefaultPort},
+					WaitingFor:     wait.ForListeningPort(nginxDefaultPort),
+					LifecycleHooks: []ContainerLifecycleHooks{trackingHook},
+				},
+			}
+		}
+
+		containers, err := StartAll(ctx, reqs, WithParallelism(2))
+		for _, c := range containers {
+			terminateContainerOnEnd(t, ctx, c)
+		}
+		require.NoError(t, err)
+		require.Len(t, containers, 5)
+		require.Equal(t, 2, maxActive)
+
+		for _, c := range containers {
+			state, err := c.State(ctx)
+			require.NoError(t, err)
+			require.True(t, state.Running)
+		}
+	})
+
+	reqs := []GenericContainerRequest{
+		{ContainerRequest: ContainerRequest{Image: nginxAlpineImage}},
+		{ContainerRequest: ContainerRequest{Image: "bad bad bad"}},
+	}
+
+	t.Run("collect-errors", func(t *testing.T) {
+		containers, err := StartAll(ctx, reqs)
+		for _, c := range containers {
+			terminateContainerOnEnd(t, ctx, c)
+		}
+		require.ErrorContains(t, err, "start container 1 (bad bad bad)")
+		require.Len(t, containers, 1)
+	})
+
+	t.Run("fail-fast", func(t *testing.T) {
+		containers, err := StartAll(ctx, reqs, WithFailFast())
+		require.ErrorContains(t, err, "start container 1 (bad bad bad)")
+		require.Empty(t, containers)
+	})
+}
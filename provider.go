@@ -39,6 +39,11 @@ type (
 	// DockerProviderOptions defines options applicable to DockerProvider
 	DockerProviderOptions struct {
 		defaultBridgeNetworkName string
+		reaperNetwork            string
+		maxErrorLogBytes         int
+		reaperImage              string
+		reaperLabels             map[string]string
+		reaperPrivileged         *bool
 		*GenericProviderOptions
 	}
 
@@ -78,6 +83,71 @@ func WithDefaultBridgeNetwork(bridgeNetworkName string) DockerProviderOption {
 	})
 }
 
+// WithReaperNetwork attaches the reaper to the given network, in addition to the default network
+// of the provider, so it shares the network with the containers it manages, e.g. when they are
+// isolated in that network. If the reaper of the test session is already running, it's connected
+// to the network. The containers created by the provider are attached to the network too, unless
+// their network mode does not allow it, e.g. "host", "none" or "container:<id>". The network must
+// exist, and removing it with Network.Remove detaches the reaper from it first.
+func WithReaperNetwork(networkName string) DockerProviderOption {
+	return DockerProviderOptionFunc(func(opts *DockerProviderOptions) {
+		opts.reaperNetwork = networkName
+	})
+}
+
+// SetGlobalMaxConcurrentPulls limits the number of images pulled at the same time by all the Docker
+// providers of the process, so starting many containers at once does not overwhelm the Docker daemon
+// or the registry. It's a global setting, as each call to GenericContainer creates its own provider,
+// so it's meant to be called once, e.g. in TestMain, before starting any container.
+// Zero or a negative number removes the limit, which is the default.
+// Concurrent pulls of the same image are always coalesced into a single pull.
+func SetGlobalMaxConcurrentPulls(n int) {
+	defaultImagePuller.setMaxConcurrentPulls(n)
+}
+
+// WithMaxErrorLogBytes limits the size of the logs kept in the LogsError returned, and printed,
+// when a container created by the provider fails to start, keeping the last bytes of the logs,
+// so a container writing a lot of logs before failing does not exhaust the memory of the tests.
+// Only the kept size is limited: the whole logs are still read from the Docker engine.
+// It takes precedence over the error.logs.max.bytes property. The default is 1MB, zero keeps
+// the property or the default, and a negative number removes the limit.
+func WithMaxErrorLogBytes(n int) DockerProviderOption {
+	return DockerProviderOptionFunc(func(opts *DockerProviderOptions) {
+		opts.maxErrorLogBytes = n
+	})
+}
+
+// WithReaperImage sets the image of the reaper (Ryuk) container started by the provider,
+// e.g. a copy of the default image in a private registry. The image must expose the
+// port 8080/tcp, where Ryuk listens. It takes precedence over the ryuk.container.image property.
+// The reaper is shared by the whole test session, and it's only started by the first provider
+// needing it, e.g. the one created by GenericContainer without this option, so the property is
+// the reliable way to set the image. A warning is logged if the running reaper uses another image.
+func WithReaperImage(image string) DockerProviderOption {
+	return DockerProviderOptionFunc(func(opts *DockerProviderOptions) {
+		opts.reaperImage = image
+	})
+}
+
+// WithReaperLabels adds labels to the reaper (Ryuk) container started by the provider.
+// They are merged with the labels of the ryuk.container.labels property, taking precedence
+// over them, but they cannot override the labels used by Testcontainers. As with WithReaperImage,
+// they are only applied if the provider is the one starting the reaper of the test session.
+func WithReaperLabels(labels map[string]string) DockerProviderOption {
+	return DockerProviderOptionFunc(func(opts *DockerProviderOptions) {
+		opts.reaperLabels = labels
+	})
+}
+
+// WithReaperPrivileged sets whether the reaper (Ryuk) container started by the provider runs
+// privileged. It takes precedence over the ryuk.container.privileged property. As with WithReaperImage,
+// it's only applied if the provider is the one starting the reaper of the test session.
+func WithReaperPrivileged(privileged bool) DockerProviderOption {
+	return DockerProviderOptionFunc(func(opts *DockerProviderOptions) {
+		opts.reaperPrivileged = &privileged
+	})
+}
+
 func (f GenericProviderOptionFunc) ApplyGenericTo(opts *GenericProviderOptions) {
 	f(opts)
 }
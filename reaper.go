@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"maps"
 	"math/rand"
 	"net"
 	"strings"
@@ -154,6 +155,10 @@ func reuseOrCreateReaper(ctx context.Context, sessionID string, provider ReaperP
 				return nil, err
 			}
 		} else if state.Running {
+			if err := connectReaperToNetwork(ctx, provider, reaperInstance); err != nil {
+				return nil, err
+			}
+			warnReaperOptionsIgnored(ctx, provider, reaperInstance)
 			return reaperInstance, nil
 		}
 		// else: the reaper instance has been terminated, so we need to create a new one
@@ -173,6 +178,11 @@ func reuseOrCreateReaper(ctx context.Context, sessionID string, provider ReaperP
 			return nil, err
 		}
 
+		if err := connectReaperToNetwork(ctx, provider, reaperInstance); err != nil {
+			return nil, err
+		}
+		warnReaperOptionsIgnored(ctx, provider, reaperInstance)
+
 		return reaperInstance, nil
 	}
 
@@ -196,6 +206,66 @@ func reuseOrCreateReaper(ctx context.Context, sessionID string, provider ReaperP
 	return reaperInstance, nil
 }
 
+// warnReaperOptionsIgnored logs a warning if the provider sets the image, the privileged mode or the labels
+// of the reaper, but the reaper of the test session is already running with a different configuration,
+// as it's shared by all the providers of the session, and only the one creating it applies its options.
+func warnReaperOptionsIgnored(ctx context.Context, provider ReaperProvider, reaper *Reaper) {
+	p, ok := provider.(*DockerProvider)
+	if !ok || (p.reaperImage == "" && p.reaperPrivileged == nil && len(p.reaperLabels) == 0) {
+		return
+	}
+
+	inspect, err := reaper.container.Inspect(ctx)
+	if err != nil || inspect.Config == nil || inspect.HostConfig == nil {
+		return
+	}
+
+	var ignored []string
+	if p.reaperImage != "" && p.reaperImage != inspect.Config.Image {
+		ignored = append(ignored, fmt.Sprintf("image %s (running %s)", p.reaperImage, inspect.Config.Image))
+	}
+	if p.reaperPrivileged != nil && *p.reaperPrivileged != inspect.HostConfig.Privileged {
+		ignored = append(ignored, fmt.Sprintf("privileged %t (running %t)", *p.reaperPrivileged, inspect.HostConfig.Privileged))
+	}
+	for k, v := range p.reaperLabels {
+		if inspect.Config.Labels[k] != v {
+			ignored = append(ignored, "labels")
+			break
+		}
+	}
+
+	if len(ignored) > 0 {
+		Logger.Printf("⚠️ The reaper of the test session %s is already running, so the reaper options of the provider are ignored: %s",
+			reaper.SessionID, strings.Join(ignored, ", "))
+	}
+}
+
+// connectReaperToNetwork connects the already running reaper to the reaper network of the provider,
+// if any, as it could have been created by a provider using a different network.
+func connectReaperToNetwork(ctx context.Context, provider ReaperProvider, reaper *Reaper) error {
+	p, ok := provider.(*DockerProvider)
+	if !ok || p.reaperNetwork == "" {
+		return nil
+	}
+
+	inspect, err := p.client.ContainerInspect(ctx, reaper.container.GetContainerID())
+	if err != nil {
+		return fmt.Errorf("inspect reaper container: %w", err)
+	}
+
+	if inspect.NetworkSettings != nil {
+		if _, ok := inspect.NetworkSettings.Networks[p.reaperNetwork]; ok {
+			return nil
+		}
+	}
+
+	if err := p.client.NetworkConnect(ctx, p.reaperNetwork, inspect.ID, nil); err != nil {
+		return fmt.Errorf("connect reaper to network %s: %w", p.reaperNetwork, err)
+	}
+
+	return nil
+}
+
 // reuseReaperContainer constructs a Reaper from an already running reaper
 // DockerContainer.
 func reuseReaperContainer(ctx context.Context, sessionID string, provider ReaperProvider, reaperContainer *DockerContainer) (*Reaper, error) {
@@ -232,6 +302,52 @@ func reuseReaperContainer(ctx context.Context, sessionID string, provider Reaper
 	}, nil
 }
 
+// parseReaperLabels parses the labels of the reaper container from the ryuk.container.labels
+// property, a comma-separated list of key=value pairs, e.g. "team=qa,cost-center=42".
+func parseReaperLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("invalid reaper label %q: expected key=value", pair)
+		}
+
+		labels[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	return labels, nil
+}
+
+// validateReaperImage checks that a custom image of the reaper exposes the port where Ryuk
+// listens. Images without any exposed port are accepted, as the port is exposed by the request.
+func (p *DockerProvider) validateReaperImage(ctx context.Context, image string, port nat.Port) error {
+	imageName, err := newPrependHubRegistry(p.Config().Config.HubImageNamePrefix).Substitute(image)
+	if err != nil {
+		return fmt.Errorf("substitute reaper image %s: %w", image, err)
+	}
+
+	inspect, _, err := p.client.ImageInspectWithRaw(ctx, imageName)
+	if err != nil {
+		return fmt.Errorf("inspect reaper image %s: %w", imageName, err)
+	}
+
+	if inspect.Config == nil || len(inspect.Config.ExposedPorts) == 0 {
+		return nil
+	}
+
+	if _, ok := inspect.Config.ExposedPorts[port]; !ok {
+		return fmt.Errorf("reaper image %s does not expose the port %s", imageName, port)
+	}
+
+	return nil
+}
+
 // newReaper creates a Reaper with a sessionID to identify containers and a
 // provider to use. Do not call this directly, use reuseOrCreateReaper instead.
 func newReaper(ctx context.Context, sessionID string, provider ReaperProvider) (*Reaper, error) {
@@ -246,11 +362,37 @@ func newReaper(ctx context.Context, sessionID string, provider ReaperProvider) (
 
 	tcConfig := provider.Config().Config
 
+	// the options of the provider take precedence over the configuration,
+	// where the environment variables take precedence over the properties file
+	image := config.ReaperDefaultImage
+	if tcConfig.RyukImage != "" {
+		image = tcConfig.RyukImage
+	}
+	privileged := tcConfig.RyukPrivileged
+	labels, err := parseReaperLabels(tcConfig.RyukLabels)
+	if err != nil {
+		return nil, err
+	}
+
+	p, isDockerProvider := provider.(*DockerProvider)
+	if isDockerProvider {
+		if p.reaperImage != "" {
+			image = p.reaperImage
+		}
+		if p.reaperPrivileged != nil {
+			privileged = *p.reaperPrivileged
+		}
+		maps.Copy(labels, p.reaperLabels)
+	}
+
+	// the labels used by Testcontainers cannot be overridden
+	maps.Copy(labels, core.DefaultLabels(sessionID))
+
 	req := ContainerRequest{
-		Image:        config.ReaperDefaultImage,
+		Image:        image,
 		ExposedPorts: []string{string(listeningPort)},
-		Labels:       core.DefaultLabels(sessionID),
-		Privileged:   tcConfig.RyukPrivileged,
+		Labels:       labels,
+		Privileged:   privileged,
 		WaitingFor:   wait.ForListeningPort(listeningPort),
 		Name:         reaperContainerNameFromSessionID(sessionID),
 		HostConfigModifier: func(hc *container.HostConfig) {
@@ -274,9 +416,24 @@ func newReaper(ctx context.Context, sessionID string, provider ReaperProvider) (
 	req.Labels[core.LabelReaper] = "true"
 	req.Labels[core.LabelRyuk] = "true"
 
-	// Attach reaper container to a requested network if it is specified
-	if p, ok := provider.(*DockerProvider); ok {
+	if isDockerProvider {
+		// Attach reaper container to a requested network if it is specified
 		req.Networks = append(req.Networks, p.DefaultNetwork)
+
+		if p.reaperNetwork != "" && p.reaperNetwork != p.DefaultNetwork {
+			req.Networks = append(req.Networks, p.reaperNetwork)
+		}
+
+		if image != config.ReaperDefaultImage {
+			// the image is pulled before the pre-create hooks are called
+			req.LifecycleHooks = []ContainerLifecycleHooks{{
+				PreCreates: []ContainerRequestHook{
+					func(ctx context.Context, req ContainerRequest) error {
+						return p.validateReaperImage(ctx, req.Image, listeningPort)
+					},
+				},
+			}}
+		}
 	}
 
 	c, err := provider.RunContainer(ctx, req)
@@ -11,6 +11,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -372,6 +373,20 @@ func Test_NewReaper(t *testing.T) {
 				RyukVerbose:    true,
 			}},
 		},
+		{
+			name: "custom image and labels",
+			req: createContainerRequest(func(req ContainerRequest) ContainerRequest {
+				req.Image = "registry.mycompany.com/ryuk:0.7.0"
+				req.Labels["team"] = "qa"
+				return req
+			}),
+			config: TestcontainersConfig{Config: config.Config{
+				RyukImage:               "registry.mycompany.com/ryuk:0.7.0",
+				RyukLabels:              "team=qa," + core.LabelReaper + "=false",
+				RyukConnectionTimeout:   time.Minute,
+				RyukReconnectionTimeout: 10 * time.Second,
+			}},
+		},
 		{
 			name: "docker-host in context",
 			req: createContainerRequest(func(req ContainerRequest) ContainerRequest {
@@ -637,3 +652,94 @@ func TestReaper_ReuseRunning(t *testing.T) {
 		assert.Equal(t, firstContainerID, containerID, "call %d should have returned same container id", i)
 	}
 }
+
+func TestReaper_WithReaperNetwork(t *testing.T) {
+	config.Reset() // reset the config using the internal method to avoid the sync.Once
+	tcConfig := config.Read()
+	if tcConfig.RyukDisabled {
+		t.Skip("Ryuk is disabled, skipping test")
+	}
+
+	ctx := context.Background()
+
+	networkName := "reaper-network-" + uuid.NewString()
+
+	cli, err := NewDockerClientWithOpts(ctx)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	provider, err := NewDockerProvider(WithReaperNetwork(networkName))
+	require.NoError(t, err)
+	defer provider.Close()
+
+	nw, err := provider.CreateNetwork(ctx, NetworkRequest{Name: networkName})
+	require.NoError(t, err)
+
+	ctr, err := provider.CreateContainer(ctx, ContainerRequest{
+		Image: nginxAlpineImage,
+	})
+	require.NoError(t, err)
+
+	dc := ctr.(*DockerContainer)
+	require.NotNil(t, dc.terminationSignal, "the reaper should be enabled")
+
+	inspect, err := cli.ContainerInspect(ctx, dc.ID)
+	require.NoError(t, err)
+	require.Contains(t, inspect.NetworkSettings.Networks, networkName)
+
+	reaperContainer, err := lookUpReaperContainer(ctx, SessionID())
+	require.NoError(t, err)
+
+	inspect, err = cli.ContainerInspect(ctx, reaperContainer.GetContainerID())
+	require.NoError(t, err)
+	require.Contains(t, inspect.NetworkSettings.Networks, networkName)
+
+	t.Run("network-mode-none", func(t *testing.T) {
+		// containers not allowed to join other networks are not attached to the reaper network
+		ctr, err := provider.CreateContainer(ctx, ContainerRequest{
+			Image: nginxAlpineImage,
+			HostConfigModifier: func(hc *container.HostConfig) {
+				hc.NetworkMode = "none"
+			},
+		})
+		require.NoError(t, err)
+		terminateContainerOnEnd(t, ctx, ctr)
+
+		inspect, err := cli.ContainerInspect(ctx, ctr.GetContainerID())
+		require.NoError(t, err)
+		require.NotContains(t, inspect.NetworkSettings.Networks, networkName)
+	})
+
+	// the reaper outlives the network, so it's detached from it when the network is removed
+	require.NoError(t, ctr.Terminate(ctx))
+	require.NoError(t, nw.Remove(ctx))
+
+	inspect, err = cli.ContainerInspect(ctx, reaperContainer.GetContainerID())
+	require.NoError(t, err)
+	require.NotContains(t, inspect.NetworkSettings.Networks, networkName)
+}
+
+func TestAllowsExtraNetworks(t *testing.T) {
+	require.True(t, allowsExtraNetworks(""))
+	require.True(t, allowsExtraNetworks("bridge"))
+	require.True(t, allowsExtraNetworks("my-network"))
+	require.False(t, allowsExtraNetworks("host"))
+	require.False(t, allowsExtraNetworks("none"))
+	require.False(t, allowsExtraNetworks("container:1234"))
+}
+
+func TestParseReaperLabels(t *testing.T) {
+	labels, err := parseReaperLabels(" team = qa ,cost-center=42,,empty=")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "qa", "cost-center": "42", "empty": ""}, labels)
+
+	labels, err = parseReaperLabels("")
+	require.NoError(t, err)
+	require.Empty(t, labels)
+
+	_, err = parseReaperLabels("team")
+	require.EqualError(t, err, `invalid reaper label "team": expected key=value`)
+
+	_, err = parseReaperLabels("=qa")
+	require.EqualError(t, err, `invalid reaper label "=qa": expected key=value`)
+}
@@ -0,0 +1,122 @@
+package testcontainers
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// DiffRequests returns the differences between two container requests, one per line, e.g. to assert
+// in a test that a customizer only changes the intended fields, or to debug the precedence of stacked
+// customizers. The fields are compared recursively, including the elements of slices and the keys of
+// maps, and reported with their path:
+//
+//   - `Image: "redis:6" -> "redis:7"` for a changed value
+//   - `Env["FOO"]: added "bar"` for an added map entry or slice element
+//   - `Cmd[1]: removed "--verbose"` for a removed map entry or slice element
+//
+// The unexported fields are not compared, and functions, like the modifiers, are compared by their code,
+// so two closures created by the same function are considered equal. A nil slice or map and an empty one are equal.
+func DiffRequests(before, after ContainerRequest) []string {
+	var diffs []string
+	diffValues("", reflect.ValueOf(before), reflect.ValueOf(after), &diffs)
+	return diffs
+}
+
+// diffValues appends the differences between two values of the same type, at the given path, to the diffs.
+func diffValues(path string, before, after reflect.Value, diffs *[]string) {
+	switch before.Kind() {
+	case reflect.Struct:
+		t := before.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			diffValues(fieldPath, before.Field(i), after.Field(i), diffs)
+		}
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, k := range before.MapKeys() {
+			keys[fmt.Sprintf("%#v", k.Interface())] = k
+		}
+		for _, k := range after.MapKeys() {
+			keys[fmt.Sprintf("%#v", k.Interface())] = k
+		}
+
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			k := keys[name]
+			keyPath := path + "[" + name + "]"
+			b, a := before.MapIndex(k), after.MapIndex(k)
+			switch {
+			case !a.IsValid():
+				*diffs = append(*diffs, keyPath+": removed "+formatDiffValue(b))
+			case !b.IsValid():
+				*diffs = append(*diffs, keyPath+": added "+formatDiffValue(a))
+			default:
+				diffValues(keyPath, b, a, diffs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < max(before.Len(), after.Len()); i++ {
+			indexPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= after.Len():
+				*diffs = append(*diffs, indexPath+": removed "+formatDiffValue(before.Index(i)))
+			case i >= before.Len():
+				*diffs = append(*diffs, indexPath+": added "+formatDiffValue(after.Index(i)))
+			default:
+				diffValues(indexPath, before.Index(i), after.Index(i), diffs)
+			}
+		}
+	case reflect.Pointer, reflect.Interface:
+		switch {
+		case before.IsNil() && after.IsNil():
+		case before.IsNil() || after.IsNil():
+			*diffs = append(*diffs, path+": "+formatDiffValue(before)+" -> "+formatDiffValue(after))
+		case before.Elem().Type() != after.Elem().Type():
+			// the values of different types are not comparable, so only their types are reported
+			*diffs = append(*diffs, fmt.Sprintf("%s: %T -> %T", path, before.Interface(), after.Interface()))
+		case before.Kind() == reflect.Pointer && before.Pointer() == after.Pointer():
+		default:
+			diffValues(path, before.Elem(), after.Elem(), diffs)
+		}
+	case reflect.Func:
+		if before.IsNil() != after.IsNil() || (!before.IsNil() && before.Pointer() != after.Pointer()) {
+			*diffs = append(*diffs, path+": "+formatDiffValue(before)+" -> "+formatDiffValue(after))
+		}
+	case reflect.Chan, reflect.UnsafePointer:
+		if before.Pointer() != after.Pointer() {
+			*diffs = append(*diffs, path+": changed")
+		}
+	default:
+		if !before.Equal(after) {
+			*diffs = append(*diffs, path+": "+formatDiffValue(before)+" -> "+formatDiffValue(after))
+		}
+	}
+}
+
+// formatDiffValue formats a value for the diffs, in Go syntax, except for functions, which are only reported as set or not.
+func formatDiffValue(v reflect.Value) string {
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface || v.Kind() == reflect.Func) && v.IsNil() {
+		return "nil"
+	}
+
+	if v.Kind() == reflect.Func {
+		return "func"
+	}
+
+	return fmt.Sprintf("%#v", v.Interface())
+}
@@ -0,0 +1,71 @@
+package testcontainers
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestDiffRequests(t *testing.T) {
+	newRequest := func() ContainerRequest {
+		return ContainerRequest{
+			Image:        "redis:7",
+			Env:          map[string]string{"REDIS_ARGS": "--save 60 1"},
+			Cmd:          []string{"redis-server", "--verbose"},
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForListeningPort("6379/tcp"),
+			FromDockerfile: FromDockerfile{
+				BuildArgs: map[string]*string{},
+			},
+		}
+	}
+
+	t.Run("added-env", func(t *testing.T) {
+		before := newRequest()
+
+		req := GenericContainerRequest{ContainerRequest: newRequest()}
+		require.NoError(t, WithEnv(map[string]string{"FOO": "bar"})(&req))
+
+		require.Equal(t, []string{`Env["FOO"]: added "bar"`}, DiffRequests(before, req.ContainerRequest))
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		req := newRequest()
+		req.Labels = map[string]string{}
+
+		// an empty map is equal to a nil one
+		require.Empty(t, DiffRequests(newRequest(), req))
+	})
+
+	t.Run("changed-fields", func(t *testing.T) {
+		after := newRequest()
+		after.Image = "redis:6"
+		after.Env["REDIS_ARGS"] = "--save 30 1"
+		after.Cmd = after.Cmd[:1]
+		after.ExposedPorts = append(after.ExposedPorts, "16379/tcp")
+		after.FromDockerfile.Context = "testdata"
+		after.WaitingFor = wait.ForListeningPort("16379/tcp")
+		after.HostConfigModifier = func(hc *container.HostConfig) {}
+
+		// the differences are reported in the order of the fields
+		require.Equal(t, []string{
+			`FromDockerfile.Context: "" -> "testdata"`,
+			`Image: "redis:7" -> "redis:6"`,
+			`Env["REDIS_ARGS"]: "--save 60 1" -> "--save 30 1"`,
+			`ExposedPorts[1]: added "16379/tcp"`,
+			`Cmd[1]: removed "--verbose"`,
+			`WaitingFor.Port: "6379/tcp" -> "16379/tcp"`,
+			`HostConfigModifier: nil -> func`,
+		}, DiffRequests(newRequest(), after))
+	})
+
+	t.Run("different-types", func(t *testing.T) {
+		after := newRequest()
+		after.WaitingFor = wait.ForLog("Ready to accept connections")
+
+		require.Equal(t, []string{"WaitingFor: *wait.HostPortStrategy -> *wait.LogStrategy"}, DiffRequests(newRequest(), after))
+	})
+}
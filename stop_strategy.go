@@ -0,0 +1,27 @@
+package testcontainers
+
+import "context"
+
+// StopStrategy prepares a container to be stopped, e.g. draining its connections by hitting
+// an endpoint and waiting for the in-flight requests to complete. It's invoked when the
+// container is stopped with Stop, right before the Docker engine is requested to stop it.
+type StopStrategy interface {
+	// BeforeStop is called before the container is stopped. Returning an error aborts the stop.
+	BeforeStop(ctx context.Context, c Container) error
+}
+
+// StopStrategyFunc is an adapter to use an ordinary function as a StopStrategy.
+type StopStrategyFunc func(ctx context.Context, c Container) error
+
+// BeforeStop calls f(ctx, c).
+func (f StopStrategyFunc) BeforeStop(ctx context.Context, c Container) error {
+	return f(ctx, c)
+}
+
+// NopStopStrategy is the default stop strategy, which does nothing before stopping the container.
+type NopStopStrategy struct{}
+
+// BeforeStop does nothing.
+func (NopStopStrategy) BeforeStop(context.Context, Container) error {
+	return nil
+}
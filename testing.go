@@ -3,6 +3,9 @@ package testcontainers
 import (
 	"context"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -40,6 +43,26 @@ func SkipIfDockerDesktop(t *testing.T, ctx context.Context) {
 	}
 }
 
+// TempDir creates a temporary directory on the host, which is removed when the test and
+// all its subtests complete, so it can be populated with the files to be copied into a
+// container, e.g. using the WithFilesFromDir option.
+func TempDir(tb testing.TB) string {
+	tb.Helper()
+
+	dir, err := os.MkdirTemp("", "testcontainers-")
+	if err != nil {
+		tb.Fatalf("failed to create temporary directory: %s", err)
+	}
+
+	tb.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			tb.Errorf("failed to remove temporary directory %s: %s", dir, err)
+		}
+	})
+
+	return dir
+}
+
 // exampleLogConsumer {
 
 // StdoutLogConsumer is a LogConsumer that prints the log to stdout
@@ -51,3 +74,105 @@ func (lc *StdoutLogConsumer) Accept(l Log) {
 }
 
 // }
+
+// prefixingTestLogConsumer is a LogConsumer writing the logs to the test logger, prefixing each line.
+type prefixingTestLogConsumer struct {
+	tb     testing.TB
+	prefix string
+
+	// containerPrefix is true if the prefix is the name of the container the consumer follows,
+	// set once the production of the logs starts, guarded by mu
+	containerPrefix bool
+
+	// done is set once the test completes, as the test logger cannot be used after that, guarded by mu
+	done bool
+	mu   sync.Mutex
+}
+
+// PrefixingTestLogConsumer returns a LogConsumer writing each line of the logs of a container to the
+// test logger, prefixed with the given prefix, e.g. the name of the container, to identify it in the
+// output of the test. The lines written to stderr are also tagged with STDERR.
+// The logs received once the test and its cleanup functions complete are discarded.
+func PrefixingTestLogConsumer(tb testing.TB, prefix string) LogConsumer {
+	lc := &prefixingTestLogConsumer{tb: tb, prefix: prefix}
+
+	tb.Cleanup(func() {
+		lc.mu.Lock()
+		defer lc.mu.Unlock()
+
+		lc.done = true
+	})
+
+	return lc
+}
+
+// NewTestLogConsumer returns a LogConsumer writing each line of the logs of a container to the test
+// logger, prefixed with the name of the container, once it's registered in the LogConsumerConfig of
+// the container request. The lines written to stderr are also tagged with STDERR. A consumer should
+// only be registered for a single container, to identify it. It stops with the log production,
+// when the container is terminated, and the logs received once the test and its cleanup functions
+// complete are discarded.
+func NewTestLogConsumer(tb testing.TB) LogConsumer {
+	lc := PrefixingTestLogConsumer(tb, "container").(*prefixingTestLogConsumer)
+	lc.containerPrefix = true
+
+	return lc
+}
+
+// containerNamedLogConsumer is implemented by the log consumers needing the name of the container
+// they follow, which is set before the production of the logs starts.
+type containerNamedLogConsumer interface {
+	setContainerName(name string)
+}
+
+// setContainerName sets the name of the container as the prefix, if the consumer was created by NewTestLogConsumer.
+func (lc *prefixingTestLogConsumer) setContainerName(name string) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if lc.containerPrefix {
+		lc.prefix = name
+	}
+}
+
+// Accept writes each line of the log to the test logger, with the prefix.
+func (lc *prefixingTestLogConsumer) Accept(l Log) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if lc.done {
+		return
+	}
+
+	stream := ""
+	if l.LogType == StderrLog {
+		stream = " " + StderrLog
+	}
+
+	for _, line := range strings.Split(strings.TrimRight(string(l.Content), "\n"), "\n") {
+		lc.tb.Logf("[%s]%s %s", lc.prefix, stream, line)
+	}
+}
+
+// WithTestLogForwarding forwards the logs of the container to the test logger, using a
+// PrefixingTestLogConsumer prefixed with the name of the container, or its image if it has
+// no name. The logs are followed once the container is started, until it's terminated.
+func WithTestLogForwarding(tb testing.TB) CustomizeRequestOption {
+	return func(req *GenericContainerRequest) error {
+		prefix := req.Name
+		if prefix == "" {
+			prefix = req.Image
+		}
+		if prefix == "" {
+			prefix = "container"
+		}
+
+		if req.LogConsumerCfg == nil {
+			req.LogConsumerCfg = &LogConsumerConfig{}
+		}
+
+		req.LogConsumerCfg.Consumers = append(req.LogConsumerCfg.Consumers, PrefixingTestLogConsumer(tb, prefix))
+
+		return nil
+	}
+}
@@ -1,7 +1,158 @@
 package testcontainers
 
-import "testing"
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
 
 func ExampleSkipIfProviderIsNotHealthy() {
 	SkipIfProviderIsNotHealthy(&testing.T{})
 }
+
+func TestTempDir(t *testing.T) {
+	var dir string
+	t.Run("create", func(t *testing.T) {
+		dir = TempDir(t)
+
+		info, err := os.Stat(dir)
+		require.NoError(t, err)
+		require.True(t, info.IsDir())
+	})
+
+	// the directory is removed once the subtest completes
+	_, err := os.Stat(dir)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+// recordingTB is a testing.TB recording the logged lines and the cleanup functions.
+type recordingTB struct {
+	testing.TB
+
+	mu       sync.Mutex
+	lines    []string
+	cleanups []func()
+}
+
+func (tb *recordingTB) Logf(format string, args ...any) {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	tb.lines = append(tb.lines, fmt.Sprintf(format, args...))
+}
+
+// loggedLines returns a copy of the logged lines, which can be logged from other goroutines.
+func (tb *recordingTB) loggedLines() []string {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	return append([]string(nil), tb.lines...)
+}
+
+func (tb *recordingTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func TestPrefixingTestLogConsumer(t *testing.T) {
+	tb := &recordingTB{TB: t}
+
+	lc := PrefixingTestLogConsumer(tb, "nginx")
+	lc.Accept(Log{LogType: StdoutLog, Content: []byte("first line\nsecond line\n")})
+	lc.Accept(Log{LogType: StderrLog, Content: []byte("error line\n")})
+
+	require.Equal(t, []string{
+		"[nginx] first line",
+		"[nginx] second line",
+		"[nginx] STDERR error line",
+	}, tb.lines)
+
+	// the logs received once the test completes are discarded
+	require.Len(t, tb.cleanups, 1)
+	tb.cleanups[0]()
+	lc.Accept(Log{LogType: StdoutLog, Content: []byte("late line\n")})
+	require.Len(t, tb.lines, 3)
+}
+
+func TestWithTestLogForwarding(t *testing.T) {
+	tb := &recordingTB{TB: t}
+
+	req := GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			Image: "nginx:alpine",
+		},
+	}
+	require.NoError(t, WithTestLogForwarding(tb)(&req))
+	require.NotNil(t, req.LogConsumerCfg)
+	require.Len(t, req.LogConsumerCfg.Consumers, 1)
+
+	req.LogConsumerCfg.Consumers[0].Accept(Log{LogType: StdoutLog, Content: []byte("ready\n")})
+	require.Equal(t, []string{"[nginx:alpine] ready"}, tb.lines)
+
+	t.Run("name", func(t *testing.T) {
+		tb := &recordingTB{TB: t}
+
+		req := GenericContainerRequest{
+			ContainerRequest: ContainerRequest{
+				Image: "nginx:alpine",
+				Name:  "web",
+			},
+		}
+		require.NoError(t, WithTestLogForwarding(tb)(&req))
+
+		req.LogConsumerCfg.Consumers[0].Accept(Log{LogType: StdoutLog, Content: []byte("ready\n")})
+		require.Equal(t, []string{"[web] ready"}, tb.lines)
+	})
+}
+
+func TestNewTestLogConsumer(t *testing.T) {
+	tb := &recordingTB{TB: t}
+
+	lc := NewTestLogConsumer(tb)
+
+	// the name of the container is set before the production of the logs starts
+	lc.(containerNamedLogConsumer).setContainerName("db")
+	lc.Accept(Log{LogType: StderrLog, Content: []byte("starting\n")})
+	require.Equal(t, []string{"[db] STDERR starting"}, tb.lines)
+
+	t.Run("explicit-prefix", func(t *testing.T) {
+		tb := &recordingTB{TB: t}
+
+		// the prefix of a PrefixingTestLogConsumer is kept
+		lc := PrefixingTestLogConsumer(tb, "cache")
+		lc.(containerNamedLogConsumer).setContainerName("redis")
+		lc.Accept(Log{LogType: StdoutLog, Content: []byte("ready\n")})
+		require.Equal(t, []string{"[cache] ready"}, tb.lines)
+	})
+}
+
+func TestNewTestLogConsumer_container(t *testing.T) {
+	ctx := context.Background()
+	tb := &recordingTB{TB: t}
+
+	ctr, err := GenericContainer(ctx, GenericContainerRequest{
+		ContainerRequest: ContainerRequest{
+			Image:      "docker.io/alpine",
+			Name:       "test-log-consumer",
+			Cmd:        []string{"sh", "-c", "echo hello; sleep 60"},
+			WaitingFor: wait.ForLog("hello"),
+			LogConsumerCfg: &LogConsumerConfig{
+				Consumers: []LogConsumer{NewTestLogConsumer(tb)},
+			},
+		},
+		Started: true,
+	})
+	terminateContainerOnEnd(t, ctx, ctr)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		lines := tb.loggedLines()
+		return len(lines) > 0 && lines[0] == "[test-log-consumer] hello"
+	}, 10*time.Second, 100*time.Millisecond)
+}
@@ -61,6 +61,13 @@ func (ms *MultiStrategy) WaitUntilReady(ctx context.Context, target StrategyTarg
 		return fmt.Errorf("no wait strategy supplied")
 	}
 
+	// the strategies fail fast by themselves while polling, if the container stops running,
+	// so the container is not checked before running them, as some of them can be satisfied
+	// by a container that already exited, e.g. a log strategy reading the logs of a one-shot
+	// container. It's only checked once a strategy fails, to report the exit of the container
+	// instead of the error of the strategy, unless the container is expected to exit.
+	failFast := !expectsExit(ms)
+
 	for _, strategy := range ms.Strategies {
 		strategyCtx := ctx
 
@@ -74,9 +81,36 @@ func (ms *MultiStrategy) WaitUntilReady(ctx context.Context, target StrategyTarg
 
 		err := strategy.WaitUntilReady(strategyCtx, target)
 		if err != nil {
+			if failFast {
+				if exitErr := exitedError(ctx, target); exitErr != nil {
+					return exitErr
+				}
+			}
 			return err
 		}
 	}
 
 	return nil
 }
+
+// expectsExit returns true if the strategy, or any of its inner strategies, waits for the container to exit.
+func expectsExit(strategy Strategy) bool {
+	switch s := strategy.(type) {
+	case *ExitStrategy:
+		return true
+	case *MultiStrategy:
+		for _, inner := range s.Strategies {
+			if expectsExit(inner) {
+				return true
+			}
+		}
+	case *AndStrategy:
+		for _, inner := range s.Strategies {
+			if expectsExit(inner) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -7,6 +7,9 @@ import (
 	"io"
 	"testing"
 	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMultiStrategy_WaitUntilReady(t *testing.T) {
@@ -119,3 +122,62 @@ func TestMultiStrategy_WaitUntilReady(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiStrategy_FailFastOnExit(t *testing.T) {
+	logs := "booting\nfatal error\n"
+
+	// exitingTarget runs for the given number of state checks and then exits with code 3
+	exitingTarget := func(runningChecks int) *MockStrategyTarget {
+		checks := 0
+		return &MockStrategyTarget{
+			StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+				checks++
+				if checks <= runningChecks {
+					return &types.ContainerState{Running: true, Status: "running"}, nil
+				}
+				return &types.ContainerState{Status: "exited", ExitCode: 3}, nil
+			},
+			LogsImpl: func(_ context.Context) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader([]byte(logs))), nil
+			},
+		}
+	}
+
+	t.Run("exits during a strategy", func(t *testing.T) {
+		start := time.Now()
+		err := ForAll(ForLog("ready").WithStartupTimeout(30*time.Second)).
+			WaitUntilReady(context.Background(), exitingTarget(3))
+		require.ErrorContains(t, err, "container exited with code 3")
+		require.ErrorContains(t, err, "fatal error")
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("exited one-shot container", func(t *testing.T) {
+		// the container printed the log and exited before waiting for it
+		err := ForAll(ForLog("fatal error").WithStartupTimeout(time.Second)).
+			WaitUntilReady(context.Background(), exitingTarget(0))
+		require.NoError(t, err)
+	})
+
+	t.Run("exits between strategies", func(t *testing.T) {
+		// the container is not checked before each strategy, only the failing ones report its exit
+		called := false
+		err := ForAll(
+			ForNop(func(context.Context, StrategyTarget) error { return nil }),
+			ForNop(func(context.Context, StrategyTarget) error {
+				called = true
+				return errors.New("not ready")
+			}),
+		).WaitUntilReady(context.Background(), exitingTarget(0))
+		require.EqualError(t, err, "container exited with code 3, last container logs:\nbooting\nfatal error")
+		require.True(t, called, "the second strategy should be called")
+	})
+
+	t.Run("exit is expected", func(t *testing.T) {
+		err := ForAll(
+			ForExit().WithExitTimeout(time.Second),
+			ForNop(func(context.Context, StrategyTarget) error { return nil }),
+		).WaitUntilReady(context.Background(), exitingTarget(1))
+		require.NoError(t, err)
+	})
+}
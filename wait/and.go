@@ -0,0 +1,199 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*AndStrategy)(nil)
+	_ StrategyTimeout = (*AndStrategy)(nil)
+)
+
+// AndStrategy waits until all its strategies are satisfied at the same time.
+// As opposed to the MultiStrategy, which accepts each strategy once it passed once,
+// the AndStrategy evaluates all the strategies in rounds, and a round only succeeds
+// if every strategy passes and the container kept running, without restarting,
+// for the whole round. This is useful for services that could flap, e.g. logging
+// a ready message right before crashing and being restarted. The logs read by the
+// strategies are the ones of the current run of the container, since it started,
+// if the target supports it, as the DockerContainer does, so a ready message logged
+// before a restart is not matched again.
+type AndStrategy struct {
+	// all Strategies should have a startupTimeout to avoid waiting infinitely
+	timeout *time.Duration
+
+	// additional properties
+	Strategies   []Strategy
+	PollInterval time.Duration
+	// StableFor is the minimum time the container must have been running,
+	// without restarting, for the strategies to be satisfied.
+	StableFor time.Duration
+}
+
+// And is the default construction for the fluid interface.
+//
+// For Example:
+//
+//	wait.
+//		And(wait.ForLog("ready"), wait.ForListeningPort("8080/tcp")).
+//		WithStableFor(2 * time.Second)
+func And(strategies ...Strategy) *AndStrategy {
+	return &AndStrategy{
+		Strategies:   strategies,
+		PollInterval: defaultPollInterval(),
+	}
+}
+
+// WithStartupTimeout can be used to change the default startup timeout
+func (ws *AndStrategy) WithStartupTimeout(startupTimeout time.Duration) *AndStrategy {
+	ws.timeout = &startupTimeout
+	return ws
+}
+
+// WithPollInterval can be used to override the default polling interval of 100 milliseconds
+func (ws *AndStrategy) WithPollInterval(pollInterval time.Duration) *AndStrategy {
+	ws.PollInterval = pollInterval
+	return ws
+}
+
+// WithStableFor sets the minimum time the container must have been running,
+// without restarting, for the strategies to be satisfied. Default is zero.
+func (ws *AndStrategy) WithStableFor(stableFor time.Duration) *AndStrategy {
+	ws.StableFor = stableFor
+	return ws
+}
+
+func (ws *AndStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *AndStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	timeout := defaultStartupTimeout()
+	if ws.timeout != nil {
+		timeout = *ws.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if len(ws.Strategies) == 0 {
+		return fmt.Errorf("no wait strategy supplied")
+	}
+
+	// local time at which each run of the container, identified by its start time,
+	// was first seen, so the stability does not depend on the clock of the Docker host.
+	firstSeen := map[string]time.Time{}
+
+	var lastErr error
+	for {
+		startedAt, err := ws.round(ctx, target)
+		if err == nil {
+			if _, ok := firstSeen[startedAt]; !ok {
+				firstSeen[startedAt] = time.Now()
+			}
+
+			if time.Since(firstSeen[startedAt]) >= ws.StableFor {
+				return nil
+			}
+
+			err = fmt.Errorf("container not running for %s yet", ws.StableFor)
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ctx.Err(), lastErr)
+		case <-time.After(ws.PollInterval):
+		}
+	}
+}
+
+// round evaluates all the strategies once, returning the start time of the container
+// if all of them passed while the container was running and did not restart.
+func (ws *AndStrategy) round(ctx context.Context, target StrategyTarget) (string, error) {
+	before, err := runningState(ctx, target)
+	if err != nil {
+		return "", err
+	}
+
+	run := currentRunTarget{StrategyTarget: target, startedAt: before.StartedAt}
+	for _, strategy := range ws.Strategies {
+		if err := strategy.WaitUntilReady(ctx, run); err != nil {
+			return "", err
+		}
+	}
+
+	after, err := runningState(ctx, target)
+	if err != nil {
+		return "", err
+	}
+
+	if before.StartedAt != after.StartedAt {
+		return "", errors.New("container restarted while waiting")
+	}
+
+	return after.StartedAt, nil
+}
+
+// runningState returns the state of the container, or an error if it's not running.
+func runningState(ctx context.Context, target StrategyTarget) (*types.ContainerState, error) {
+	state, err := target.State(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !state.Running {
+		return nil, fmt.Errorf("container is not running: %s", state.Status)
+	}
+
+	return state, nil
+}
+
+// currentRunTarget scopes the logs of the target to the current run of the container,
+// identified by its start time, if the target can select the logs since a time.
+type currentRunTarget struct {
+	StrategyTarget
+	startedAt string
+}
+
+func (t currentRunTarget) Logs(ctx context.Context) (io.ReadCloser, error) {
+	if _, ok := t.StrategyTarget.(logsTextTarget); !ok {
+		return t.StrategyTarget.Logs(ctx)
+	}
+
+	logs, err := t.LogsText(ctx, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return io.NopCloser(strings.NewReader(logs)), nil
+}
+
+func (t currentRunTarget) LogsText(ctx context.Context, opts container.LogsOptions) (string, error) {
+	lt, ok := t.StrategyTarget.(logsTextTarget)
+	if !ok {
+		return "", errUnsupportedLogStream
+	}
+
+	opts.Since = t.startedAt
+	return lt.LogsText(ctx, opts)
+}
+
+func (t currentRunTarget) CopyFileFromContainer(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	copier, ok := t.StrategyTarget.(fileCopierTarget)
+	if !ok {
+		return nil, fmt.Errorf("target %T cannot copy files from the container", t.StrategyTarget)
+	}
+
+	return copier.CopyFileFromContainer(ctx, filePath)
+}
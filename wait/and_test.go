@@ -0,0 +1,148 @@
+package wait
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/require"
+)
+
+// flappingStrategyTarget is a running container that already logged the ready message,
+// but that restarts every time its state is inspected.
+type flappingStrategyTarget struct {
+	NopStrategyTarget
+	restarts *atomic.Int64
+}
+
+func (st flappingStrategyTarget) Logs(_ context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader([]byte("ready\n"))), nil
+}
+
+func (st flappingStrategyTarget) State(_ context.Context) (*types.ContainerState, error) {
+	return &types.ContainerState{
+		Running:   true,
+		Status:    "running",
+		StartedAt: strconv.FormatInt(st.restarts.Add(1), 10),
+	}, nil
+}
+
+// restartedStrategyTarget is a running container that logged the ready message before
+// restarting, in its previous run, returning only the logs since the given time.
+type restartedStrategyTarget struct {
+	NopStrategyTarget
+	readyAgain bool
+}
+
+func (st restartedStrategyTarget) State(_ context.Context) (*types.ContainerState, error) {
+	return &types.ContainerState{Running: true, Status: "running", StartedAt: "2024-01-01T00:00:02Z"}, nil
+}
+
+func (st restartedStrategyTarget) LogsText(_ context.Context, opts container.LogsOptions) (string, error) {
+	logs := []struct{ at, line string }{
+		{at: "2024-01-01T00:00:01Z", line: "ready\n"},
+		{at: "2024-01-01T00:00:02Z", line: "starting\n"},
+	}
+	if st.readyAgain {
+		logs = append(logs, struct{ at, line string }{at: "2024-01-01T00:00:03Z", line: "ready\n"})
+	}
+
+	var text string
+	for _, l := range logs {
+		if opts.Since == "" || l.at >= opts.Since {
+			text += l.line
+		}
+	}
+
+	return text, nil
+}
+
+func TestAndStrategy_WaitUntilReady(t *testing.T) {
+	t.Run("no strategies", func(t *testing.T) {
+		err := And().WaitUntilReady(context.Background(), NopStrategyTarget{})
+		require.Error(t, err)
+	})
+
+	t.Run("all strategies pass", func(t *testing.T) {
+		target := NopStrategyTarget{
+			ReaderCloser:   io.NopCloser(bytes.NewReader([]byte("ready\n"))),
+			ContainerState: types.ContainerState{Running: true, StartedAt: "1"},
+		}
+
+		err := And(ForLog("ready"), ForNop(func(context.Context, StrategyTarget) error {
+			return nil
+		})).WithStartupTimeout(time.Second).WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+	})
+
+	t.Run("one strategy never passes", func(t *testing.T) {
+		target := NopStrategyTarget{
+			ContainerState: types.ContainerState{Running: true, StartedAt: "1"},
+		}
+
+		err := And(ForNop(func(context.Context, StrategyTarget) error {
+			return errors.New("not ready")
+		})).WithStartupTimeout(500*time.Millisecond).WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "not ready")
+	})
+
+	t.Run("container not running", func(t *testing.T) {
+		target := NopStrategyTarget{
+			ContainerState: types.ContainerState{Running: false, Status: "exited"},
+		}
+
+		err := And(ForNop(func(context.Context, StrategyTarget) error {
+			return nil
+		})).WithStartupTimeout(500*time.Millisecond).WaitUntilReady(context.Background(), target)
+		require.ErrorContains(t, err, "container is not running: exited")
+	})
+
+	t.Run("log appears but container restarts", func(t *testing.T) {
+		// ForAll accepts the log message once, even if the container restarts afterwards
+		err := ForAll(ForLog("ready")).WithDeadline(time.Second).
+			WaitUntilReady(context.Background(), flappingStrategyTarget{restarts: &atomic.Int64{}})
+		require.NoError(t, err)
+
+		err = And(ForLog("ready")).WithStartupTimeout(time.Second).
+			WaitUntilReady(context.Background(), flappingStrategyTarget{restarts: &atomic.Int64{}})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "container restarted while waiting")
+	})
+
+	t.Run("log of a previous run", func(t *testing.T) {
+		err := And(ForLog("ready")).WithStartupTimeout(500*time.Millisecond).
+			WaitUntilReady(context.Background(), restartedStrategyTarget{})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		err = And(ForLog("ready")).WithStartupTimeout(500*time.Millisecond).
+			WaitUntilReady(context.Background(), restartedStrategyTarget{readyAgain: true})
+		require.NoError(t, err)
+	})
+
+	t.Run("container not stable for long enough", func(t *testing.T) {
+		target := NopStrategyTarget{
+			ReaderCloser:   io.NopCloser(bytes.NewReader([]byte("ready\n"))),
+			ContainerState: types.ContainerState{Running: true, StartedAt: "1"},
+		}
+
+		err := And(ForNop(func(context.Context, StrategyTarget) error {
+			return nil
+		})).WithStableFor(time.Minute).WithStartupTimeout(500*time.Millisecond).WaitUntilReady(context.Background(), target)
+		require.ErrorContains(t, err, "container not running for 1m0s yet")
+
+		start := time.Now()
+		err = And(ForNop(func(context.Context, StrategyTarget) error {
+			return nil
+		})).WithStableFor(300*time.Millisecond).WithStartupTimeout(5*time.Second).WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 300*time.Millisecond)
+	})
+}
@@ -92,6 +92,9 @@ func (ws *ExecStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 		case <-time.After(ws.PollInterval):
 			exitCode, resp, err := target.Exec(ctx, ws.cmd, tcexec.Multiplexed())
 			if err != nil {
+				if exitErr := exitedError(ctx, target); exitErr != nil {
+					return exitErr
+				}
 				return err
 			}
 			if !ws.ExitCodeMatcher(exitCode) {
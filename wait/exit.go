@@ -2,6 +2,8 @@ package wait
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"time"
 )
@@ -19,6 +21,9 @@ type ExitStrategy struct {
 
 	// additional properties
 	PollInterval time.Duration
+
+	// exitCode is the expected exit code of the container, only validated if set
+	exitCode *int
 }
 
 // NewExitStrategy constructs with polling interval of 100 milliseconds without timeout by default
@@ -44,13 +49,22 @@ func (ws *ExitStrategy) WithPollInterval(pollInterval time.Duration) *ExitStrate
 	return ws
 }
 
+// WithExitCode can be used to validate the exit code of the container once it has exited,
+// failing if it differs from the given one. Use WithExitCode(0) to require a successful exit.
+// If not set, the exit code is not validated.
+func (ws *ExitStrategy) WithExitCode(exitCode int) *ExitStrategy {
+	ws.exitCode = &exitCode
+	return ws
+}
+
 // ForExit is the default construction for the fluid interface.
 //
 // For Example:
 //
 //	wait.
 //		ForExit().
-//		WithPollInterval(1 * time.Second)
+//		WithPollInterval(1 * time.Second).
+//		WithExitCode(2)
 func ForExit() *ExitStrategy {
 	return NewExitStrategy()
 }
@@ -76,6 +90,8 @@ func (ws *ExitStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 			if err != nil {
 				if !strings.Contains(err.Error(), "No such container") {
 					return err
+				} else if ws.exitCode != nil {
+					return errors.New("container was removed, the exit code cannot be validated")
 				} else {
 					return nil
 				}
@@ -84,6 +100,9 @@ func (ws *ExitStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 				time.Sleep(ws.PollInterval)
 				continue
 			}
+			if ws.exitCode != nil && state.ExitCode != *ws.exitCode {
+				return fmt.Errorf("container exited with code %d, expected %d", state.ExitCode, *ws.exitCode)
+			}
 			return nil
 		}
 	}
@@ -14,6 +14,7 @@ import (
 
 type exitStrategyTarget struct {
 	isRunning bool
+	exitCode  int
 }
 
 func (st exitStrategyTarget) Host(ctx context.Context) (string, error) {
@@ -42,7 +43,7 @@ func (st exitStrategyTarget) Exec(ctx context.Context, cmd []string, options ...
 }
 
 func (st exitStrategyTarget) State(ctx context.Context) (*types.ContainerState, error) {
-	return &types.ContainerState{Running: st.isRunning}, nil
+	return &types.ContainerState{Running: st.isRunning, ExitCode: st.exitCode}, nil
 }
 
 func TestWaitForExit(t *testing.T) {
@@ -55,3 +56,35 @@ func TestWaitForExit(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestWaitForExit_WithExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		exitCode int
+		expected int
+		wantErr  bool
+	}{
+		{name: "success", exitCode: 0, expected: 0},
+		{name: "failure", exitCode: 2, expected: 0, wantErr: true},
+		{name: "expected-non-zero", exitCode: 2, expected: 2},
+		{name: "unexpected-zero", exitCode: 0, expected: 2, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := exitStrategyTarget{
+				isRunning: false,
+				exitCode:  tt.exitCode,
+			}
+
+			wg := ForExit().WithExitCode(tt.expected).WithExitTimeout(100 * time.Millisecond)
+			err := wg.WaitUntilReady(context.Background(), target)
+			if tt.wantErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
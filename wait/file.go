@@ -0,0 +1,126 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*FileContentStrategy)(nil)
+	_ StrategyTimeout = (*FileContentStrategy)(nil)
+)
+
+// fileCopierTarget is implemented by the targets that can copy a file from the container,
+// e.g. the DockerContainer, as required by the FileContentStrategy.
+type fileCopierTarget interface {
+	CopyFileFromContainer(ctx context.Context, filePath string) (io.ReadCloser, error)
+}
+
+// FileContentStrategy will wait until the content of a file in the container
+// satisfies a matcher. The file is copied from the container on each poll,
+// so the matcher is called with its whole content at that point.
+type FileContentStrategy struct {
+	// all Strategies should have a startupTimeout to avoid waiting infinitely
+	timeout *time.Duration
+
+	// additional properties
+	Path         string
+	Matcher      func(content []byte) bool
+	PollInterval time.Duration
+}
+
+// NewFileContentStrategy constructs with polling interval of 100 milliseconds and startup timeout of 60 seconds by default
+func NewFileContentStrategy(path string, matcher func(content []byte) bool) *FileContentStrategy {
+	return &FileContentStrategy{
+		Path:         path,
+		Matcher:      matcher,
+		PollInterval: defaultPollInterval(),
+	}
+}
+
+// WithStartupTimeout can be used to change the default startup timeout
+func (ws *FileContentStrategy) WithStartupTimeout(startupTimeout time.Duration) *FileContentStrategy {
+	ws.timeout = &startupTimeout
+	return ws
+}
+
+// WithPollInterval can be used to override the default polling interval of 100 milliseconds
+func (ws *FileContentStrategy) WithPollInterval(pollInterval time.Duration) *FileContentStrategy {
+	ws.PollInterval = pollInterval
+	return ws
+}
+
+// ForFileContent is the default construction for the fluid interface.
+// The file not existing yet is not an error: it's read again on the next poll.
+// As the file can be read while it's being written, the matcher must reject
+// incomplete content.
+//
+// For Example:
+//
+//	wait.
+//		ForFileContent("/etc/app/config.json", func(content []byte) bool {
+//			return json.Valid(content) && bytes.Contains(content, []byte(`"ready":true`))
+//		}).
+//		WithPollInterval(1 * time.Second)
+func ForFileContent(path string, matcher func(content []byte) bool) *FileContentStrategy {
+	return NewFileContentStrategy(path, matcher)
+}
+
+func (ws *FileContentStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *FileContentStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	timeout := defaultStartupTimeout()
+	if ws.timeout != nil {
+		timeout = *ws.timeout
+	}
+
+	copier, ok := target.(fileCopierTarget)
+	if !ok {
+		return fmt.Errorf("target %T cannot copy files from the container", target)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		if err := checkTarget(ctx, target); err != nil {
+			return err
+		}
+
+		content, err := readFile(ctx, copier, ws.Path)
+		if err == nil && ws.Matcher(content) {
+			return nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w: file %q: %w", ctx.Err(), ws.Path, lastErr)
+			}
+			return fmt.Errorf("%w: content of file %q not matched", ctx.Err(), ws.Path)
+		case <-time.After(ws.PollInterval):
+		}
+	}
+}
+
+// readFile returns the content of the file, copied from the target.
+func readFile(ctx context.Context, target fileCopierTarget, path string) ([]byte, error) {
+	rc, err := target.CopyFileFromContainer(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if rc == nil {
+		return nil, fmt.Errorf("no content returned for file %q", path)
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
@@ -0,0 +1,144 @@
+package wait_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestFileContentStrategyWaitUntilReady(t *testing.T) {
+	// newTarget returns a running container, in which the file is missing on the first copy,
+	// partially written on the second one, and completely written from the third one on.
+	newTarget := func() *wait.MockStrategyTarget {
+		var copies int
+		return &wait.MockStrategyTarget{
+			StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+				return &types.ContainerState{Running: true}, nil
+			},
+			CopyFileImpl: func(_ context.Context, filePath string) (io.ReadCloser, error) {
+				copies++
+				switch copies {
+				case 1:
+					return nil, errors.New("no such file or directory")
+				case 2:
+					return io.NopCloser(bytes.NewReader([]byte(`{"ready":`))), nil
+				default:
+					return io.NopCloser(bytes.NewReader([]byte(`{"ready":true}`))), nil
+				}
+			},
+		}
+	}
+
+	t.Run("matched", func(t *testing.T) {
+		var matched []string
+		err := wait.ForFileContent("/tmp/config.json", func(content []byte) bool {
+			matched = append(matched, string(content))
+			return json.Valid(content)
+		}).
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(time.Second).
+			WaitUntilReady(context.Background(), newTarget())
+		require.NoError(t, err)
+		require.Equal(t, []string{`{"ready":`, `{"ready":true}`}, matched)
+	})
+
+	t.Run("not-matched", func(t *testing.T) {
+		err := wait.ForFileContent("/tmp/config.json", func(content []byte) bool {
+			return false
+		}).
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(200*time.Millisecond).
+			WaitUntilReady(context.Background(), newTarget())
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, `content of file "/tmp/config.json" not matched`)
+	})
+
+	t.Run("missing-file", func(t *testing.T) {
+		target := newTarget()
+		target.CopyFileImpl = func(_ context.Context, _ string) (io.ReadCloser, error) {
+			return nil, errors.New("no such file or directory")
+		}
+
+		err := wait.ForFileContent("/tmp/config.json", func(content []byte) bool {
+			return true
+		}).
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(200*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "no such file or directory")
+	})
+
+	t.Run("exited", func(t *testing.T) {
+		target := newTarget()
+		target.StateImpl = func(_ context.Context) (*types.ContainerState, error) {
+			return &types.ContainerState{Status: "exited", ExitCode: 1}, nil
+		}
+
+		err := wait.ForFileContent("/tmp/config.json", func(content []byte) bool {
+			return true
+		}).WaitUntilReady(context.Background(), target)
+		require.ErrorContains(t, err, "container exited with code 1")
+	})
+
+	t.Run("no-file-copier", func(t *testing.T) {
+		// only the methods of the StrategyTarget interface are promoted
+		target := struct{ wait.StrategyTarget }{newTarget()}
+
+		err := wait.ForFileContent("/tmp/config.json", func(content []byte) bool {
+			return true
+		}).WaitUntilReady(context.Background(), target)
+		require.ErrorContains(t, err, "cannot copy files from the container")
+	})
+
+	t.Run("nop-target", func(t *testing.T) {
+		target := wait.NopStrategyTarget{ContainerState: types.ContainerState{Running: true}}
+
+		err := wait.ForFileContent("/tmp/config.json", func(content []byte) bool {
+			return len(content) == 0
+		}).WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+	})
+}
+
+func TestFileContentStrategyWaitUntilReady_Container(t *testing.T) {
+	ctx := context.Background()
+
+	// the file is written in two steps, the final content being written after a while
+	req := testcontainers.ContainerRequest{
+		Image: "docker.io/alpine:latest",
+		Entrypoint: []string{"sh", "-c", `printf '{"ready":' > /tmp/config.json; ` +
+			`sleep 2; printf 'true}' >> /tmp/config.json; ` +
+			`sleep 300`},
+		WaitingFor: wait.ForFileContent("/tmp/config.json", func(content []byte) bool {
+			return json.Valid(content)
+		}).WithStartupTimeout(30 * time.Second),
+	}
+
+	ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, ctr.Terminate(ctx))
+	})
+
+	rc, err := ctr.CopyFileFromContainer(ctx, "/tmp/config.json")
+	require.NoError(t, err)
+	defer rc.Close()
+
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, `{"ready":true}`, string(content))
+}
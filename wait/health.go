@@ -2,6 +2,8 @@ package wait
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -20,6 +22,9 @@ type HealthStrategy struct {
 
 	// additional properties
 	PollInterval time.Duration
+	// FailureThreshold is the number of consecutive failed health checks of an unhealthy container
+	// after which the strategy fails, instead of waiting for the startup timeout. Zero disables it.
+	FailureThreshold int
 }
 
 // NewHealthStrategy constructs with polling interval of 100 milliseconds and startup timeout of 60 seconds by default
@@ -45,6 +50,16 @@ func (ws *HealthStrategy) WithPollInterval(pollInterval time.Duration) *HealthSt
 	return ws
 }
 
+// WithFailureThreshold makes the strategy fail once the container is unhealthy and its health check
+// failed the given number of consecutive times, e.g. to detect containers whose health check keeps
+// failing without waiting for the startup timeout. The health checks are counted by the Docker engine,
+// which reports an unhealthy container until its health check passes again, no matter how often it's
+// polled, so the count is reset when the container is reported as starting or healthy again.
+func (ws *HealthStrategy) WithFailureThreshold(threshold int) *HealthStrategy {
+	ws.FailureThreshold = threshold
+	return ws
+}
+
 // ForHealthCheck is the default construction for the fluid interface.
 //
 // For Example:
@@ -79,9 +94,15 @@ func (ws *HealthStrategy) WaitUntilReady(ctx context.Context, target StrategyTar
 			if err != nil {
 				return err
 			}
-			if err := checkState(state); err != nil {
+			if err := checkTargetState(ctx, target, state); err != nil {
 				return err
 			}
+
+			if ws.FailureThreshold > 0 && state.Health != nil && state.Health.Status == types.Unhealthy &&
+				state.Health.FailingStreak >= ws.FailureThreshold {
+				return unhealthyError(state.Health)
+			}
+
 			if state.Health == nil || state.Health.Status != types.Healthy {
 				time.Sleep(ws.PollInterval)
 				continue
@@ -90,3 +111,15 @@ func (ws *HealthStrategy) WaitUntilReady(ctx context.Context, target StrategyTar
 		}
 	}
 }
+
+// unhealthyError returns the error for an unhealthy container, including the number of consecutive
+// failed health checks and the output of the last one, if any.
+func unhealthyError(health *types.Health) error {
+	err := fmt.Errorf("container unhealthy after %d consecutive failed health checks", health.FailingStreak)
+	if len(health.Log) == 0 {
+		return err
+	}
+
+	last := health.Log[len(health.Log)-1]
+	return fmt.Errorf("%w, last health check exited with code %d: %s", err, last.ExitCode, strings.TrimSpace(last.Output))
+}
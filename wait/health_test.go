@@ -162,3 +162,75 @@ func TestWaitForHealthFailsDueToUnexpectedContainerStatus(t *testing.T) {
 	require.Error(t, err)
 	require.EqualError(t, err, "unexpected container status \"dead\"")
 }
+
+// healthSequenceTarget returns a running container reporting the given health states, in order,
+// keeping the last one once they are exhausted.
+func healthSequenceTarget(states ...types.Health) *MockStrategyTarget {
+	var polls int
+	return &MockStrategyTarget{
+		StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+			health := states[min(polls, len(states)-1)]
+			polls++
+
+			health.Log = []*types.HealthcheckResult{{ExitCode: 1, Output: "connection refused\n"}}
+			return &types.ContainerState{Running: true, Health: &health}, nil
+		},
+	}
+}
+
+func TestWaitForHealthFailureThreshold(t *testing.T) {
+	t.Run("fails-fast", func(t *testing.T) {
+		target := healthSequenceTarget(
+			types.Health{Status: types.Starting},
+			types.Health{Status: types.Unhealthy, FailingStreak: 1},
+			types.Health{Status: types.Unhealthy, FailingStreak: 1},
+			types.Health{Status: types.Unhealthy, FailingStreak: 2},
+			types.Health{Status: types.Unhealthy, FailingStreak: 3},
+		)
+
+		err := NewHealthStrategy().
+			WithStartupTimeout(10*time.Second).
+			WithPollInterval(10*time.Millisecond).
+			WithFailureThreshold(3).
+			WaitUntilReady(context.Background(), target)
+		require.EqualError(t, err, "container unhealthy after 3 consecutive failed health checks, last health check exited with code 1: connection refused")
+	})
+
+	t.Run("polls-do-not-count", func(t *testing.T) {
+		// the container stays unhealthy after a single failed health check, however often it's polled
+		target := healthSequenceTarget(types.Health{Status: types.Unhealthy, FailingStreak: 1})
+
+		err := NewHealthStrategy().
+			WithStartupTimeout(200*time.Millisecond).
+			WithPollInterval(10*time.Millisecond).
+			WithFailureThreshold(3).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("reset-by-starting", func(t *testing.T) {
+		target := healthSequenceTarget(
+			types.Health{Status: types.Unhealthy, FailingStreak: 2},
+			types.Health{Status: types.Starting},
+			types.Health{Status: types.Unhealthy, FailingStreak: 2},
+			types.Health{Status: types.Healthy},
+		)
+
+		err := NewHealthStrategy().
+			WithStartupTimeout(10*time.Second).
+			WithPollInterval(10*time.Millisecond).
+			WithFailureThreshold(3).
+			WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		target := healthSequenceTarget(types.Health{Status: types.Unhealthy, FailingStreak: 10})
+
+		err := NewHealthStrategy().
+			WithStartupTimeout(200*time.Millisecond).
+			WithPollInterval(10*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -28,6 +28,8 @@ type HostPortStrategy struct {
 	// all WaitStrategies should have a startupTimeout to avoid waiting infinitely
 	timeout      *time.Duration
 	PollInterval time.Duration
+	// InternalProbe checks the port from inside the container only, see WithInternalProbe
+	InternalProbe bool
 }
 
 // NewHostPortStrategy constructs a default host port strategy
@@ -66,6 +68,15 @@ func (hp *HostPortStrategy) WithPollInterval(pollInterval time.Duration) *HostPo
 	return hp
 }
 
+// WithInternalProbe checks the port from inside the container only, executing a shell command
+// which looks for the port in /proc/net/tcp, or tries to connect to it with nc or bash,
+// so services which are not published to the host, e.g. only reachable in a custom network, can be waited for.
+// The container must have a shell.
+func (hp *HostPortStrategy) WithInternalProbe() *HostPortStrategy {
+	hp.InternalProbe = true
+	return hp
+}
+
 func (hp *HostPortStrategy) Timeout() *time.Duration {
 	return hp.timeout
 }
@@ -109,6 +120,13 @@ func (hp *HostPortStrategy) WaitUntilReady(ctx context.Context, target StrategyT
 		return fmt.Errorf("no port to wait for")
 	}
 
+	if hp.InternalProbe {
+		if err := internalCheck(ctx, internalPort, target, waitInterval); err != nil {
+			return fmt.Errorf("check port %s from inside the container: %w", internalPort, err)
+		}
+		return nil
+	}
+
 	var port nat.Port
 	port, err = target.MappedPort(ctx, internalPort)
 	i := 0
@@ -134,7 +152,7 @@ func (hp *HostPortStrategy) WaitUntilReady(ctx context.Context, target StrategyT
 		return err
 	}
 
-	err = internalCheck(ctx, internalPort, target)
+	err = internalCheck(ctx, internalPort, target, waitInterval)
 	if err != nil && errors.Is(errShellNotExecutable, err) {
 		log.Println("Shell not executable in container, only external port check will be performed")
 	} else {
@@ -176,7 +194,7 @@ func externalCheck(ctx context.Context, ipAddress string, port nat.Port, target
 	return nil
 }
 
-func internalCheck(ctx context.Context, internalPort nat.Port, target StrategyTarget) error {
+func internalCheck(ctx context.Context, internalPort nat.Port, target StrategyTarget, waitInterval time.Duration) error {
 	command := buildInternalCheckCommand(internalPort.Int())
 	for {
 		if ctx.Err() != nil {
@@ -195,6 +213,12 @@ func internalCheck(ctx context.Context, internalPort nat.Port, target StrategyTa
 		} else if exitCode == 126 {
 			return errShellNotExecutable
 		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitInterval):
+		}
 	}
 	return nil
 }
@@ -2,6 +2,7 @@ package wait
 
 import (
 	"context"
+	"errors"
 	"io"
 	"net"
 	"strconv"
@@ -544,3 +545,76 @@ func TestHostPortStrategySucceedsGivenShellIsNotInstalled(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestHostPortStrategyWithInternalProbe(t *testing.T) {
+	newTarget := func(exitCode func() int) *MockStrategyTarget {
+		// the port is not published to the host, so it must not be mapped
+		return &MockStrategyTarget{
+			HostImpl: func(_ context.Context) (string, error) {
+				return "localhost", nil
+			},
+			InspectImpl: func(_ context.Context) (*types.ContainerJSON, error) {
+				return &types.ContainerJSON{
+					NetworkSettings: &types.NetworkSettings{
+						NetworkSettingsBase: types.NetworkSettingsBase{
+							Ports: nat.PortMap{"6379/tcp": nil},
+						},
+					},
+				}, nil
+			},
+			MappedPortImpl: func(_ context.Context, port nat.Port) (nat.Port, error) {
+				t.Fatalf("port %s must not be mapped", port)
+				return "", nil
+			},
+			StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+				return &types.ContainerState{
+					Running: true,
+				}, nil
+			},
+			ExecImpl: func(_ context.Context, _ []string, _ ...exec.ProcessOption) (int, io.Reader, error) {
+				return exitCode(), nil, nil
+			},
+		}
+	}
+
+	t.Run("listening", func(t *testing.T) {
+		var execCount int
+		target := newTarget(func() int {
+			execCount++
+			if execCount < 3 {
+				return 1
+			}
+			return 0
+		})
+
+		wg := ForExposedPort().
+			WithInternalProbe().
+			WithStartupTimeout(5 * time.Second).
+			WithPollInterval(10 * time.Millisecond)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+
+		if execCount != 3 {
+			t.Fatalf("expected 3 checks, got %d", execCount)
+		}
+	})
+
+	t.Run("shell-not-installed", func(t *testing.T) {
+		// the port can only be checked from inside the container
+		target := newTarget(func() int {
+			return 126
+		})
+
+		wg := ForListeningPort("6379/tcp").
+			WithInternalProbe().
+			WithStartupTimeout(5 * time.Second).
+			WithPollInterval(10 * time.Millisecond)
+
+		err := wg.WaitUntilReady(context.Background(), target)
+		if !errors.Is(err, errShellNotExecutable) {
+			t.Fatalf("expected %v, got %v", errShellNotExecutable, err)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package wait
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/tls"
@@ -10,11 +11,14 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/docker/go-connections/nat"
+
+	"github.com/testcontainers/testcontainers-go/exec"
 )
 
 // Implement interface
@@ -34,14 +38,19 @@ type HTTPStrategy struct {
 	ResponseMatcher        func(body io.Reader) bool
 	UseTLS                 bool
 	AllowInsecure          bool
-	TLSConfig              *tls.Config // TLS config for HTTPS
-	Method                 string      // http method
-	Body                   io.Reader   // http request body
+	TLSConfig              *tls.Config      // TLS config for HTTPS
+	Method                 string           // http method
+	Body                   io.Reader        // http request body
+	BodyFunc               func() io.Reader // returns the http request body for each request, takes precedence over Body
 	Headers                map[string]string
 	ResponseHeadersMatcher func(headers http.Header) bool
 	PollInterval           time.Duration
 	UserInfo               *url.Userinfo
 	ForceIPv4LocalHost     bool
+	// DialContext is the function used to dial the HTTP server, instead of the mapped port of the container
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// InternalProbe sends the requests from inside the container, see WithInternalProbe
+	InternalProbe bool
 }
 
 // NewHTTPStrategy constructs a HTTP strategy waiting on port 80 and status code 200
@@ -114,6 +123,13 @@ func (ws *HTTPStrategy) WithBody(reqdata io.Reader) *HTTPStrategy {
 	return ws
 }
 
+// WithBodyFunc sets a function returning the body of each request, so it can be different
+// for every attempt, e.g. to include a timestamp. It takes precedence over WithBody.
+func (ws *HTTPStrategy) WithBodyFunc(bodyFunc func() io.Reader) *HTTPStrategy {
+	ws.BodyFunc = bodyFunc
+	return ws
+}
+
 func (ws *HTTPStrategy) WithHeaders(headers map[string]string) *HTTPStrategy {
 	ws.Headers = headers
 	return ws
@@ -142,6 +158,27 @@ func (ws *HTTPStrategy) WithForcedIPv4LocalHost() *HTTPStrategy {
 	return ws
 }
 
+// WithDialContext sets the function used to dial the HTTP server, e.g. to connect to a Unix socket
+// bind-mounted from the container, or to the IP address of the container in a custom network.
+// When set, the host and the mapped port of the container are not resolved: the requests are sent
+// to localhost, using the port set with WithPort if any, and the dial function decides where to connect.
+func (ws *HTTPStrategy) WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) *HTTPStrategy {
+	ws.DialContext = dial
+	return ws
+}
+
+// WithInternalProbe sends the requests from inside the container, executing curl, or wget if curl
+// is not available, so services which are not published to the host, e.g. only reachable in a custom
+// network, can be waited for. The requests are sent to localhost, on the port set with WithPort,
+// or the first TCP port exposed by the container. The TLS config is not used: the certificate
+// of the server is verified with the CAs of the container, unless insecure connections are allowed.
+// As wget does not report the status code nor the headers of the response, only successful responses
+// are matched when it's used, with the status code 200, and only GET and POST requests are supported.
+func (ws *HTTPStrategy) WithInternalProbe() *HTTPStrategy {
+	ws.InternalProbe = true
+	return ws
+}
+
 // ForHTTP is a convenience method similar to Wait.java
 // https://github.com/testcontainers/testcontainers-java/blob/1d85a3834bd937f80aad3a4cec249c027f31aeb4/core/src/main/java/org/testcontainers/containers/wait/strategy/Wait.java
 func ForHTTP(path string) *HTTPStrategy {
@@ -162,10 +199,148 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	ipAddress, err := target.Host(ctx)
+	switch ws.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost,
+		http.MethodPut, http.MethodPatch, http.MethodDelete,
+		http.MethodConnect, http.MethodOptions, http.MethodTrace:
+	default:
+		if ws.Method != "" {
+			return fmt.Errorf("invalid http method %q", ws.Method)
+		}
+		ws.Method = http.MethodGet
+	}
+
+	if ws.InternalProbe {
+		return ws.waitInternally(ctx, target)
+	}
+
+	address, err := ws.address(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	dialContext := ws.DialContext
+	if dialContext == nil {
+		dialContext = (&net.Dialer{
+			Timeout:   time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).DialContext
+	}
+
+	tripper := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       ws.TLSConfig,
+	}
+	if ws.DialContext != nil {
+		// the requests must reach the dial function, and not a proxy
+		tripper.Proxy = nil
+	}
+
+	var proto string
+	if ws.UseTLS {
+		proto = "https"
+		if ws.AllowInsecure {
+			if ws.TLSConfig == nil {
+				tripper.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+			} else {
+				ws.TLSConfig.InsecureSkipVerify = true
+			}
+		}
+	} else {
+		proto = "http"
+	}
+
+	client := http.Client{Transport: tripper, Timeout: time.Second}
+
+	endpoint, err := url.Parse(ws.Path)
 	if err != nil {
 		return err
 	}
+	endpoint.Scheme = proto
+	endpoint.Host = address
+
+	if ws.UserInfo != nil {
+		endpoint.User = ws.UserInfo
+	}
+
+	// cache the body into a byte-slice so that it can be iterated over multiple times
+	var body []byte
+	if ws.Body != nil && ws.BodyFunc == nil {
+		body, err = io.ReadAll(ws.Body)
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(ws.PollInterval):
+			if err := checkTarget(ctx, target); err != nil {
+				return err
+			}
+			var reqBody io.Reader = bytes.NewReader(body)
+			if ws.BodyFunc != nil {
+				reqBody = ws.BodyFunc()
+			}
+			req, err := http.NewRequestWithContext(ctx, ws.Method, endpoint.String(), reqBody)
+			if err != nil {
+				return err
+			}
+
+			for k, v := range ws.Headers {
+				req.Header.Set(k, v)
+			}
+
+			resp, err := client.Do(req)
+			if err != nil {
+				continue
+			}
+			matched := ws.matches(resp)
+			if err := resp.Body.Close(); err != nil || !matched {
+				continue
+			}
+			return nil
+		}
+	}
+}
+
+// matches returns true if the response matches the status code, body and headers matchers.
+func (ws *HTTPStrategy) matches(resp *http.Response) bool {
+	if ws.StatusCodeMatcher != nil && !ws.StatusCodeMatcher(resp.StatusCode) {
+		return false
+	}
+	if ws.ResponseMatcher != nil && !ws.ResponseMatcher(resp.Body) {
+		return false
+	}
+	if ws.ResponseHeadersMatcher != nil && !ws.ResponseHeadersMatcher(resp.Header) {
+		return false
+	}
+	return true
+}
+
+// address returns the host:port address the requests are sent to, which is the host and the mapped
+// port of the container, or localhost and the port of the strategy, if any, when using a custom dial.
+func (ws *HTTPStrategy) address(ctx context.Context, target StrategyTarget) (string, error) {
+	if ws.DialContext != nil {
+		if ws.Port == "" {
+			return "localhost", nil
+		}
+		return net.JoinHostPort("localhost", ws.Port.Port()), nil
+	}
+
+	ipAddress, err := target.Host(ctx)
+	if err != nil {
+		return "", err
+	}
 	// to avoid ipv6 docker bugs https://github.com/moby/moby/issues/42442 https://github.com/moby/moby/issues/42375
 	if ws.ForceIPv4LocalHost {
 		ipAddress = strings.Replace(ipAddress, "localhost", "127.0.0.1", 1)
@@ -179,15 +354,15 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 		for err != nil || ports == nil {
 			select {
 			case <-ctx.Done():
-				return fmt.Errorf("%w: %w", ctx.Err(), err)
+				return "", fmt.Errorf("%w: %w", ctx.Err(), err)
 			case <-time.After(ws.PollInterval):
 				if err := checkTarget(ctx, target); err != nil {
-					return err
+					return "", err
 				}
 
 				inspect, err := target.Inspect(ctx)
 				if err != nil {
-					return err
+					return "", err
 				}
 
 				ports = inspect.NetworkSettings.Ports
@@ -203,7 +378,7 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 		}
 
 		if mappedPort == "" {
-			return errors.New("No exposed tcp ports or mapped ports - cannot wait for status")
+			return "", errors.New("No exposed tcp ports or mapped ports - cannot wait for status")
 		}
 	} else {
 		mappedPort, err = target.MappedPort(ctx, ws.Port)
@@ -211,10 +386,10 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 		for mappedPort == "" {
 			select {
 			case <-ctx.Done():
-				return fmt.Errorf("%w: %w", ctx.Err(), err)
+				return "", fmt.Errorf("%w: %w", ctx.Err(), err)
 			case <-time.After(ws.PollInterval):
 				if err := checkTarget(ctx, target); err != nil {
-					return err
+					return "", err
 				}
 
 				mappedPort, err = target.MappedPort(ctx, ws.Port)
@@ -222,67 +397,35 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 		}
 
 		if mappedPort.Proto() != "tcp" {
-			return errors.New("Cannot use HTTP client on non-TCP ports")
+			return "", errors.New("Cannot use HTTP client on non-TCP ports")
 		}
 	}
 
-	switch ws.Method {
-	case http.MethodGet, http.MethodHead, http.MethodPost,
-		http.MethodPut, http.MethodPatch, http.MethodDelete,
-		http.MethodConnect, http.MethodOptions, http.MethodTrace:
-	default:
-		if ws.Method != "" {
-			return fmt.Errorf("invalid http method %q", ws.Method)
-		}
-		ws.Method = http.MethodGet
-	}
+	return net.JoinHostPort(ipAddress, strconv.Itoa(mappedPort.Int())), nil
+}
 
-	tripper := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		DialContext: (&net.Dialer{
-			Timeout:   time.Second,
-			KeepAlive: 30 * time.Second,
-			DualStack: true,
-		}).DialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-		TLSClientConfig:       ws.TLSConfig,
-	}
+// errNoHTTPClient is returned when neither curl nor wget can be executed in the container.
+var errNoHTTPClient = errors.New("neither curl nor wget can be executed in the container")
 
-	var proto string
-	if ws.UseTLS {
-		proto = "https"
-		if ws.AllowInsecure {
-			if ws.TLSConfig == nil {
-				tripper.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
-			} else {
-				ws.TLSConfig.InsecureSkipVerify = true
-			}
-		}
-	} else {
-		proto = "http"
+// waitInternally sends the requests from inside the container until the response matches.
+func (ws *HTTPStrategy) waitInternally(ctx context.Context, target StrategyTarget) error {
+	port, err := ws.internalPort(ctx, target)
+	if err != nil {
+		return err
 	}
 
-	client := http.Client{Transport: tripper, Timeout: time.Second}
-	address := net.JoinHostPort(ipAddress, strconv.Itoa(mappedPort.Int()))
-
 	endpoint, err := url.Parse(ws.Path)
 	if err != nil {
 		return err
 	}
-	endpoint.Scheme = proto
-	endpoint.Host = address
-
-	if ws.UserInfo != nil {
-		endpoint.User = ws.UserInfo
+	endpoint.Scheme = "http"
+	if ws.UseTLS {
+		endpoint.Scheme = "https"
 	}
+	endpoint.Host = net.JoinHostPort("localhost", port.Port())
 
-	// cache the body into a byte-slice so that it can be iterated over multiple times
 	var body []byte
-	if ws.Body != nil {
+	if ws.Body != nil && ws.BodyFunc == nil {
 		body, err = io.ReadAll(ws.Body)
 		if err != nil {
 			return err
@@ -297,35 +440,166 @@ func (ws *HTTPStrategy) WaitUntilReady(ctx context.Context, target StrategyTarge
 			if err := checkTarget(ctx, target); err != nil {
 				return err
 			}
-			req, err := http.NewRequestWithContext(ctx, ws.Method, endpoint.String(), bytes.NewReader(body))
-			if err != nil {
-				return err
-			}
 
-			for k, v := range ws.Headers {
-				req.Header.Set(k, v)
+			reqBody := body
+			if ws.BodyFunc != nil {
+				if reqBody, err = io.ReadAll(ws.BodyFunc()); err != nil {
+					return err
+				}
 			}
 
-			resp, err := client.Do(req)
+			resp, err := ws.execRequest(ctx, target, endpoint, reqBody)
 			if err != nil {
+				if errors.Is(err, errNoHTTPClient) {
+					return err
+				}
 				continue
 			}
-			if ws.StatusCodeMatcher != nil && !ws.StatusCodeMatcher(resp.StatusCode) {
-				_ = resp.Body.Close()
-				continue
-			}
-			if ws.ResponseMatcher != nil && !ws.ResponseMatcher(resp.Body) {
-				_ = resp.Body.Close()
-				continue
+			if ws.matches(resp) {
+				return nil
 			}
-			if ws.ResponseHeadersMatcher != nil && !ws.ResponseHeadersMatcher(resp.Header) {
-				_ = resp.Body.Close()
-				continue
-			}
-			if err := resp.Body.Close(); err != nil {
-				continue
+		}
+	}
+}
+
+// internalPort returns the port of the strategy, or the first TCP port exposed by the container.
+func (ws *HTTPStrategy) internalPort(ctx context.Context, target StrategyTarget) (nat.Port, error) {
+	if ws.Port != "" {
+		if ws.Port.Proto() != "tcp" {
+			return "", errors.New("Cannot use HTTP client on non-TCP ports")
+		}
+		return ws.Port, nil
+	}
+
+	inspect, err := target.Inspect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var ports []nat.Port
+	if inspect.Config != nil {
+		for p := range inspect.Config.ExposedPorts {
+			if p.Proto() == "tcp" {
+				ports = append(ports, p)
 			}
-			return nil
 		}
 	}
+	if len(ports) == 0 {
+		return "", errors.New("No exposed tcp ports - cannot wait for status")
+	}
+
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Int() < ports[j].Int()
+	})
+
+	return ports[0], nil
+}
+
+// execRequest sends the request with curl from inside the container, falling back to wget
+// if curl is not available. An error is returned if the request fails.
+func (ws *HTTPStrategy) execRequest(ctx context.Context, target StrategyTarget, endpoint *url.URL, body []byte) (*http.Response, error) {
+	exitCode, reader, err := target.Exec(ctx, ws.curlCommand(endpoint, body), exec.Multiplexed())
+	if err != nil {
+		return nil, err
+	}
+
+	switch exitCode {
+	case 0:
+		// curl includes the status line and the headers of the response in its output
+		return http.ReadResponse(bufio.NewReader(reader), nil)
+	case 126, 127:
+		// curl is not available
+	default:
+		return nil, fmt.Errorf("curl exited with code %d", exitCode)
+	}
+
+	cmd, err := ws.wgetCommand(endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	exitCode, reader, err = target.Exec(ctx, cmd, exec.Multiplexed())
+	if err != nil {
+		return nil, err
+	}
+
+	switch exitCode {
+	case 0:
+		// wget only succeeds for successful responses, and outputs their body only
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(reader),
+		}, nil
+	case 126, 127:
+		return nil, errNoHTTPClient
+	default:
+		return nil, fmt.Errorf("wget exited with code %d", exitCode)
+	}
+}
+
+// curlCommand returns the curl command sending the request to the endpoint.
+func (ws *HTTPStrategy) curlCommand(endpoint *url.URL, body []byte) []string {
+	// HTTP/1.1 is forced, as the status line of HTTP/2 cannot be parsed by http.ReadResponse
+	cmd := []string{"curl", "--silent", "--include", "--http1.1", "--max-time", "1"}
+
+	if ws.Method == http.MethodHead {
+		// curl waits for a body if the method is set with --request
+		cmd = append(cmd, "--head")
+	} else {
+		cmd = append(cmd, "--request", ws.Method)
+	}
+
+	if ws.UseTLS && ws.AllowInsecure {
+		cmd = append(cmd, "--insecure")
+	}
+
+	if ws.UserInfo != nil {
+		password, _ := ws.UserInfo.Password()
+		cmd = append(cmd, "--user", ws.UserInfo.Username()+":"+password)
+	}
+
+	for k, v := range ws.Headers {
+		cmd = append(cmd, "--header", k+": "+v)
+	}
+
+	if body != nil {
+		// no interim "100 Continue" response must be included in the output
+		cmd = append(cmd, "--header", "Expect:", "--data-raw", string(body))
+	}
+
+	return append(cmd, endpoint.String())
+}
+
+// wgetCommand returns the wget command sending the request to the endpoint,
+// which only supports GET and POST requests.
+func (ws *HTTPStrategy) wgetCommand(endpoint *url.URL, body []byte) ([]string, error) {
+	cmd := []string{"wget", "-q", "-O", "-", "-T", "1"}
+
+	switch ws.Method {
+	case http.MethodGet:
+		if body != nil {
+			return nil, errors.New("a GET request with a body requires curl in the container")
+		}
+	case http.MethodPost:
+		cmd = append(cmd, "--post-data", string(body))
+	default:
+		return nil, fmt.Errorf("a %s request requires curl in the container", ws.Method)
+	}
+
+	if ws.UseTLS && ws.AllowInsecure {
+		cmd = append(cmd, "--no-check-certificate")
+	}
+
+	for k, v := range ws.Headers {
+		cmd = append(cmd, "--header", k+": "+v)
+	}
+
+	if ws.UserInfo != nil {
+		withUser := *endpoint
+		withUser.User = ws.UserInfo
+		endpoint = &withUser
+	}
+
+	return append(cmd, endpoint.String()), nil
 }
@@ -10,15 +10,19 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 	"github.com/docker/go-connections/nat"
 
 	"github.com/testcontainers/testcontainers-go"
+	tcexec "github.com/testcontainers/testcontainers-go/exec"
 	"github.com/testcontainers/testcontainers-go/wait"
 )
 
@@ -927,3 +931,285 @@ func TestHttpStrategyFailsWhileGettingPortDueToExposedPortNoBindings(t *testing.
 		}
 	}
 }
+
+func TestHTTPStrategyWithDialContext(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ping" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("pong"))
+	})
+
+	// the container is running but has no mapped ports, so the requests can only reach the servers through the dial function
+	target := &wait.MockStrategyTarget{
+		StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+			return &types.ContainerState{Running: true}, nil
+		},
+	}
+
+	t.Run("custom-dialer", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		var dialedAddr string
+		wg := wait.ForHTTP("/ping").
+			WithPort("8080/tcp").
+			WithResponseMatcher(func(body io.Reader) bool {
+				b, err := io.ReadAll(body)
+				return err == nil && string(b) == "pong"
+			}).
+			WithDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+				dialedAddr = addr
+				var d net.Dialer
+				return d.DialContext(ctx, network, server.Listener.Addr().String())
+			}).
+			WithStartupTimeout(5 * time.Second)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+
+		if dialedAddr != "localhost:8080" {
+			t.Fatalf("expected the dial function to receive %q, got %q", "localhost:8080", dialedAddr)
+		}
+	})
+
+	t.Run("unix-socket", func(t *testing.T) {
+		socket := filepath.Join(t.TempDir(), "http.sock")
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		server := &http.Server{Handler: handler}
+		go func() { _ = server.Serve(listener) }()
+		defer server.Close()
+
+		wg := wait.ForHTTP("/ping").
+			WithDialContext(func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			}).
+			WithStartupTimeout(5 * time.Second)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestHTTPStrategyWithMethodAndBody(t *testing.T) {
+	// the server is only ready for a POST with the expected body
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil || r.Method != http.MethodPost || string(body) != `{"probe":"ready"}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	target := &wait.MockStrategyTarget{
+		StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+			return &types.ContainerState{Running: true}, nil
+		},
+	}
+
+	dial := func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	responseMatcher := func(body io.Reader) bool {
+		b, err := io.ReadAll(body)
+		return err == nil && string(b) == `{"status":"ok"}`
+	}
+
+	t.Run("post-with-body", func(t *testing.T) {
+		wg := wait.ForHTTP("/rpc/readiness").
+			WithMethod(http.MethodPost).
+			WithBody(bytes.NewBufferString(`{"probe":"ready"}`)).
+			WithResponseMatcher(responseMatcher).
+			WithDialContext(dial).
+			WithStartupTimeout(5 * time.Second)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("post-with-body-func", func(t *testing.T) {
+		// the first attempts send a wrong body, so the body must be created again for each request
+		var attempts int
+		wg := wait.ForHTTP("/rpc/readiness").
+			WithMethod(http.MethodPost).
+			WithBodyFunc(func() io.Reader {
+				attempts++
+				if attempts < 3 {
+					return bytes.NewBufferString(`{"probe":"starting"}`)
+				}
+				return bytes.NewBufferString(`{"probe":"ready"}`)
+			}).
+			WithResponseMatcher(responseMatcher).
+			WithDialContext(dial).
+			WithStartupTimeout(5 * time.Second)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+
+		if attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("get-never-ready", func(t *testing.T) {
+		wg := wait.ForHTTP("/rpc/readiness").
+			WithBody(bytes.NewBufferString(`{"probe":"ready"}`)).
+			WithResponseMatcher(responseMatcher).
+			WithDialContext(dial).
+			WithStartupTimeout(500 * time.Millisecond)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err == nil {
+			t.Fatal("expected an error, as the server only accepts POST requests")
+		}
+	})
+}
+
+func TestHTTPStrategyWithInternalProbe(t *testing.T) {
+	// the service is not published to the host, so the target has no mapped ports
+	newTarget := func(execFn func(cmd []string) (int, io.Reader, error)) *wait.MockStrategyTarget {
+		return &wait.MockStrategyTarget{
+			StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+				return &types.ContainerState{Running: true}, nil
+			},
+			InspectImpl: func(_ context.Context) (*types.ContainerJSON, error) {
+				return &types.ContainerJSON{
+					Config: &container.Config{
+						ExposedPorts: nat.PortSet{"8080/tcp": {}, "53/udp": {}},
+					},
+				}, nil
+			},
+			ExecImpl: func(_ context.Context, cmd []string, _ ...tcexec.ProcessOption) (int, io.Reader, error) {
+				return execFn(cmd)
+			},
+		}
+	}
+
+	responseMatcher := func(body io.Reader) bool {
+		b, err := io.ReadAll(body)
+		return err == nil && string(b) == "pong"
+	}
+
+	t.Run("curl", func(t *testing.T) {
+		var attempts int
+		target := newTarget(func(cmd []string) (int, io.Reader, error) {
+			if cmd[0] != "curl" {
+				t.Fatalf("expected curl to be executed, got %q", cmd)
+			}
+			if url := cmd[len(cmd)-1]; url != "http://localhost:8080/ping" {
+				t.Fatalf("expected the request to be sent to the exposed port, got %q", url)
+			}
+
+			attempts++
+			switch attempts {
+			case 1:
+				// connection refused
+				return 7, strings.NewReader(""), nil
+			case 2:
+				return 0, strings.NewReader("HTTP/1.1 503 Service Unavailable\r\nContent-Length: 0\r\n\r\n"), nil
+			default:
+				return 0, strings.NewReader("HTTP/1.1 200 OK\r\nContent-Length: 4\r\n\r\npong"), nil
+			}
+		})
+
+		wg := wait.ForHTTP("/ping").
+			WithResponseMatcher(responseMatcher).
+			WithInternalProbe().
+			WithStartupTimeout(5 * time.Second)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+
+		if attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("wget", func(t *testing.T) {
+		var wgetCmd []string
+		target := newTarget(func(cmd []string) (int, io.Reader, error) {
+			if cmd[0] == "curl" {
+				// executable not found
+				return 127, strings.NewReader(""), nil
+			}
+			wgetCmd = cmd
+			return 0, strings.NewReader("pong"), nil
+		})
+
+		wg := wait.ForHTTP("/ping").
+			WithPort("9090/tcp").
+			WithHeaders(map[string]string{"X-Probe": "1"}).
+			WithResponseMatcher(responseMatcher).
+			WithInternalProbe().
+			WithStartupTimeout(5 * time.Second)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"wget", "-q", "-O", "-", "-T", "1", "--header", "X-Probe: 1", "http://localhost:9090/ping"}
+		if fmt.Sprint(wgetCmd) != fmt.Sprint(expected) {
+			t.Fatalf("expected %q, got %q", expected, wgetCmd)
+		}
+	})
+
+	t.Run("no-http-client", func(t *testing.T) {
+		target := newTarget(func(_ []string) (int, io.Reader, error) {
+			return 127, strings.NewReader(""), nil
+		})
+
+		wg := wait.ForHTTP("/ping").
+			WithInternalProbe().
+			WithStartupTimeout(5 * time.Second)
+
+		err := wg.WaitUntilReady(context.Background(), target)
+		if err == nil || !strings.Contains(err.Error(), "neither curl nor wget") {
+			t.Fatalf("expected an error about the missing HTTP client, got %v", err)
+		}
+	})
+
+	t.Run("container", func(t *testing.T) {
+		ctx := context.Background()
+
+		// no port is exposed to the host
+		ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+			ContainerRequest: testcontainers.ContainerRequest{
+				Image:      "nginx:alpine",
+				WaitingFor: wait.ForHTTP("/").WithInternalProbe().WithStartupTimeout(10 * time.Second),
+			},
+			Started: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			if err := ctr.Terminate(ctx); err != nil {
+				t.Fatal(err)
+			}
+		})
+
+		inspect, err := ctr.Inspect(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for port, bindings := range inspect.NetworkSettings.Ports {
+			if len(bindings) > 0 {
+				t.Fatalf("expected port %s not to be published to the host", port)
+			}
+		}
+	})
+}
@@ -2,10 +2,13 @@ package wait
 
 import (
 	"context"
+	"errors"
 	"io"
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/docker/docker/api/types/container"
 )
 
 // Implement interface
@@ -14,6 +17,24 @@ var (
 	_ StrategyTimeout = (*LogStrategy)(nil)
 )
 
+// LogStream identifies the log streams of the container a LogStrategy matches against.
+type LogStream int
+
+const (
+	// LogStreamBoth matches against the combined stdout and stderr streams, which is the default.
+	LogStreamBoth LogStream = iota
+	// LogStreamStdout matches against the stdout stream only.
+	LogStreamStdout
+	// LogStreamStderr matches against the stderr stream only.
+	LogStreamStderr
+)
+
+// logsTextTarget is implemented by the targets that can return the logs of the selected streams only,
+// e.g. the DockerContainer, which separates them using the framing of the Docker log stream.
+type logsTextTarget interface {
+	LogsText(ctx context.Context, opts container.LogsOptions) (string, error)
+}
+
 // LogStrategy will wait until a given log entry shows up in the docker logs
 type LogStrategy struct {
 	// all Strategies should have a startupTimeout to avoid waiting infinitely
@@ -24,6 +45,8 @@ type LogStrategy struct {
 	IsRegexp     bool
 	Occurrence   int
 	PollInterval time.Duration
+	// Stream is the log stream to match against, the combined stdout and stderr streams by default.
+	Stream LogStream
 }
 
 // NewLogStrategy constructs with polling interval of 100 milliseconds and startup timeout of 60 seconds by default
@@ -67,6 +90,14 @@ func (ws *LogStrategy) WithOccurrence(o int) *LogStrategy {
 	return ws
 }
 
+// WithStream can be used to match against the stdout or the stderr stream only,
+// instead of the combined streams, e.g. for services printing their readiness
+// message to stderr while stdout is noisy.
+func (ws *LogStrategy) WithStream(stream LogStream) *LogStrategy {
+	ws.Stream = stream
+	return ws
+}
+
 // ForLog is the default construction for the fluid interface.
 //
 // For Example:
@@ -102,13 +133,10 @@ LOOP:
 		default:
 			checkErr := checkTarget(ctx, target)
 
-			reader, err := target.Logs(ctx)
-			if err != nil {
-				time.Sleep(ws.PollInterval)
-				continue
+			b, err := ws.logs(ctx, target)
+			if errors.Is(err, errUnsupportedLogStream) {
+				return err
 			}
-
-			b, err := io.ReadAll(reader)
 			if err != nil {
 				time.Sleep(ws.PollInterval)
 				continue
@@ -132,6 +160,37 @@ LOOP:
 	return nil
 }
 
+// errUnsupportedLogStream is returned when the strategy selects a single log stream,
+// but the target can't return the logs of a single stream.
+var errUnsupportedLogStream = errors.New("the target does not support selecting the log stream")
+
+// logs returns the logs of the selected stream of the target.
+func (ws *LogStrategy) logs(ctx context.Context, target StrategyTarget) ([]byte, error) {
+	if ws.Stream == LogStreamBoth {
+		reader, err := target.Logs(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return io.ReadAll(reader)
+	}
+
+	t, ok := target.(logsTextTarget)
+	if !ok {
+		return nil, errUnsupportedLogStream
+	}
+
+	logs, err := t.LogsText(ctx, container.LogsOptions{
+		ShowStdout: ws.Stream == LogStreamStdout,
+		ShowStderr: ws.Stream == LogStreamStderr,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(logs), nil
+}
+
 func checkLogsFn(ws *LogStrategy, b []byte) bool {
 	if ws.IsRegexp {
 		re := regexp.MustCompile(ws.Log)
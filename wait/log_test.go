@@ -3,11 +3,13 @@ package wait
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"testing"
 	"time"
 
 	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
 )
 
 const loremIpsum = `Lorem ipsum dolor sit amet,
@@ -276,3 +278,83 @@ func TestWaitForLogFailsDueToUnexpectedContainerStatus(t *testing.T) {
 		}
 	})
 }
+
+// streamsStrategyTarget is a running container writing different logs to stdout and stderr.
+type streamsStrategyTarget struct {
+	NopStrategyTarget
+	stdout string
+	stderr string
+}
+
+func (st streamsStrategyTarget) Logs(_ context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader([]byte(st.stdout + st.stderr))), nil
+}
+
+func (st streamsStrategyTarget) LogsText(_ context.Context, opts container.LogsOptions) (string, error) {
+	var logs string
+	if opts.ShowStdout {
+		logs += st.stdout
+	}
+	if opts.ShowStderr {
+		logs += st.stderr
+	}
+	return logs, nil
+}
+
+func TestWaitForLogWithStream(t *testing.T) {
+	target := streamsStrategyTarget{
+		NopStrategyTarget: NopStrategyTarget{
+			ContainerState: types.ContainerState{Running: true},
+		},
+		stdout: "ready\nnoise\n",
+		stderr: "ready\nwarning\n",
+	}
+
+	tests := []struct {
+		name    string
+		stream  LogStream
+		log     string
+		wantErr bool
+	}{
+		{name: "both", stream: LogStreamBoth, log: "warning"},
+		{name: "stdout", stream: LogStreamStdout, log: "noise"},
+		{name: "stdout-without-stderr", stream: LogStreamStdout, log: "warning", wantErr: true},
+		{name: "stderr", stream: LogStreamStderr, log: "warning"},
+		{name: "stderr-without-stdout", stream: LogStreamStderr, log: "noise", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ForLog(tt.log).
+				WithStream(tt.stream).
+				WithStartupTimeout(200*time.Millisecond).
+				WaitUntilReady(context.Background(), target)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error: %t, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("occurrences-in-stream", func(t *testing.T) {
+		// "ready" is printed twice in total, but only once to stderr
+		err := ForLog("ready").
+			WithStream(LogStreamStderr).
+			WithOccurrence(2).
+			WithStartupTimeout(200*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		if err == nil {
+			t.Fatal("expected an error, as the log only appears once in stderr")
+		}
+	})
+
+	t.Run("unsupported-target", func(t *testing.T) {
+		err := ForLog("ready").
+			WithStream(LogStreamStderr).
+			WaitUntilReady(context.Background(), NopStrategyTarget{
+				ReaderCloser: io.NopCloser(bytes.NewReader([]byte("ready"))),
+			})
+		if !errors.Is(err, errUnsupportedLogStream) {
+			t.Fatalf("expected %v, got: %v", errUnsupportedLogStream, err)
+		}
+	})
+}
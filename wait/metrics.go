@@ -0,0 +1,43 @@
+package wait
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+)
+
+// ForMetrics is a helper returning an HTTP strategy which scrapes the Prometheus-style metrics
+// endpoint of the container, at the given port and path, e.g. "/metrics", until the named metric
+// is present, with or without labels, e.g. "http_requests_total" matches both `http_requests_total 3`
+// and `http_requests_total{code="200"} 3`. The HELP and TYPE comments of a metric are not enough
+// for it to be present. The returned strategy can be configured further, e.g. with WithStartupTimeout.
+func ForMetrics(port nat.Port, path string, metricName string) *HTTPStrategy {
+	return ForHTTP(path).
+		WithPort(port).
+		WithResponseMatcher(func(body io.Reader) bool {
+			return hasMetric(body, metricName)
+		})
+}
+
+// hasMetric returns true if the body, in the Prometheus text format, has a sample of the named metric.
+func hasMetric(body io.Reader, metricName string) bool {
+	scanner := bufio.NewScanner(body)
+	// the lines of the metrics with many labels can exceed the default limit of 64KB
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(line, metricName)
+		if ok && (strings.HasPrefix(rest, "{") || strings.HasPrefix(rest, " ") || strings.HasPrefix(rest, "\t")) {
+			return true
+		}
+	}
+
+	return false
+}
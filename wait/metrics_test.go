@@ -0,0 +1,81 @@
+package wait_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/go-connections/nat"
+
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestForMetrics(t *testing.T) {
+	// the metric is only exposed after some scrapes, as if the service was still starting
+	var scrapes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprintln(w, "# HELP process_start_time_seconds Start time of the process.")
+		fmt.Fprintln(w, "# TYPE process_start_time_seconds gauge")
+		fmt.Fprintln(w, "process_start_time_seconds 1.7e+09")
+		fmt.Fprintln(w, "# HELP app_ready Whether the app is ready.")
+		fmt.Fprintln(w, "# TYPE app_ready gauge")
+		fmt.Fprintln(w, "app_ready_created 1.7e+09")
+		if scrapes.Add(1) > 3 {
+			fmt.Fprintln(w, `app_ready{instance="a"} 1`)
+		}
+	}))
+	defer server.Close()
+
+	_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := &wait.MockStrategyTarget{
+		HostImpl: func(_ context.Context) (string, error) {
+			return "127.0.0.1", nil
+		},
+		MappedPortImpl: func(_ context.Context, _ nat.Port) (nat.Port, error) {
+			return nat.NewPort("tcp", port)
+		},
+		StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+			return &types.ContainerState{Running: true}, nil
+		},
+	}
+
+	t.Run("present", func(t *testing.T) {
+		wg := wait.ForMetrics("9090/tcp", "/metrics", "app_ready").
+			WithStartupTimeout(5 * time.Second).
+			WithPollInterval(10 * time.Millisecond)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+
+		if n := scrapes.Load(); n < 4 {
+			t.Fatalf("expected at least 4 scrapes, got %d", n)
+		}
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		// the name is only a prefix of the names of the exposed metrics
+		wg := wait.ForMetrics("9090/tcp", "/metrics", "app").
+			WithStartupTimeout(500 * time.Millisecond).
+			WithPollInterval(10 * time.Millisecond)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err == nil {
+			t.Fatal("expected an error, as the metric is not present")
+		}
+	})
+}
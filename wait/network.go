@@ -0,0 +1,105 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*NetworkStrategy)(nil)
+	_ StrategyTimeout = (*NetworkStrategy)(nil)
+)
+
+// NetworkStrategy will wait until the container is attached to a network,
+// with an IP address assigned in it. This is useful when the container is
+// connected to the network asynchronously, once it's already running.
+type NetworkStrategy struct {
+	// all Strategies should have a startupTimeout to avoid waiting infinitely
+	timeout *time.Duration
+
+	// additional properties
+	NetworkName  string
+	PollInterval time.Duration
+}
+
+// NewNetworkStrategy constructs with polling interval of 100 milliseconds and startup timeout of 60 seconds by default
+func NewNetworkStrategy(networkName string) *NetworkStrategy {
+	return &NetworkStrategy{
+		NetworkName:  networkName,
+		PollInterval: defaultPollInterval(),
+	}
+}
+
+// fluent builders for each property
+// since go has neither covariance nor generics, the return type must be the type of the concrete implementation
+// this is true for all properties, even the "shared" ones like startupTimeout
+
+// WithStartupTimeout can be used to change the default startup timeout
+func (ws *NetworkStrategy) WithStartupTimeout(startupTimeout time.Duration) *NetworkStrategy {
+	ws.timeout = &startupTimeout
+	return ws
+}
+
+// WithPollInterval can be used to override the default polling interval of 100 milliseconds
+func (ws *NetworkStrategy) WithPollInterval(pollInterval time.Duration) *NetworkStrategy {
+	ws.PollInterval = pollInterval
+	return ws
+}
+
+// ForNetwork is the default construction for the fluid interface.
+//
+// For Example:
+//
+//	wait.
+//		ForNetwork("my-network").
+//		WithPollInterval(1 * time.Second)
+func ForNetwork(networkName string) *NetworkStrategy {
+	return NewNetworkStrategy(networkName)
+}
+
+func (ws *NetworkStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *NetworkStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	timeout := defaultStartupTimeout()
+	if ws.timeout != nil {
+		timeout = *ws.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		// the state is always fetched from the Docker daemon, refreshing the inspection
+		// of the container, so the networks it's attached to are up to date
+		state, err := target.State(ctx)
+		if err != nil {
+			return err
+		}
+		if err := checkTargetState(ctx, target, state); err != nil {
+			return err
+		}
+
+		inspect, err := target.Inspect(ctx)
+		if err != nil {
+			return err
+		}
+
+		if inspect.NetworkSettings != nil {
+			endpoint, ok := inspect.NetworkSettings.Networks[ws.NetworkName]
+			if ok && endpoint != nil && (endpoint.IPAddress != "" || endpoint.GlobalIPv6Address != "") {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: container not attached to network %q", ctx.Err(), ws.NetworkName)
+		case <-time.After(ws.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkStrategy_WaitUntilReady(t *testing.T) {
+	// newTarget returns a running container, attached to the given networks
+	// from the given number of inspections on.
+	newTarget := func(after int, networks map[string]*network.EndpointSettings) *MockStrategyTarget {
+		var inspections int
+		return &MockStrategyTarget{
+			StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+				return &types.ContainerState{Running: true}, nil
+			},
+			InspectImpl: func(_ context.Context) (*types.ContainerJSON, error) {
+				inspections++
+
+				settings := &types.NetworkSettings{Networks: map[string]*network.EndpointSettings{}}
+				if inspections >= after {
+					settings.Networks = networks
+				}
+
+				return &types.ContainerJSON{NetworkSettings: settings}, nil
+			},
+		}
+	}
+
+	t.Run("attached", func(t *testing.T) {
+		target := newTarget(3, map[string]*network.EndpointSettings{
+			"my-network": {IPAddress: "172.18.0.2"},
+		})
+
+		err := ForNetwork("my-network").
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(time.Second).
+			WaitUntilReady(context.Background(), target)
+		require.NoError(t, err)
+	})
+
+	t.Run("no-ip-assigned", func(t *testing.T) {
+		target := newTarget(1, map[string]*network.EndpointSettings{
+			"my-network": {},
+		})
+
+		err := ForNetwork("my-network").
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(200*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, `container not attached to network "my-network"`)
+	})
+
+	t.Run("other-network", func(t *testing.T) {
+		target := newTarget(1, map[string]*network.EndpointSettings{
+			"bridge": {IPAddress: "172.17.0.2"},
+		})
+
+		err := ForNetwork("my-network").
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(200*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("inspect-error", func(t *testing.T) {
+		target := newTarget(1, nil)
+		target.InspectImpl = func(_ context.Context) (*types.ContainerJSON, error) {
+			return nil, errors.New("inspect failed")
+		}
+
+		err := ForNetwork("my-network").WaitUntilReady(context.Background(), target)
+		require.EqualError(t, err, "inspect failed")
+	})
+}
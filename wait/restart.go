@@ -0,0 +1,109 @@
+package wait
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Implement interface
+var (
+	_ Strategy        = (*RestartCountStrategy)(nil)
+	_ StrategyTimeout = (*RestartCountStrategy)(nil)
+)
+
+// RestartCountStrategy will wait until the container has been restarted a number of times
+// by the Docker engine, because of its restart policy.
+type RestartCountStrategy struct {
+	// all Strategies should have a startupTimeout to avoid waiting infinitely
+	timeout *time.Duration
+
+	// additional properties
+	RestartCount int
+	PollInterval time.Duration
+}
+
+// NewRestartCountStrategy constructs with polling interval of 100 milliseconds and startup timeout of 60 seconds by default
+func NewRestartCountStrategy(n int) *RestartCountStrategy {
+	return &RestartCountStrategy{
+		RestartCount: n,
+		PollInterval: defaultPollInterval(),
+	}
+}
+
+// fluent builders for each property
+// since go has neither covariance nor generics, the return type must be the type of the concrete implementation
+// this is true for all properties, even the "shared" ones like startupTimeout
+
+// WithStartupTimeout can be used to change the default startup timeout
+func (ws *RestartCountStrategy) WithStartupTimeout(startupTimeout time.Duration) *RestartCountStrategy {
+	ws.timeout = &startupTimeout
+	return ws
+}
+
+// WithPollInterval can be used to override the default polling interval of 100 milliseconds
+func (ws *RestartCountStrategy) WithPollInterval(pollInterval time.Duration) *RestartCountStrategy {
+	ws.PollInterval = pollInterval
+	return ws
+}
+
+// ForRestartCount waits until the restart count of the container, as reported by Inspect,
+// reaches n, e.g. for resilience tests of a container crashing a number of times before being
+// stable. The container needs a restart policy, e.g. "on-failure". The strategy fails without
+// waiting for the startup timeout if the container stops restarting before reaching the count.
+//
+// For Example:
+//
+//	wait.
+//		ForRestartCount(3).
+//		WithStartupTimeout(30 * time.Second)
+func ForRestartCount(n int) *RestartCountStrategy {
+	return NewRestartCountStrategy(n)
+}
+
+func (ws *RestartCountStrategy) Timeout() *time.Duration {
+	return ws.timeout
+}
+
+// WaitUntilReady implements Strategy.WaitUntilReady
+func (ws *RestartCountStrategy) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
+	timeout := defaultStartupTimeout()
+	if ws.timeout != nil {
+		timeout = *ws.timeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var restarts int
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: container restarted %d times, expected %d", ctx.Err(), restarts, ws.RestartCount)
+		default:
+			// the state is read first, as Inspect could return a cached representation of the container
+			state, err := target.State(ctx)
+			if err != nil {
+				return err
+			}
+
+			inspect, err := target.Inspect(ctx)
+			if err != nil {
+				return err
+			}
+
+			restarts = inspect.RestartCount
+			if restarts >= ws.RestartCount {
+				return nil
+			}
+
+			// the Docker engine marks the container as restarting as soon as it exits, if it's restarted
+			if isTerminated(state) && !state.Restarting {
+				err := fmt.Errorf("container stopped restarting after %d restarts, expected %d: exit code %d", restarts, ws.RestartCount, state.ExitCode)
+				return withLogsTail(ctx, target, err)
+			}
+
+			time.Sleep(ws.PollInterval)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package wait
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForRestartCount(t *testing.T) {
+	// the container is restarted every poll, until it gives up after the given number of restarts
+	newTarget := func(maxRestarts int32) MockStrategyTarget {
+		var polls atomic.Int32
+		return MockStrategyTarget{
+			StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+				if polls.Add(1) > maxRestarts {
+					return &types.ContainerState{Status: "exited", ExitCode: 1}, nil
+				}
+				return &types.ContainerState{Status: "restarting", Restarting: true}, nil
+			},
+			InspectImpl: func(_ context.Context) (*types.ContainerJSON, error) {
+				return &types.ContainerJSON{
+					ContainerJSONBase: &types.ContainerJSONBase{RestartCount: int(min(polls.Load(), maxRestarts))},
+				}, nil
+			},
+			LogsImpl: func(_ context.Context) (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader("crashed\n")), nil
+			},
+		}
+	}
+
+	t.Run("reached", func(t *testing.T) {
+		err := ForRestartCount(3).
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(time.Second).
+			WaitUntilReady(context.Background(), newTarget(5))
+		require.NoError(t, err)
+	})
+
+	t.Run("stopped-restarting", func(t *testing.T) {
+		err := ForRestartCount(3).
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(time.Second).
+			WaitUntilReady(context.Background(), newTarget(2))
+		require.ErrorContains(t, err, "container stopped restarting after 2 restarts, expected 3: exit code 1")
+		require.ErrorContains(t, err, "crashed")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		target := newTarget(5)
+		target.StateImpl = func(_ context.Context) (*types.ContainerState, error) {
+			return &types.ContainerState{Status: "running", Running: true}, nil
+		}
+		target.InspectImpl = func(_ context.Context) (*types.ContainerJSON, error) {
+			return &types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{RestartCount: 1}}, nil
+		}
+
+		err := ForRestartCount(3).
+			WithPollInterval(10*time.Millisecond).
+			WithStartupTimeout(200*time.Millisecond).
+			WaitUntilReady(context.Background(), target)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "container restarted 1 times, expected 3")
+	})
+}
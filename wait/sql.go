@@ -19,24 +19,26 @@ const defaultForSqlQuery = "SELECT 1"
 // ForSQL constructs a new waitForSql strategy for the given driver
 func ForSQL(port nat.Port, driver string, url func(host string, port nat.Port) string) *waitForSql {
 	return &waitForSql{
-		Port:           port,
-		URL:            url,
-		Driver:         driver,
-		startupTimeout: defaultStartupTimeout(),
-		PollInterval:   defaultPollInterval(),
-		query:          defaultForSqlQuery,
+		Port:             port,
+		URL:              url,
+		Driver:           driver,
+		startupTimeout:   defaultStartupTimeout(),
+		PollInterval:     defaultPollInterval(),
+		query:            defaultForSqlQuery,
+		successThreshold: 1,
 	}
 }
 
 type waitForSql struct {
 	timeout *time.Duration
 
-	URL            func(host string, port nat.Port) string
-	Driver         string
-	Port           nat.Port
-	startupTimeout time.Duration
-	PollInterval   time.Duration
-	query          string
+	URL              func(host string, port nat.Port) string
+	Driver           string
+	Port             nat.Port
+	startupTimeout   time.Duration
+	PollInterval     time.Duration
+	query            string
+	successThreshold int
 }
 
 // WithStartupTimeout can be used to change the default startup timeout
@@ -57,11 +59,20 @@ func (w *waitForSql) WithQuery(query string) *waitForSql {
 	return w
 }
 
+// WithSuccessThreshold can be used to require the query to succeed n consecutive times, one per poll
+// interval, before the database is considered ready, e.g. for databases accepting connections before
+// being fully initialized. A failed query resets the count. It defaults to 1, also for values below 1.
+func (w *waitForSql) WithSuccessThreshold(n int) *waitForSql {
+	w.successThreshold = max(n, 1)
+	return w
+}
+
 func (w *waitForSql) Timeout() *time.Duration {
 	return w.timeout
 }
 
-// WaitUntilReady repeatedly tries to run "SELECT 1" or user defined query on the given port using sql and driver.
+// WaitUntilReady repeatedly tries to run "SELECT 1" or user defined query on the given port using sql and driver,
+// until it succeeds the number of consecutive times set with WithSuccessThreshold.
 //
 // If it doesn't succeed until the timeout value which defaults to 60 seconds, it will return an error.
 func (w *waitForSql) WaitUntilReady(ctx context.Context, target StrategyTarget) error {
@@ -101,18 +112,27 @@ func (w *waitForSql) WaitUntilReady(ctx context.Context, target StrategyTarget)
 		return fmt.Errorf("sql.Open: %w", err)
 	}
 	defer db.Close()
+
+	var successes int
 	for {
 		select {
 		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("%w: %w", ctx.Err(), err)
+			}
 			return ctx.Err()
 		case <-ticker.C:
 			if err := checkTarget(ctx, target); err != nil {
 				return err
 			}
-			if _, err := db.ExecContext(ctx, w.query); err != nil {
+			if _, err = db.ExecContext(ctx, w.query); err != nil {
+				successes = 0
 				continue
 			}
-			return nil
+			successes++
+			if successes >= w.successThreshold {
+				return nil
+			}
 		}
 	}
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,6 +38,7 @@ func Test_waitForSql_WithQuery(t *testing.T) {
 
 func init() {
 	sql.Register("mock", &mockSQLDriver{})
+	sql.Register("mock-flaky", flakyDriver)
 }
 
 type mockSQLDriver struct {
@@ -78,6 +81,126 @@ func (st *mockSQLStmt) ExecContext(_ context.Context, _ []driver.NamedValue) (dr
 	return nil, nil
 }
 
+// flakyDriver is a driver whose queries return the results in order, one per query,
+// succeeding once they are exhausted, counting the connections opened and closed.
+var flakyDriver = &flakySQLDriver{}
+
+type flakySQLDriver struct {
+	driver.Driver
+
+	mu      sync.Mutex
+	results []error
+	queries int
+	opened  int
+	closed  int
+}
+
+func (d *flakySQLDriver) reset(results ...error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.results = results
+	d.queries, d.opened, d.closed = 0, 0, 0
+}
+
+func (d *flakySQLDriver) Open(_ string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.opened++
+	return &flakySQLConn{driver: d}, nil
+}
+
+type flakySQLConn struct {
+	driver.Conn
+	driver *flakySQLDriver
+}
+
+func (c *flakySQLConn) Close() error {
+	c.driver.mu.Lock()
+	defer c.driver.mu.Unlock()
+
+	c.driver.closed++
+	return nil
+}
+
+func (c *flakySQLConn) ExecContext(_ context.Context, _ string, _ []driver.NamedValue) (driver.Result, error) {
+	d := c.driver
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.queries++
+	if len(d.results) == 0 {
+		return driver.RowsAffected(0), nil
+	}
+
+	err := d.results[0]
+	d.results = d.results[1:]
+	return driver.RowsAffected(0), err
+}
+
+func TestWaitForSQLSuccessThreshold(t *testing.T) {
+	target := &MockStrategyTarget{
+		HostImpl: func(_ context.Context) (string, error) {
+			return "localhost", nil
+		},
+		MappedPortImpl: func(_ context.Context, _ nat.Port) (nat.Port, error) {
+			return "49152", nil
+		},
+		StateImpl: func(_ context.Context) (*types.ContainerState, error) {
+			return &types.ContainerState{
+				Running: true,
+			}, nil
+		},
+	}
+
+	errNotReady := errors.New("database is starting up")
+
+	t.Run("consecutive successes", func(t *testing.T) {
+		// the first success is followed by a failure, which resets the count
+		flakyDriver.reset(errNotReady, nil, errNotReady, nil, nil)
+
+		wg := ForSQL("5432", "mock-flaky", func(_ string, _ nat.Port) string { return "" }).
+			WithQuery("SELECT 1 FROM schema_ready").
+			WithSuccessThreshold(3).
+			WithStartupTimeout(5 * time.Second).
+			WithPollInterval(10 * time.Millisecond)
+
+		if err := wg.WaitUntilReady(context.Background(), target); err != nil {
+			t.Fatal(err)
+		}
+
+		if flakyDriver.queries != 6 {
+			t.Fatalf("expected 6 queries, got %d", flakyDriver.queries)
+		}
+		if flakyDriver.opened == 0 || flakyDriver.opened != flakyDriver.closed {
+			t.Fatalf("expected all the connections to be closed, opened %d, closed %d", flakyDriver.opened, flakyDriver.closed)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		failures := make([]error, 1000)
+		for i := range failures {
+			failures[i] = errNotReady
+		}
+		flakyDriver.reset(failures...)
+
+		wg := ForSQL("5432", "mock-flaky", func(_ string, _ nat.Port) string { return "" }).
+			WithSuccessThreshold(2).
+			WithStartupTimeout(200 * time.Millisecond).
+			WithPollInterval(10 * time.Millisecond)
+
+		err := wg.WaitUntilReady(context.Background(), target)
+		if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, errNotReady) {
+			t.Fatalf("expected a deadline exceeded error wrapping the query error, got %v", err)
+		}
+
+		if flakyDriver.opened == 0 || flakyDriver.opened != flakyDriver.closed {
+			t.Fatalf("expected all the connections to be closed, opened %d, closed %d", flakyDriver.opened, flakyDriver.closed)
+		}
+	})
+}
+
 func TestWaitForSQLSucceeds(t *testing.T) {
 	var mappedPortCount int
 	target := &MockStrategyTarget{
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/docker/docker/api/types"
@@ -33,13 +34,69 @@ type StrategyTarget interface {
 	State(context.Context) (*types.ContainerState, error)
 }
 
+// exitLogsTailLines is the number of lines of the container logs added to the error
+// returned when the container stops running while waiting for it.
+const exitLogsTailLines = 10
+
 func checkTarget(ctx context.Context, target StrategyTarget) error {
 	state, err := target.State(ctx)
 	if err != nil {
 		return err
 	}
 
-	return checkState(state)
+	return checkTargetState(ctx, target, state)
+}
+
+// checkTargetState checks the state of the target, adding the tail of its logs
+// to the error if the container is not running anymore.
+func checkTargetState(ctx context.Context, target StrategyTarget, state *types.ContainerState) error {
+	err := checkState(state)
+	if err == nil || !isTerminated(state) {
+		return err
+	}
+
+	return withLogsTail(ctx, target, err)
+}
+
+// isTerminated returns true if the container stopped running and will not run again by itself.
+func isTerminated(state *types.ContainerState) bool {
+	return !state.Running && (state.OOMKilled || state.Status == "exited" || state.Status == "dead")
+}
+
+// exitedError returns an error, including the tail of the logs, if the container
+// stopped running, or nil otherwise, including when its state cannot be retrieved.
+func exitedError(ctx context.Context, target StrategyTarget) error {
+	state, err := target.State(ctx)
+	if err != nil || state == nil || !isTerminated(state) {
+		return nil
+	}
+
+	return checkTargetState(ctx, target, state)
+}
+
+// withLogsTail adds the last lines of the logs of the target to the error,
+// returning the error as is if the logs cannot be retrieved.
+func withLogsTail(ctx context.Context, target StrategyTarget, err error) error {
+	reader, logsErr := target.Logs(ctx)
+	if logsErr != nil || reader == nil {
+		return err
+	}
+	defer reader.Close()
+
+	b, logsErr := io.ReadAll(reader)
+	if logsErr != nil {
+		return err
+	}
+
+	lines := strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return err
+	}
+	if len(lines) > exitLogsTailLines {
+		lines = lines[len(lines)-exitLogsTailLines:]
+	}
+
+	return fmt.Errorf("%w, last container logs:\n%s", err, strings.Join(lines, "\n"))
 }
 
 func checkState(state *types.ContainerState) error {
@@ -62,3 +119,44 @@ func defaultStartupTimeout() time.Duration {
 func defaultPollInterval() time.Duration {
 	return 100 * time.Millisecond
 }
+
+// SetStartupTimeout overrides the startup timeout of the given strategy with the given timeout.
+// For strategies combining other strategies, such as the ones created with ForAll, the timeout
+// is used as their deadline, and it's also set to all the inner strategies.
+// Strategies not defined in this package are not modified.
+func SetStartupTimeout(strategy Strategy, timeout time.Duration) {
+	switch s := strategy.(type) {
+	case *MultiStrategy:
+		s.deadline = &timeout
+		for _, inner := range s.Strategies {
+			SetStartupTimeout(inner, timeout)
+		}
+	case *AndStrategy:
+		s.timeout = &timeout
+		for _, inner := range s.Strategies {
+			SetStartupTimeout(inner, timeout)
+		}
+	case *ExecStrategy:
+		s.timeout = &timeout
+	case *ExitStrategy:
+		s.timeout = &timeout
+	case *FileContentStrategy:
+		s.timeout = &timeout
+	case *HealthStrategy:
+		s.timeout = &timeout
+	case *HostPortStrategy:
+		s.timeout = &timeout
+	case *HTTPStrategy:
+		s.timeout = &timeout
+	case *LogStrategy:
+		s.timeout = &timeout
+	case *NetworkStrategy:
+		s.timeout = &timeout
+	case *NopStrategy:
+		s.timeout = &timeout
+	case *RestartCountStrategy:
+		s.timeout = &timeout
+	case *waitForSql:
+		s.timeout = &timeout
+	}
+}
@@ -3,10 +3,19 @@ package wait
 import (
 	"context"
 	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/require"
 
 	tcexec "github.com/testcontainers/testcontainers-go/exec"
 )
@@ -21,6 +30,7 @@ type MockStrategyTarget struct {
 	LogsImpl       func(context.Context) (io.ReadCloser, error)
 	ExecImpl       func(context.Context, []string, ...tcexec.ProcessOption) (int, io.Reader, error)
 	StateImpl      func(context.Context) (*types.ContainerState, error)
+	CopyFileImpl   func(context.Context, string) (io.ReadCloser, error)
 }
 
 func (st MockStrategyTarget) Host(ctx context.Context) (string, error) {
@@ -46,6 +56,9 @@ func (st MockStrategyTarget) MappedPort(ctx context.Context, port nat.Port) (nat
 }
 
 func (st MockStrategyTarget) Logs(ctx context.Context) (io.ReadCloser, error) {
+	if st.LogsImpl == nil {
+		return nil, errors.New("not implemented")
+	}
 	return st.LogsImpl(ctx)
 }
 
@@ -54,5 +67,94 @@ func (st MockStrategyTarget) Exec(ctx context.Context, cmd []string, options ...
 }
 
 func (st MockStrategyTarget) State(ctx context.Context) (*types.ContainerState, error) {
+	if st.StateImpl == nil {
+		return nil, errors.New("not implemented")
+	}
 	return st.StateImpl(ctx)
 }
+
+func (st MockStrategyTarget) CopyFileFromContainer(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	if st.CopyFileImpl == nil {
+		return nil, errors.New("not implemented")
+	}
+	return st.CopyFileImpl(ctx, filePath)
+}
+
+func TestSetStartupTimeout(t *testing.T) {
+	timeout := 5 * time.Minute
+
+	log := ForLog("ready").WithStartupTimeout(time.Second)
+	port := ForListeningPort("80/tcp")
+	nested := ForAll(ForHTTP("/"), ForExec([]string{"true"}))
+	and := And(ForHealthCheck(), ForNetwork("bridge"))
+	strategy := ForAll(log, port, nested, and).WithDeadline(10 * time.Second)
+
+	SetStartupTimeout(strategy, timeout)
+
+	require.Equal(t, timeout, *strategy.deadline)
+	require.Equal(t, timeout, *nested.deadline)
+	for _, s := range []StrategyTimeout{log, port, nested.Strategies[0].(StrategyTimeout), nested.Strategies[1].(StrategyTimeout), and, and.Strategies[0].(StrategyTimeout), and.Strategies[1].(StrategyTimeout)} {
+		require.NotNil(t, s.Timeout())
+		require.Equal(t, timeout, *s.Timeout())
+	}
+}
+
+func TestSetStartupTimeout_allStrategies(t *testing.T) {
+	timeout := 5 * time.Minute
+
+	strategies := []StrategyTimeout{
+		ForAll(),
+		And(),
+		ForExec([]string{"true"}),
+		ForExit(),
+		ForFileContent("/tmp/file", nil),
+		ForHealthCheck(),
+		ForListeningPort("80/tcp"),
+		ForHTTP("/"),
+		ForLog("ready"),
+		ForNetwork("bridge"),
+		ForNop(nil),
+		ForRestartCount(1),
+		ForSQL("5432/tcp", "postgres", nil),
+	}
+
+	covered := map[string]bool{}
+	for _, s := range strategies {
+		name := reflect.TypeOf(s).Elem().Name()
+		covered[name] = true
+
+		t.Run(name, func(t *testing.T) {
+			SetStartupTimeout(s.(Strategy), timeout)
+
+			got := s.Timeout()
+			if ms, ok := s.(*MultiStrategy); ok {
+				// the timeout is used as the deadline of the strategies combining other ones
+				got = ms.deadline
+			}
+			require.NotNil(t, got)
+			require.Equal(t, timeout, *got)
+		})
+	}
+
+	// every strategy of the package implementing StrategyTimeout must be listed above
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	for _, f := range pkgs["wait"].Files {
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Name.Name != "Timeout" {
+				continue
+			}
+
+			star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+			require.True(t, ok, "Timeout must have a pointer receiver")
+
+			name := star.X.(*ast.Ident).Name
+			require.True(t, covered[name], "%s implements StrategyTimeout but is not tested", name)
+		}
+	}
+}